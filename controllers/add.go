@@ -4,41 +4,59 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+
+	"github.com/danielronalds/gotm/models"
 )
 
 type componentGenerator interface {
-	GenerateController(name string) error
-	GenerateService(name string) error
-	GenerateRepository(name string) error
-	GenerateMigration(name string) error
-	GenerateModel(name string) error
-	GenerateView(name string) error
-	GeneratePage(name string) error
-	GenerateDockerfile() error
+	GenerateController(name string, options models.ComponentOptions) error
+	GenerateService(name string, options models.ComponentOptions) error
+	GenerateRepository(name string, options models.ComponentOptions) error
+	GenerateMiddleware(name string, options models.ComponentOptions) error
+	GenerateMigration(name string, options models.ComponentOptions) error
+	GenerateModel(name string, options models.ComponentOptions) error
+	GenerateView(name string, options models.ComponentOptions) error
+	GeneratePage(name string, options models.ComponentOptions) error
+	GenerateResource(name string, options models.ComponentOptions) error
+	GenerateDockerfile() ([]string, error)
+	GenerateCompose(services []string) ([]string, error)
+	GenerateTest(componentType, name string) error
+}
+
+type TableGenerator interface {
+	GenerateTable(name string, fields []string, generateCode bool) error
 }
 
-type generator = func(name string) error
-type dockerfileGenerator = func() error
+type generator = func(name string, options models.ComponentOptions) error
+type dockerfileGenerator = func() ([]string, error)
+type composeGenerator = func(services []string) ([]string, error)
+type testGenerator = func(componentType, name string) error
 
 type AddController struct {
-	generatorMap    map[string]generator
-	dockerGenerator dockerfileGenerator
+	generatorMap     map[string]generator
+	dockerGenerator  dockerfileGenerator
+	composeGenerator composeGenerator
+	testGenerator    testGenerator
+	tables           TableGenerator
+	validator        NameValidator
 }
 
-func NewAddController(gen componentGenerator) AddController {
+func NewAddController(gen componentGenerator, tables TableGenerator, validator NameValidator) AddController {
 	generatorMap := map[string]generator{
 		"controller": gen.GenerateController,
 		"service":    gen.GenerateService,
 		"repository": gen.GenerateRepository,
+		"middleware": gen.GenerateMiddleware,
 		"migration":  gen.GenerateMigration,
 		"model":      gen.GenerateModel,
 		"view":       gen.GenerateView,
 		"page":       gen.GeneratePage,
+		"resource":   gen.GenerateResource,
 	}
 
 	dockerGenerator := gen.GenerateDockerfile
 
-	return AddController{generatorMap, dockerGenerator}
+	return AddController{generatorMap, dockerGenerator, gen.GenerateCompose, gen.GenerateTest, tables, validator}
 }
 
 func (c AddController) Handle(args []string) error {
@@ -46,33 +64,150 @@ func (c AddController) Handle(args []string) error {
 		return errors.New("passed to incorrect controller! Passed to `add` controller")
 	}
 
-	// Handling special case of the dockerfile, no named part of the component so only 2 args
-	if len(args) == 2 && args[1] == "dockerfile" {
-		if err := c.dockerGenerator(); err != nil {
+	// Dry runs are handled by the filesystem the generators are given
+	parsed, err := parseArgs(args[1:], flagSpec{"dry-run": boolFlag, "preset": valueFlag, "no-register": boolFlag, "route": valueFlag, "with-tests": boolFlag, "uses": valueFlag, "service": valueFlag})
+	if err != nil {
+		return err
+	}
+
+	componentType := strings.ToLower(parsed.arg(0))
+
+	// Handling special case of the dockerfile, no named part of the component
+	if componentType == "dockerfile" && parsed.arg(1) == "" {
+		created, err := c.dockerGenerator()
+		if err != nil {
 			return fmt.Errorf("failed to generate dockerfile: %v", err.Error())
 		}
 
-		fmt.Println("Added Dockerfile")
+		fmt.Printf("Added %v\n", strings.Join(created, " and "))
 		return nil
 	}
 
-	if len(args) < 3 || args[1] == "" || args[2] == "" {
+	// The compose file is also unnamed, taking the services to run alongside the app instead
+	services, hasServices := parsed.value("service")
+	if componentType == "compose" && parsed.arg(1) == "" {
+		created, err := c.composeGenerator(strings.FieldsFunc(services, func(r rune) bool { return r == ',' || r == ' ' }))
+		if err != nil {
+			return fmt.Errorf("failed to generate compose file: %v", err.Error())
+		}
+
+		fmt.Printf("Added %v\n", strings.Join(created, ", "))
+		return nil
+	}
+	if hasServices {
+		return errors.New("--service can only be used with compose")
+	}
+
+	options := models.ComponentOptions{}
+	options.Preset, _ = parsed.value("preset")
+	options.NoRegister = parsed.isSet("no-register")
+	options.Route, _ = parsed.value("route")
+	options.WithTests = parsed.isSet("with-tests")
+	if uses, ok := parsed.value("uses"); ok {
+		options.Uses = strings.FieldsFunc(uses, func(r rune) bool { return r == ',' || r == ' ' })
+	}
+
+	componentName := parsed.arg(1)
+	// Presets are named after themselves unless given a name
+	if componentName == "" && componentType == "middleware" {
+		componentName = strings.ToLower(options.Preset)
+	}
+
+	if componentType == "" || componentName == "" {
 		return errors.New("expected argument [component-type] [component-name]")
 	}
 
-	componentType := strings.ToLower(args[1])
-	componentName := args[2]
+	// Tables are the only component taking more than a name, so they're handled separately
+	if componentType == "table" {
+		return c.addTable(componentName, parsed.positional[2:], parsed.isSet("dry-run"))
+	}
+
+	// Tests are backfilled for existing components, i.e. `add test controller users`
+	if componentType == "test" {
+		return c.addTest(componentName, parsed.arg(2))
+	}
 
 	gen, ok := c.generatorMap[componentType]
 	if !ok {
 		return fmt.Errorf("\"%v\" is not a valid component", componentType)
 	}
 
-	if err := gen(componentName); err != nil {
+	if options.Preset != "" && componentType != "middleware" {
+		return errors.New("--preset can only be used with middleware")
+	}
+	if options.Route != "" && componentType != "page" {
+		return errors.New("--route can only be used with pages")
+	}
+	if options.WithTests && componentType != "controller" && componentType != "service" && componentType != "repository" {
+		return errors.New("--with-tests can only be used with controllers, services and repositories")
+	}
+	if len(options.Uses) != 0 && componentType != "controller" && componentType != "service" {
+		return errors.New("--uses can only be used with controllers and services")
+	}
+
+	// Resources take their fields after the name, i.e. `add resource book title:string pages:int`
+	if componentType == "resource" {
+		options.Fields = parsed.positional[2:]
+	}
+
+	if err := c.validator.ValidateComponentName(componentType, componentName); err != nil {
+		return err
+	}
+
+	// Components that couldn't be registered, or given a test, have still been generated, so only a
+	// warning is given
+	var registrationErr models.RegistrationError
+	var nothingToTestErr models.NothingToTestError
+	if err := gen(componentName, options); err != nil && !errors.As(err, &registrationErr) && !errors.As(err, &nothingToTestErr) {
 		return fmt.Errorf("failed to generate %v component: %v", componentType, err.Error())
 	}
 
 	fmt.Printf("Added \"%v\" %v\n", componentName, componentType)
 
+	if registrationErr.Err != nil {
+		fmt.Printf("Warning: %v\n", registrationErr.Error())
+	}
+	if nothingToTestErr.ComponentType != "" {
+		fmt.Printf("Warning: no test was generated, %v\n", nothingToTestErr.Error())
+	}
+
+	if componentType == "middleware" {
+		fmt.Println("Add it to the middlewares in main.go to wrap every request with it")
+	}
+
+	return nil
+}
+
+func (c AddController) addTest(componentType, name string) error {
+	if name == "" {
+		return errors.New("expected argument test [component-type] [component-name]")
+	}
+
+	componentType = strings.ToLower(componentType)
+	if err := c.validator.ValidateComponentName(componentType, name); err != nil {
+		return err
+	}
+
+	if err := c.testGenerator(componentType, name); err != nil {
+		return fmt.Errorf("failed to generate test: %v", err.Error())
+	}
+
+	fmt.Printf("Added test for \"%v\" %v\n", name, componentType)
+
+	return nil
+}
+
+func (c AddController) addTable(name string, fields []string, dryRun bool) error {
+	if err := c.validator.ValidateComponentName("table", name); err != nil {
+		return err
+	}
+
+	// sqlc can't see the files of a dry run, so code generation is skipped
+	if err := c.tables.GenerateTable(name, fields, !dryRun); err != nil {
+		return fmt.Errorf("failed to generate table component: %v", err.Error())
+	}
+
+	fmt.Printf("Added \"%v\" table\n", name)
+
 	return nil
 }
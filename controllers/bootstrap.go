@@ -0,0 +1,233 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/danielronalds/gotm/models"
+)
+
+type RootSetter interface {
+	SetRoot(dir string) error
+}
+
+type BootstrapFilesystem interface {
+	RootSetter
+	Manifest() (models.Manifest, error)
+}
+
+type UserConfigReader interface {
+	UserConfig() (models.UserConfig, error)
+}
+
+type BuildVerifier interface {
+	VerifyBuild() error
+}
+
+type GitRepository interface {
+	InitRepository() error
+	CommitAll(message string) error
+}
+
+// Flags accepted by commands that create a project, to control the steps run afterwards
+var bootstrapFlags = flagSpec{
+	"bootstrap":  boolFlag,
+	"git":        boolFlag,
+	"no-git":     boolFlag,
+	"install":    boolFlag,
+	"no-install": boolFlag,
+	"build":      boolFlag,
+	"no-build":   boolFlag,
+	"commit":     boolFlag,
+	"no-commit":  boolFlag,
+}
+
+// Runs the optional steps after a project has been created, i.e. initialising git, installing
+// dependencies, building, and committing
+type Bootstrapper struct {
+	filesystem BootstrapFilesystem
+	installer  DependencyInstaller
+	builder    BuildVerifier
+	git        GitRepository
+	config     UserConfigReader
+}
+
+func NewBootstrapper(filesystem BootstrapFilesystem, installer DependencyInstaller, builder BuildVerifier, git GitRepository, config UserConfigReader) Bootstrapper {
+	return Bootstrapper{filesystem, installer, builder, git, config}
+}
+
+// A bootstrap step along with the command to run by hand if it fails
+type bootstrapStep struct {
+	name     string
+	enabled  bool
+	run      func() error
+	recovery string
+	// Steps that must have run and succeeded for this one to run
+	requires []string
+}
+
+// Works out which steps to run from the user config, overridden by any flags passed
+func (b Bootstrapper) options(parsed parsedArgs) (models.BootstrapConfig, error) {
+	config, err := b.config.UserConfig()
+	if err != nil {
+		return models.BootstrapConfig{}, fmt.Errorf("unable to read user config: %v", err)
+	}
+	options := config.Bootstrap
+
+	if parsed.isSet("bootstrap") {
+		options = models.BootstrapConfig{Git: true, Install: true, Build: true, Commit: true}
+	}
+
+	for name, option := range map[string]*bool{"git": &options.Git, "install": &options.Install, "build": &options.Build, "commit": &options.Commit} {
+		if parsed.isSet(name) {
+			*option = true
+		}
+		if parsed.isSet(fmt.Sprintf("no-%v", name)) {
+			*option = false
+		}
+	}
+
+	// Can't commit without a repository
+	if options.Commit && parsed.isSet("no-git") {
+		if parsed.isSet("commit") {
+			return models.BootstrapConfig{}, fmt.Errorf("can't commit without git, remove --no-git or pass --no-commit")
+		}
+		options.Commit = false
+	}
+	if options.Commit {
+		options.Git = true
+	}
+
+	return options, nil
+}
+
+// Runs the enabled bootstrap steps in the project at projectDir, printing a summary of the result
+// of each. Steps that fail don't stop the rest from running, unless they depend on them
+func (b Bootstrapper) Run(projectDir string, parsed parsedArgs) error {
+	options, err := b.options(parsed)
+	if err != nil {
+		return err
+	}
+
+	if !options.Git && !options.Install && !options.Build && !options.Commit {
+		return nil
+	}
+
+	if parsed.isSet("dry-run") {
+		fmt.Println("\nSkipping bootstrap steps on a dry run")
+		return nil
+	}
+
+	if err := b.filesystem.SetRoot(projectDir); err != nil {
+		return err
+	}
+
+	manifest, err := b.filesystem.Manifest()
+	if err != nil {
+		return fmt.Errorf("unable to read project manifest: %v", err)
+	}
+
+	cd := ""
+	if projectDir != "." {
+		cd = fmt.Sprintf("cd %v && ", projectDir)
+	}
+
+	steps := []bootstrapStep{
+		{
+			name:     "git",
+			enabled:  options.Git,
+			run:      b.git.InitRepository,
+			recovery: fmt.Sprintf("%vgit init", cd),
+		},
+		{
+			name:     "install",
+			enabled:  options.Install,
+			run:      b.install,
+			recovery: fmt.Sprintf("%vgotm install", cd),
+		},
+		{
+			name:     "build",
+			enabled:  options.Build,
+			run:      b.builder.VerifyBuild,
+			recovery: fmt.Sprintf("%vgo build %v && gotm npm run build", cd, manifest.MainPackage()),
+			requires: []string{"install"},
+		},
+		{
+			name:     "commit",
+			enabled:  options.Commit,
+			run:      func() error { return b.git.CommitAll("Initial commit") },
+			recovery: fmt.Sprintf("%vgit add -A && git commit -m \"Initial commit\"", cd),
+			requires: []string{"git"},
+		},
+	}
+
+	results := make(map[string]error)
+	summary := make([]string, 0, len(steps))
+	failed := false
+
+	for _, step := range steps {
+		if !step.enabled {
+			continue
+		}
+
+		if blocker, blockerFailed := unmetRequirement(step, results); blocker != "" {
+			reason := fmt.Sprintf("%v was skipped", blocker)
+			if blockerFailed {
+				reason = fmt.Sprintf("%v failed", blocker)
+				results[step.name] = errors.New(reason)
+				failed = true
+			}
+			summary = append(summary, fmt.Sprintf("  skipped  %-8v (%v) run: %v", step.name, reason, step.recovery))
+			continue
+		}
+
+		fmt.Printf("\n==> %v\n", step.name)
+		err := step.run()
+		results[step.name] = err
+
+		if err != nil {
+			summary = append(summary, fmt.Sprintf("  failed   %-8v %v\n           to retry run: %v", step.name, err, step.recovery))
+			failed = true
+			continue
+		}
+		summary = append(summary, fmt.Sprintf("  ok       %v", step.name))
+	}
+
+	fmt.Println("\nBootstrap summary:")
+	for _, line := range summary {
+		fmt.Println(line)
+	}
+
+	if failed {
+		return fmt.Errorf("the project was created, but some bootstrap steps failed")
+	}
+
+	return nil
+}
+
+func (b Bootstrapper) install() error {
+	if err := b.installer.InstallNpmDeps(); err != nil {
+		return fmt.Errorf("unable to install npm deps: %v", err)
+	}
+
+	if err := b.installer.InstallGoDeps(); err != nil {
+		return fmt.Errorf("unable to install go deps: %v", err)
+	}
+
+	return nil
+}
+
+// Returns the name of a required step that failed or was skipped, if any, and whether it failed
+func unmetRequirement(step bootstrapStep, results map[string]error) (string, bool) {
+	for _, required := range step.requires {
+		err, ran := results[required]
+		if !ran {
+			return required, false
+		}
+		if err != nil {
+			return required, true
+		}
+	}
+
+	return "", false
+}
@@ -0,0 +1,102 @@
+package controllers
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/danielronalds/gotm/models"
+)
+
+// Fake of the project and the tools bootstrapping uses, recording the calls made in order and
+// failing the calls listed in fails
+type fakeBootstrapTools struct {
+	config models.BootstrapConfig
+	fails  []string
+	calls  *[]string
+}
+
+func (f fakeBootstrapTools) call(name string) error {
+	*f.calls = append(*f.calls, name)
+	if slices.Contains(f.fails, name) {
+		return errors.New(name + " failed")
+	}
+	return nil
+}
+
+func (f fakeBootstrapTools) SetRoot(dir string) error {
+	return nil
+}
+
+func (f fakeBootstrapTools) Manifest() (models.Manifest, error) {
+	return models.DefaultManifest("shop", "shop"), nil
+}
+
+func (f fakeBootstrapTools) InstallNpmDeps() error {
+	return f.call("npm install")
+}
+
+func (f fakeBootstrapTools) InstallGoDeps() error {
+	return f.call("go mod tidy")
+}
+
+func (f fakeBootstrapTools) VerifyBuild() error {
+	return f.call("build")
+}
+
+func (f fakeBootstrapTools) InitRepository() error {
+	return f.call("git init")
+}
+
+func (f fakeBootstrapTools) CommitAll(message string) error {
+	return f.call("commit")
+}
+
+func (f fakeBootstrapTools) UserConfig() (models.UserConfig, error) {
+	return models.UserConfig{Bootstrap: f.config}, nil
+}
+
+func TestBootstrapperRunsEnabledStepsInOrder(t *testing.T) {
+	// Arrange
+	everything := models.BootstrapConfig{Git: true, Install: true, Build: true, Commit: true}
+	tests := []struct {
+		name          string
+		config        models.BootstrapConfig
+		args          []string
+		fails         []string
+		expectedCalls []string
+		expectedErr   bool
+	}{
+		{"default config", models.BootstrapConfig{}, nil, nil, []string{}, false},
+		{"every step", everything, nil, nil, []string{"git init", "npm install", "go mod tidy", "build", "commit"}, false},
+		{"commit implies git", models.BootstrapConfig{Commit: true}, nil, nil, []string{"git init", "commit"}, false},
+		{"no git", everything, []string{"--no-git"}, nil, []string{"npm install", "go mod tidy", "build"}, false},
+		{"no install", everything, []string{"--no-install"}, nil, []string{"git init", "commit"}, false},
+		{"failed install", everything, nil, []string{"npm install"}, []string{"git init", "npm install", "commit"}, true},
+		{"failed git", everything, nil, []string{"git init"}, []string{"git init", "npm install", "go mod tidy", "build"}, true},
+		{"bootstrap flag", models.BootstrapConfig{}, []string{"--bootstrap"}, nil, []string{"git init", "npm install", "go mod tidy", "build", "commit"}, false},
+		{"dry run", everything, []string{"--dry-run"}, nil, []string{}, false},
+		{"commit without git", models.BootstrapConfig{}, []string{"--commit", "--no-git"}, nil, []string{}, true},
+	}
+
+	for _, test := range tests {
+		calls := make([]string, 0)
+		tools := fakeBootstrapTools{test.config, test.fails, &calls}
+		bootstrapper := NewBootstrapper(tools, tools, tools, tools, tools)
+		parsed, err := parseArgs(test.args, flagSpec{"dry-run": boolFlag}.with(bootstrapFlags))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Act
+		err = bootstrapper.Run("shop", parsed)
+
+		// Assert
+		if (err != nil) != test.expectedErr {
+			t.Fatalf("%v: wanted an error to be %v, got %v", test.name, test.expectedErr, err)
+		}
+		if !slices.Equal(calls, test.expectedCalls) {
+			t.Fatalf("%v: wanted %v, got %v", test.name, test.expectedCalls, calls)
+		}
+	}
+}
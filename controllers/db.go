@@ -0,0 +1,111 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/danielronalds/gotm/models"
+)
+
+type MigrationRunner interface {
+	Up() ([]models.Migration, error)
+	Down() (models.Migration, error)
+	Redo() (models.Migration, error)
+	Status() ([]models.Migration, error)
+}
+
+type DbController struct {
+	migrations MigrationRunner
+}
+
+func NewDbController(migrations MigrationRunner) DbController {
+	return DbController{migrations}
+}
+
+func (c DbController) Handle(args []string) error {
+	if len(args) == 0 || args[0] != "db" {
+		return errors.New("passed to incorrect controller! Passed to `db` controller")
+	}
+
+	parsed, err := parseArgs(args[1:], flagSpec{})
+	if err != nil {
+		return err
+	}
+
+	switch parsed.arg(0) {
+	case "up":
+		return c.up()
+	case "down":
+		return c.down()
+	case "redo":
+		return c.redo()
+	case "", "status":
+		return c.status()
+	}
+
+	return fmt.Errorf("\"%v\" is not a db subcommand, expected one of [up, down, status, redo]", parsed.arg(0))
+}
+
+func (c DbController) up() error {
+	applied, err := c.migrations.Up()
+	for _, migration := range applied {
+		fmt.Printf("Applied %v\n", migration.Filename)
+	}
+	if err != nil {
+		return err
+	}
+
+	if len(applied) == 0 {
+		fmt.Println("No migrations to apply, the database is up to date")
+	}
+
+	return nil
+}
+
+func (c DbController) down() error {
+	migration, err := c.migrations.Down()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Rolled back %v\n", migration.Filename)
+
+	return nil
+}
+
+func (c DbController) redo() error {
+	migration, err := c.migrations.Redo()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Reapplied %v\n", migration.Filename)
+
+	return nil
+}
+
+func (c DbController) status() error {
+	migrations, err := c.migrations.Status()
+	if err != nil {
+		return err
+	}
+
+	if len(migrations) == 0 {
+		fmt.Println("The project has no migrations")
+		return nil
+	}
+
+	fmt.Printf("%-26v  %v\n", "Applied At", "Migration")
+	for _, migration := range migrations {
+		appliedAt := "Pending"
+		if migration.Applied {
+			appliedAt = "Applied"
+			if !migration.AppliedAt.IsZero() {
+				appliedAt = migration.AppliedAt.Format("Mon Jan _2 15:04:05 2006")
+			}
+		}
+		fmt.Printf("%-26v  %v\n", appliedAt, migration.Filename)
+	}
+
+	return nil
+}
@@ -0,0 +1,97 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/danielronalds/gotm/models"
+)
+
+// Commands that support being run with `--dry-run`
+var DryRunCommands = []string{"new", "init", "add", "remove", "destroy"}
+
+type ChangeRecorder interface {
+	Changes() []models.FileChange
+}
+
+type command interface {
+	Handle(args []string) error
+}
+
+// Controller wrapping a command run against an in-memory filesystem, printing what it would have
+// changed on disk
+type DryRunController struct {
+	command  command
+	recorder ChangeRecorder
+}
+
+func NewDryRunController(command command, recorder ChangeRecorder) DryRunController {
+	return DryRunController{command, recorder}
+}
+
+func (c DryRunController) Handle(args []string) error {
+	if len(args) == 0 || !slices.Contains(DryRunCommands, args[0]) {
+		return fmt.Errorf("--dry-run is only supported by %v", DryRunCommands)
+	}
+
+	// Commands are passed --dry-run so they can skip anything that can't be simulated
+	showContents := slices.Contains(args, "--show-contents")
+	args = slices.DeleteFunc(slices.Clone(args), func(arg string) bool {
+		return arg == "--show-contents"
+	})
+
+	if err := c.command.Handle(args); err != nil {
+		return err
+	}
+
+	changes := c.recorder.Changes()
+	if len(changes) == 0 {
+		return errors.New("\nDry run: no changes would be made")
+	}
+
+	fmt.Println("\n\nDry run, nothing has been written. The following changes would be made:")
+	for _, change := range changes {
+		path := relativeToCwd(change.Path)
+
+		switch {
+		case change.Deleted:
+			fmt.Printf("  delete  %v\n", path)
+		case change.Modified:
+			fmt.Printf("  modify  %v\n", path)
+		case change.IsDir:
+			fmt.Printf("  create  %v/\n", path)
+		default:
+			fmt.Printf("  create  %v\n", path)
+		}
+	}
+
+	if !showContents {
+		return nil
+	}
+
+	for _, change := range changes {
+		if change.IsDir || change.Deleted {
+			continue
+		}
+		fmt.Printf("\n==> %v <==\n%v", relativeToCwd(change.Path), change.Contents)
+	}
+
+	return nil
+}
+
+func relativeToCwd(path string) string {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return path
+	}
+
+	rel, err := filepath.Rel(cwd, path)
+	if err != nil {
+		return path
+	}
+
+	return rel
+}
@@ -0,0 +1,104 @@
+package controllers
+
+import (
+	"fmt"
+	"strings"
+)
+
+type flagKind int
+
+const (
+	boolFlag flagKind = iota
+	valueFlag
+)
+
+// Flags a command accepts, mapping the flag name (without dashes) to its kind
+type flagSpec map[string]flagKind
+
+// Returns a spec accepting the flags of both specs
+func (f flagSpec) with(other flagSpec) flagSpec {
+	combined := make(flagSpec, len(f)+len(other))
+	for name, kind := range f {
+		combined[name] = kind
+	}
+	for name, kind := range other {
+		combined[name] = kind
+	}
+	return combined
+}
+
+// Command line arguments, split into positional arguments and flags
+type parsedArgs struct {
+	positional []string
+	flags      map[string]string
+}
+
+// Splits args into positional arguments and flags. Flags may be given as `--name value`,
+// `--name=value` or `-name value`. Boolean flags never consume the following argument
+func parseArgs(args []string, spec flagSpec) (parsedArgs, error) {
+	parsed := parsedArgs{make([]string, 0), make(map[string]string)}
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+
+		if arg == "--" {
+			parsed.positional = append(parsed.positional, args[i+1:]...)
+			break
+		}
+
+		if !strings.HasPrefix(arg, "-") || arg == "-" {
+			parsed.positional = append(parsed.positional, arg)
+			continue
+		}
+
+		name := strings.TrimLeft(arg, "-")
+		value, hasValue := "", false
+		if before, after, found := strings.Cut(name, "="); found {
+			name, value, hasValue = before, after, true
+		}
+
+		kind, ok := spec[name]
+		if !ok {
+			return parsed, fmt.Errorf("unknown flag \"%v\"", arg)
+		}
+
+		if kind == boolFlag {
+			if !hasValue {
+				value = "true"
+			}
+			parsed.flags[name] = value
+			continue
+		}
+
+		if !hasValue {
+			if i+1 >= len(args) {
+				return parsed, fmt.Errorf("flag \"%v\" expects a value", arg)
+			}
+			i++
+			value = args[i]
+		}
+		parsed.flags[name] = value
+	}
+
+	return parsed, nil
+}
+
+// Returns the value of a value flag, and whether it was passed
+func (a parsedArgs) value(name string) (string, bool) {
+	value, ok := a.flags[name]
+	return value, ok
+}
+
+// Returns whether a boolean flag was passed, and was not explicitly set to false
+func (a parsedArgs) isSet(name string) bool {
+	value, ok := a.flags[name]
+	return ok && value != "false"
+}
+
+// Returns the positional argument at the given index, or an empty string if there isn't one
+func (a parsedArgs) arg(i int) string {
+	if i >= len(a.positional) {
+		return ""
+	}
+	return a.positional[i]
+}
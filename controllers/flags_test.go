@@ -0,0 +1,104 @@
+package controllers
+
+import (
+	"slices"
+	"testing"
+)
+
+var testFlags = flagSpec{"module": valueFlag, "force": boolFlag}
+
+func TestParseArgsAcceptsEachFlagForm(t *testing.T) {
+	// Arrange
+	inputs := [][]string{{"--module=example.com/shop"}, {"--module", "example.com/shop"}, {"-module", "example.com/shop"}, {"-module=example.com/shop"}}
+
+	for _, args := range inputs {
+		// Act
+		parsed, err := parseArgs(args, testFlags)
+
+		// Assert
+		if err != nil {
+			t.Fatalf("Failed to parse %v: %v", args, err)
+		}
+		if value, ok := parsed.value("module"); !ok || value != "example.com/shop" {
+			t.Fatalf("Wanted example.com/shop from %v, got %v", args, value)
+		}
+		if len(parsed.positional) != 0 {
+			t.Fatalf("Expected no positional args from %v, got %v", args, parsed.positional)
+		}
+	}
+}
+
+func TestParseArgsBoolFlagsTakeValuesOnlyAfterEquals(t *testing.T) {
+	// Arrange
+	tests := []struct {
+		args               []string
+		expectedSet        bool
+		expectedPositional []string
+	}{
+		{[]string{"--force"}, true, []string{}},
+		{[]string{"--force=true"}, true, []string{}},
+		{[]string{"--force=false"}, false, []string{}},
+		{[]string{"--force", "false"}, true, []string{"false"}},
+		{[]string{}, false, []string{}},
+	}
+
+	for _, test := range tests {
+		// Act
+		parsed, err := parseArgs(test.args, testFlags)
+
+		// Assert
+		if err != nil {
+			t.Fatalf("Failed to parse %v: %v", test.args, err)
+		}
+		if parsed.isSet("force") != test.expectedSet {
+			t.Fatalf("Wanted force to be %v from %v", test.expectedSet, test.args)
+		}
+		if !slices.Equal(parsed.positional, test.expectedPositional) {
+			t.Fatalf("Wanted %v from %v, got %v", test.expectedPositional, test.args, parsed.positional)
+		}
+	}
+}
+
+func TestParseArgsRejectsMissingValue(t *testing.T) {
+	// Act
+	_, err := parseArgs([]string{"shop", "--module"}, testFlags)
+
+	// Assert
+	if err == nil {
+		t.Fatal("Expected a value flag at the end of the args to fail")
+	}
+}
+
+func TestParseArgsRejectsUnknownFlags(t *testing.T) {
+	// Arrange
+	inputs := [][]string{{"--modules", "shop"}, {"-f"}, {"shop", "--no-force"}}
+
+	for _, args := range inputs {
+		// Act
+		_, err := parseArgs(args, testFlags)
+
+		// Assert
+		if err == nil {
+			t.Fatalf("Expected %v to fail", args)
+		}
+	}
+}
+
+func TestParseArgsKeepsPositionalArgsMixedWithFlags(t *testing.T) {
+	// Act
+	parsed, err := parseArgs([]string{"controller", "--force", "books", "--module", "shop", "-", "--", "--not-a-flag"}, testFlags)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Failed to parse args: %v", err)
+	}
+	if expected := []string{"controller", "books", "-", "--not-a-flag"}; !slices.Equal(parsed.positional, expected) {
+		t.Fatalf("Wanted %v, got %v", expected, parsed.positional)
+	}
+	if value, _ := parsed.value("module"); value != "shop" || !parsed.isSet("force") {
+		t.Fatalf("Expected module and force to be set, got %v", parsed.flags)
+	}
+	if parsed.arg(1) != "books" || parsed.arg(4) != "" {
+		t.Fatalf("Expected arg to return positional args or nothing, got %v and %v", parsed.arg(1), parsed.arg(4))
+	}
+}
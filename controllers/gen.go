@@ -0,0 +1,98 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/danielronalds/gotm/models"
+)
+
+type TypeGenerator interface {
+	GenerateTypes() (models.GeneratedTypes, error)
+	HasGeneratedTypes() (bool, error)
+}
+
+type MockGenerator interface {
+	GenerateMocks(dir string) ([]models.GeneratedMocks, error)
+	RegenerateMocks() ([]models.GeneratedMocks, error)
+}
+
+type GenController struct {
+	types TypeGenerator
+	mocks MockGenerator
+}
+
+func NewGenController(types TypeGenerator, mocks MockGenerator) GenController {
+	return GenController{types, mocks}
+}
+
+func (c GenController) Handle(args []string) error {
+	if len(args) == 0 || args[0] != "gen" {
+		return errors.New("passed to incorrect controller! Passed to `gen` controller")
+	}
+
+	parsed, err := parseArgs(args[1:], flagSpec{})
+	if err != nil {
+		return err
+	}
+
+	switch parsed.arg(0) {
+	case "types":
+		return c.generateTypes()
+	case "mocks":
+		return c.generateMocks(parsed.arg(1))
+	}
+
+	return fmt.Errorf("\"%v\" is not a gen subcommand, expected one of [types, mocks]", parsed.arg(0))
+}
+
+func (c GenController) generateTypes() error {
+	generated, err := c.types.GenerateTypes()
+	if err != nil {
+		return fmt.Errorf("failed to generate types: %v", err.Error())
+	}
+
+	if !generated.Changed {
+		fmt.Printf("%v is up to date\n", generated.Filename)
+		return nil
+	}
+
+	fmt.Printf("Generated %v types in %v\n", len(generated.Types), generated.Filename)
+	for _, name := range generated.Types {
+		fmt.Printf("  %v\n", name)
+	}
+
+	return nil
+}
+
+func (c GenController) generateMocks(dir string) error {
+	generated, err := c.mocks.GenerateMocks(dir)
+	printGeneratedMocks(generated)
+	if err != nil {
+		return fmt.Errorf("failed to generate mocks: %v", err.Error())
+	}
+
+	if len(generated) == 0 {
+		fmt.Println("No interfaces were found in the project")
+	}
+
+	return nil
+}
+
+func printGeneratedMocks(generated []models.GeneratedMocks) {
+	for _, mocks := range generated {
+		switch {
+		case mocks.Deleted:
+			fmt.Printf("Deleted %v, as the package no longer declares interfaces\n", mocks.Filename)
+		case mocks.Changed:
+			fmt.Printf("Generated fakes of %v in %v\n", strings.Join(mocks.Interfaces, ", "), mocks.Filename)
+		default:
+			fmt.Printf("%v is up to date\n", mocks.Filename)
+		}
+
+		for _, skipped := range mocks.Skipped {
+			fmt.Printf("  Skipped %v\n", skipped)
+		}
+	}
+}
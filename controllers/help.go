@@ -20,14 +20,68 @@ func (c HelpController) Handle(args []string) error {
 
 A cli tool building opinionated full stack web applications with the GOTM stack
 
+Usage: gotm [-C dir] <command> [args]
+
+new, init, add and remove accept --dry-run to list the files they would change without writing anything,
+add --show-contents to also print the rendered files
+
 Commands
-  new         Creates a new project with the passed in name
-  init        Creates a new project with the passed in name, in the current directory
+  new         Creates a new project with the passed in name [--module path]
+              Run without a name on a terminal to be asked for the project's details, or pass them
+              with --port n, --database, --dockerfile and --pages about,notfound
+  init        Creates a new project with the passed in name, in the current directory [--module path]
+              Pass --adopt to add gotm to an existing Go or frontend project, keeping existing files
+              Pass --bootstrap to also run git init, install, a test build and an initial commit
   install     Installs project dependencies
-  add         Adds a component to the project [controller, service, repository, view, page, model, dockerfile]
+  add         Adds a component to the project [controller, service, repository, middleware, view, page, model,
+              dockerfile, compose, table, resource]. Names can be written in any casing, i.e.
+              userProfile or user-profile, and are converted to the casing each file needs. Names with
+              slashes, i.e. admin/users, nest the component in a sub-package or directory and prefix
+              its routes
+              Middleware can be generated from a preset with --preset
+              [logging, recover, cors, ratelimit, requestid, basicauth]
+              Table and resource names are singular, i.e. book, and pluralised where needed
+              Tables take fields as name=type[:notnull][:unique][:default=value][:references=table]
+              Resources generate a model, repository, service, controller and pages for CRUD over
+              the REST API, taking fields as name:type [string, int, int64, float64, bool]
+              Controllers are registered in main.go and pages in the router automatically, pass
+              --no-register to skip this. Pages are routed to /<name>, or the path given with --route
+              Controllers, services and repositories are generated with a _test.go using --with-tests,
+              and "gotm add test <component-type> <name>" backfills the test of an existing one
+              Controllers and services take what they're constructed with using --uses, i.e.
+              --uses BooksService,UsersService, declaring an interface for each and constructing
+              them along with the controller in main.go
+              The dockerfile is built for the project's Go version, packages and port, using cgo when
+              sqlite needs it, and runs as a non-root user with a healthcheck. A .dockerignore is
+              added alongside it unless the project has one
+              "gotm add compose" generates a compose.yaml running the app from the Dockerfile, and the
+              services chosen with --service [postgres, redis] with volumes and healthchecks
+  remove      Removes a component added with add, unregistering it from main.go and the router.
+              Files changed since they were generated are only removed with --force. Also run as destroy
   npm         Convenience command for running npm in the frontend folder
-  watch       Watches for file changes, rebuilding the project when required
+  watch       Watches for file changes, rebuilding the project when required, and regenerating
+              the project's TypeScript types and fakes if they've been generated with gen
+  db          Applies or rolls back the project's goose migrations [up, down, status, redo]
+  gen         Generates code from the project's Go packages [types, mocks]. types declares the JSON
+              shape of exported structs with json tags, and of anonymous structs handlers respond
+              with, as TypeScript interfaces in frontend/src/types/generated.ts
+              "gen mocks [package]" generates fakes of the interfaces in the package, or every package,
+              into mocks_test.go. Each fake has a function field and recorded calls for every method
+  templates   Lists, shows or ejects the templates components are generated from [list, show, eject]
   help        Show this menu
+
+The module path of new projects is taken from --module, then modulePrefix/host/owner in
+~/.config/gotm/config.json, then the git remote (init only) or git username
+
+The steps run after new and init can be picked individually with --git, --install, --build and
+--commit (or --no-git etc.), with defaults read from "bootstrap" in ~/.config/gotm/config.json
+
+Project layout is read from gotm.json in the project root, created by new and init. The root
+is searched for from the current directory, or the directory passed with -C
+
+Templates are read from .gotm/templates/ in the project, then ~/.config/gotm/templates/, before
+falling back to the built in templates. Use "gotm templates eject <name> [--user]" to copy a built
+in template there for editing
 `
 
 	fmt.Println(help)
@@ -1,17 +1,28 @@
 package controllers
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"strings"
+
+	"github.com/danielronalds/gotm/models"
 )
 
+type ProjectAdopter interface {
+	ProjectInitialiser
+	AdoptProject(ctx context.Context, manifest models.Manifest, projectDir string) (models.AdoptionReport, error)
+}
+
 type InitController struct {
-	initialiser ProjectInitialiser
+	initialiser  ProjectAdopter
+	modules      ModulePathResolver
+	validator    NameValidator
+	bootstrapper Bootstrapper
 }
 
-func NewInitController(initialiser ProjectInitialiser) InitController {
-	return InitController{initialiser}
+func NewInitController(initialiser ProjectAdopter, modules ModulePathResolver, validator NameValidator, bootstrapper Bootstrapper) InitController {
+	return InitController{initialiser, modules, validator, bootstrapper}
 }
 
 func (c InitController) Handle(args []string) error {
@@ -19,17 +30,79 @@ func (c InitController) Handle(args []string) error {
 		return errors.New("passed to incorrect controller! Passed to `init` controller")
 	}
 
-	if len(args) < 2 || args[1] == "" {
+	parsed, err := parseArgs(args[1:], flagSpec{"module": valueFlag, "adopt": boolFlag, "dry-run": boolFlag}.with(bootstrapFlags))
+	if err != nil {
+		return err
+	}
+
+	if parsed.arg(0) == "" {
 		return errors.New("expected argument [project-name]")
 	}
 
-	projectName := strings.TrimSuffix(args[1], "/") // Ensuring no path is accidentally included
+	projectName := strings.TrimSuffix(parsed.arg(0), "/") // Ensuring no path is accidentally included
+
+	modulePath, err := resolveModulePath(c.modules, parsed, projectName, ".")
+	if err != nil {
+		return err
+	}
+
+	manifest := models.DefaultManifest(projectName, modulePath)
+	if err := validateManifest(c.validator, manifest); err != nil {
+		return err
+	}
+
+	ctx, stop := interruptContext()
+	defer stop()
+
+	if parsed.isSet("adopt") {
+		if err := c.adopt(ctx, manifest); err != nil {
+			return err
+		}
+		return c.bootstrapper.Run(".", parsed)
+	}
 
-	if err := c.initialiser.InitProject("danielronalds", projectName, "."); err != nil { // TODO: make user configurable username
+	if err := c.initialiser.InitProject(ctx, manifest, "."); err != nil {
 		return fmt.Errorf("unable to create project \"%v\": %v", projectName, err)
 	}
 
-	fmt.Printf("Initialised \"%v\" project", projectName)
+	fmt.Printf("Initialised \"%v\" project (module %v)\n", projectName, modulePath)
+
+	return c.bootstrapper.Run(".", parsed)
+}
+
+func (c InitController) adopt(ctx context.Context, manifest models.Manifest) error {
+	report, err := c.initialiser.AdoptProject(ctx, manifest, ".")
+	if err != nil {
+		return fmt.Errorf("unable to adopt project \"%v\": %v", manifest.Name, err)
+	}
+
+	sections := []struct {
+		heading string
+		files   []string
+	}{
+		{"Created", report.Created},
+		{"Merged", report.Merged},
+		{"Skipped (already exist)", report.Skipped},
+	}
+
+	for _, section := range sections {
+		if len(section.files) == 0 {
+			continue
+		}
+		fmt.Printf("%v:\n", section.heading)
+		for _, file := range section.files {
+			fmt.Printf("  %v\n", file)
+		}
+	}
+
+	if len(report.Notes) != 0 {
+		fmt.Println("\nTo finish adopting the project:")
+		for _, note := range report.Notes {
+			fmt.Printf("  - %v\n", note)
+		}
+	}
+
+	fmt.Printf("\nAdopted \"%v\" project\n", manifest.Name)
 
 	return nil
 }
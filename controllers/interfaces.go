@@ -1,6 +1,23 @@
 package controllers
 
+import "github.com/danielronalds/gotm/models"
+
 type FilesystemRoot interface {
-	Root() string
-	FromRoot(path string) string
+	Root() (string, error)
+	FromRoot(path string) (string, error)
+}
+
+type NameValidator interface {
+	ValidateProjectName(name string) error
+	ValidateModulePath(path string) error
+	ValidateComponentName(componentType, name string) error
+}
+
+// Checks the project name and module path of the manifest are valid
+func validateManifest(validator NameValidator, manifest models.Manifest) error {
+	if err := validator.ValidateProjectName(manifest.Name); err != nil {
+		return err
+	}
+
+	return validator.ValidateModulePath(manifest.Module)
 }
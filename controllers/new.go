@@ -1,22 +1,34 @@
 package controllers
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"os"
+	"os/signal"
 	"strings"
+	"syscall"
+
+	"github.com/danielronalds/gotm/models"
 )
 
 type ProjectInitialiser interface {
-	InitProject(username, projectName, projectDir string) error
+	InitProject(ctx context.Context, manifest models.Manifest, projectDir string) error
+}
+
+type ModulePathResolver interface {
+	ResolveModulePath(projectName, projectDir string) (string, error)
 }
 
 type NewController struct {
-	initialiser ProjectInitialiser
-	filesystem  FilesystemRoot
+	initialiser  ProjectInitialiser
+	modules      ModulePathResolver
+	validator    NameValidator
+	bootstrapper Bootstrapper
 }
 
-func NewNewController(initialiser ProjectInitialiser, filesystem FilesystemRoot) NewController {
-	return NewController{initialiser, filesystem}
+func NewNewController(initialiser ProjectInitialiser, modules ModulePathResolver, validator NameValidator, bootstrapper Bootstrapper) NewController {
+	return NewController{initialiser, modules, validator, bootstrapper}
 }
 
 func (c NewController) Handle(args []string) error {
@@ -24,17 +36,68 @@ func (c NewController) Handle(args []string) error {
 		return errors.New("passed to incorrect controller! Passed to `new` controller")
 	}
 
-	if len(args) < 2 || args[1] == "" {
-		return errors.New("expected argument [project-name]")
+	parsed, err := parseArgs(args[1:], flagSpec{"module": valueFlag, "dry-run": boolFlag}.with(bootstrapFlags).with(projectOptionFlags))
+	if err != nil {
+		return err
+	}
+
+	manifest, err := c.manifest(parsed)
+	if err != nil {
+		return err
+	}
+	if err := validateManifest(c.validator, manifest); err != nil {
+		return err
 	}
+	projectName, modulePath := manifest.Name, manifest.Module
 
-	projectName := strings.TrimSuffix(args[1], "/") // Ensuring no path is accidentally included
+	ctx, stop := interruptContext()
+	defer stop()
 
-	if err := c.initialiser.InitProject("danielronalds", projectName, projectName); err != nil { // TODO: make user configurable username
+	if err := c.initialiser.InitProject(ctx, manifest, projectName); err != nil {
 		return fmt.Errorf("unable to create project \"%v\": %v", projectName, err)
 	}
 
-	fmt.Printf("Created \"%v\" project", projectName)
+	fmt.Printf("Created \"%v\" project (module %v)\n", projectName, modulePath)
+
+	return c.bootstrapper.Run(projectName, parsed)
+}
+
+// Builds the manifest of the new project from the arguments passed, running the wizard instead if
+// no project name was passed on a terminal
+func (c NewController) manifest(parsed parsedArgs) (models.Manifest, error) {
+	if parsed.arg(0) == "" {
+		if !isInteractive() {
+			return models.Manifest{}, errors.New("expected argument [project-name]")
+		}
+		return newNewProjectWizard(os.Stdin, os.Stdout, c.modules, c.validator).run(parsed)
+	}
+
+	projectName := strings.TrimSuffix(parsed.arg(0), "/") // Ensuring no path is accidentally included
+
+	modulePath, err := resolveModulePath(c.modules, parsed, projectName, projectName)
+	if err != nil {
+		return models.Manifest{}, err
+	}
+
+	return applyProjectOptions(models.DefaultManifest(projectName, modulePath), parsed)
+}
+
+// Returns a context that is cancelled when the user interrupts gotm, so long running operations
+// can clean up after themselves
+func interruptContext() (context.Context, context.CancelFunc) {
+	return signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+}
+
+// Uses the module path passed with `--module` if present, otherwise resolving one
+func resolveModulePath(modules ModulePathResolver, parsed parsedArgs, projectName, projectDir string) (string, error) {
+	if modulePath, ok := parsed.value("module"); ok && modulePath != "" {
+		return strings.TrimSuffix(modulePath, "/"), nil
+	}
+
+	modulePath, err := modules.ResolveModulePath(projectName, projectDir)
+	if err != nil {
+		return "", fmt.Errorf("unable to work out module path, try passing --module: %v", err)
+	}
 
-	return nil
+	return modulePath, nil
 }
@@ -0,0 +1,69 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/danielronalds/gotm/models"
+)
+
+type ComponentRemover interface {
+	PlanRemoval(componentType, name string) (models.ComponentRemoval, error)
+	RemoveComponent(removal models.ComponentRemoval, force bool) error
+}
+
+// Controller for `gotm remove`, also run as `gotm destroy`
+type RemoveController struct {
+	remover ComponentRemover
+}
+
+func NewRemoveController(remover ComponentRemover) RemoveController {
+	return RemoveController{remover}
+}
+
+func (c RemoveController) Handle(args []string) error {
+	if len(args) == 0 || (args[0] != "remove" && args[0] != "destroy") {
+		return errors.New("passed to incorrect controller! Passed to `remove` controller")
+	}
+
+	// Dry runs are handled by the filesystem the remover is given
+	parsed, err := parseArgs(args[1:], flagSpec{"dry-run": boolFlag, "force": boolFlag})
+	if err != nil {
+		return err
+	}
+
+	componentType, componentName := strings.ToLower(parsed.arg(0)), parsed.arg(1)
+	if componentType == "" || componentName == "" {
+		return errors.New("expected argument [component-type] [component-name]")
+	}
+
+	removal, err := c.remover.PlanRemoval(componentType, componentName)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Removing \"%v\" %v:\n", componentName, componentType)
+	for _, file := range removal.Files {
+		if slices.Contains(removal.Modified, file) {
+			fmt.Printf("  delete      %v (modified)\n", file)
+			continue
+		}
+		fmt.Printf("  delete      %v\n", file)
+	}
+	for _, controller := range removal.Controllers {
+		fmt.Printf("  unregister  %v from the controllers in main.go\n", path.Base(controller))
+	}
+	for _, page := range removal.Pages {
+		fmt.Printf("  unroute     %v from the router\n", page)
+	}
+
+	if err := c.remover.RemoveComponent(removal, parsed.isSet("force")); err != nil {
+		return fmt.Errorf("failed to remove %v component: %v", componentType, err.Error())
+	}
+
+	fmt.Printf("Removed \"%v\" %v\n", componentName, componentType)
+	return nil
+}
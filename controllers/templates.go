@@ -0,0 +1,93 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/danielronalds/gotm/models"
+)
+
+type TemplatesManager interface {
+	ListTemplates() ([]models.TemplateInfo, error)
+	ShowTemplate(name string) (models.TemplateInfo, string, error)
+	EjectTemplate(name string, source models.TemplateSource) (string, error)
+}
+
+type TemplatesController struct {
+	templates TemplatesManager
+}
+
+func NewTemplatesController(templates TemplatesManager) TemplatesController {
+	return TemplatesController{templates}
+}
+
+func (c TemplatesController) Handle(args []string) error {
+	if len(args) == 0 || args[0] != "templates" {
+		return errors.New("passed to incorrect controller! Passed to `templates` controller")
+	}
+
+	parsed, err := parseArgs(args[1:], flagSpec{"user": boolFlag})
+	if err != nil {
+		return err
+	}
+
+	switch parsed.arg(0) {
+	case "", "list":
+		return c.list()
+	case "show":
+		if parsed.arg(1) == "" {
+			return errors.New("expected argument [template-name]")
+		}
+		return c.show(parsed.arg(1))
+	case "eject":
+		if parsed.arg(1) == "" {
+			return errors.New("expected argument [template-name]")
+		}
+		source := models.PROJECT_TEMPLATE
+		if parsed.isSet("user") {
+			source = models.USER_TEMPLATE
+		}
+		return c.eject(parsed.arg(1), source)
+	}
+
+	return fmt.Errorf("\"%v\" is not a templates subcommand, expected one of [list, show, eject]", parsed.arg(0))
+}
+
+func (c TemplatesController) list() error {
+	templates, err := c.templates.ListTemplates()
+	if err != nil {
+		return fmt.Errorf("unable to list templates: %v", err)
+	}
+
+	for _, template := range templates {
+		if template.Source == models.EMBEDDED_TEMPLATE {
+			fmt.Printf("%-28v %v\n", template.Name, template.Source)
+			continue
+		}
+		fmt.Printf("%-28v %-9v %v\n", template.Name, template.Source, template.Path)
+	}
+
+	return nil
+}
+
+func (c TemplatesController) show(name string) error {
+	_, source, err := c.templates.ShowTemplate(name)
+	if err != nil {
+		return fmt.Errorf("unable to show template: %v", err)
+	}
+
+	fmt.Print(source)
+
+	return nil
+}
+
+func (c TemplatesController) eject(name string, source models.TemplateSource) error {
+	path, err := c.templates.EjectTemplate(name, source)
+	if err != nil {
+		return fmt.Errorf("unable to eject template: %v", err)
+	}
+
+	fmt.Printf("Ejected \"%v\" to %v\n", name, path)
+
+	return nil
+}
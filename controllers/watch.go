@@ -7,6 +7,8 @@ import (
 	"regexp"
 	"syscall"
 	"time"
+
+	"github.com/danielronalds/gotm/models"
 )
 
 type FileWatcher interface {
@@ -38,10 +40,12 @@ type WatchController struct {
 	builder     ProjectBuilder
 	runner      ProjectRunner
 	filesystem  FilesystemRootWriter
+	types       TypeGenerator
+	mocks       MockGenerator
 }
 
-func NewWatchController(watcher FileWatcher, builder ProjectBuilder, runner ProjectRunner, filesystem FilesystemRootWriter) WatchController {
-	return WatchController{watcher, builder, runner, filesystem}
+func NewWatchController(watcher FileWatcher, builder ProjectBuilder, runner ProjectRunner, filesystem FilesystemRootWriter, types TypeGenerator, mocks MockGenerator) WatchController {
+	return WatchController{watcher, builder, runner, filesystem, types, mocks}
 }
 
 // Function for actions needing be run before exiting the application
@@ -51,6 +55,11 @@ func (c WatchController) cleanup() {
 }
 
 func (c WatchController) Handle(args []string) error {
+	root, err := c.filesystem.Root()
+	if err != nil {
+		return err
+	}
+
 	fmt.Println("Watching project")
 
 	ch := make(chan os.Signal, 2)
@@ -63,7 +72,7 @@ func (c WatchController) Handle(args []string) error {
 	for {
 		time.Sleep(50 * time.Microsecond)
 
-		filesChanged, err := c.filewatcher.HaveFilesChanged(c.filesystem.Root())
+		filesChanged, err := c.filewatcher.HaveFilesChanged(root)
 		if err != nil {
 			return fmt.Errorf("failed to detect project changes: %v", err.Error())
 		}
@@ -76,11 +85,19 @@ func (c WatchController) Handle(args []string) error {
 				fmt.Println("\nDetected changes, rebuilding project")
 			}
 
+			// The frontend is rebuilt when the types it's compiled against change
+			if buildBackend && c.regenerateTypes() {
+				buildFrontend = true
+			}
+			if buildBackend {
+				c.regenerateMocks()
+			}
+
 			if err := c.builder.BuildDev(buildFrontend, buildBackend); err != nil {
 				fmt.Fprintf(os.Stderr, "failed to build project:\n %v", err.Error())
 				continue
 			}
-			if err := c.filewatcher.UpdateCache(c.filesystem.Root()); err != nil {
+			if err := c.filewatcher.UpdateCache(root); err != nil {
 				fmt.Fprintf(os.Stderr, "failed to update file cache: %v\n", err.Error())
 			}
 
@@ -96,6 +113,40 @@ func (c WatchController) Handle(args []string) error {
 	}
 }
 
+// Regenerates the project's TypeScript types if they've been generated before, returning whether
+// they changed
+func (c WatchController) regenerateTypes() bool {
+	if hasTypes, err := c.types.HasGeneratedTypes(); err != nil || !hasTypes {
+		return false
+	}
+
+	generated, err := c.types.GenerateTypes()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to generate types: %v\n", err.Error())
+		return false
+	}
+	if generated.Changed {
+		fmt.Printf("Regenerated %v\n", generated.Filename)
+	}
+
+	return generated.Changed
+}
+
+// Regenerates the fakes of the packages they've been generated for, so they keep up with the
+// interfaces they fake
+func (c WatchController) regenerateMocks() {
+	generated, err := c.mocks.RegenerateMocks()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to generate mocks: %v\n", err.Error())
+	}
+
+	for _, mocks := range generated {
+		if mocks.Changed || mocks.Deleted {
+			printGeneratedMocks([]models.GeneratedMocks{mocks})
+		}
+	}
+}
+
 func anyMatchRegex(slice []string, regex string) bool {
 	rgx, err := regexp.Compile(regex)
 	if err != nil {
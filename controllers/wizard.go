@@ -0,0 +1,226 @@
+package controllers
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/danielronalds/gotm/models"
+)
+
+// Flags accepted by `gotm new` for choosing what the project includes, mirroring the questions
+// asked by the wizard so projects can be created the same way without a terminal
+var projectOptionFlags = flagSpec{
+	"port":       valueFlag,
+	"database":   boolFlag,
+	"dockerfile": boolFlag,
+	"pages":      valueFlag,
+}
+
+// Returns whether gotm is being run interactively, i.e. stdin is a terminal rather than a pipe or
+// file
+func isInteractive() bool {
+	stat, err := os.Stdin.Stat()
+	if err != nil {
+		return false
+	}
+
+	return stat.Mode()&os.ModeCharDevice != 0
+}
+
+// Applies any project options passed as flags to the manifest
+func applyProjectOptions(manifest models.Manifest, parsed parsedArgs) (models.Manifest, error) {
+	if value, ok := parsed.value("port"); ok {
+		port, err := parsePort(value)
+		if err != nil {
+			return manifest, err
+		}
+		manifest.Port = port
+	}
+
+	if parsed.isSet("database") {
+		manifest.Database = models.DefaultDatabase()
+	}
+	manifest.Dockerfile = parsed.isSet("dockerfile")
+
+	if value, ok := parsed.value("pages"); ok {
+		pages, err := parsePages(value)
+		if err != nil {
+			return manifest, err
+		}
+		manifest.Pages = pages
+	}
+
+	return manifest, nil
+}
+
+func parsePort(value string) (int, error) {
+	port, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || port < 1 || port > 65535 {
+		return 0, fmt.Errorf("\"%v\" is not a valid port, expected a number between 1 and 65535", value)
+	}
+
+	return port, nil
+}
+
+// Parses a comma separated list of starter pages
+func parsePages(value string) ([]string, error) {
+	pages := make([]string, 0)
+
+	for _, page := range strings.Split(value, ",") {
+		page = strings.ToLower(strings.TrimSpace(page))
+		if page == "" || page == "none" || slices.Contains(pages, page) {
+			continue
+		}
+		if !slices.Contains(models.StarterPages, page) {
+			return nil, fmt.Errorf("\"%v\" is not a starter page, expected one of %v", page, strings.Join(models.StarterPages, ", "))
+		}
+		pages = append(pages, page)
+	}
+
+	return pages, nil
+}
+
+// Prompts for the details of a new project, used when `gotm new` is run on a terminal without a
+// project name
+type newProjectWizard struct {
+	in        *bufio.Reader
+	out       io.Writer
+	modules   ModulePathResolver
+	validator NameValidator
+}
+
+func newNewProjectWizard(in io.Reader, out io.Writer, modules ModulePathResolver, validator NameValidator) newProjectWizard {
+	return newProjectWizard{bufio.NewReader(in), out, modules, validator}
+}
+
+// Asks each question in turn, using any flags that were passed as the defaults
+func (w newProjectWizard) run(parsed parsedArgs) (models.Manifest, error) {
+	projectName, err := w.ask("Project name", "", func(answer string) error {
+		return w.validator.ValidateProjectName(strings.TrimSuffix(answer, "/"))
+	})
+	if err != nil {
+		return models.Manifest{}, err
+	}
+	projectName = strings.TrimSuffix(projectName, "/")
+
+	defaultModule, err := resolveModulePath(w.modules, parsed, projectName, projectName)
+	if err != nil {
+		defaultModule = projectName
+	}
+	modulePath, err := w.ask("Module path", defaultModule, func(answer string) error {
+		return w.validator.ValidateModulePath(strings.TrimSuffix(answer, "/"))
+	})
+	if err != nil {
+		return models.Manifest{}, err
+	}
+
+	manifest, err := applyProjectOptions(models.DefaultManifest(projectName, strings.TrimSuffix(modulePath, "/")), parsed)
+	if err != nil {
+		return models.Manifest{}, err
+	}
+
+	port, err := w.ask("HTTP port", strconv.Itoa(manifest.Port), func(answer string) error {
+		_, err := parsePort(answer)
+		return err
+	})
+	if err != nil {
+		return models.Manifest{}, err
+	}
+	manifest.Port, _ = parsePort(port)
+
+	includeDatabase, err := w.confirm("Include a database (sqlc and goose)?", manifest.Database != nil)
+	if err != nil {
+		return models.Manifest{}, err
+	}
+	manifest.Database = nil
+	if includeDatabase {
+		manifest.Database = models.DefaultDatabase()
+	}
+
+	if manifest.Dockerfile, err = w.confirm("Include a Dockerfile?", manifest.Dockerfile); err != nil {
+		return models.Manifest{}, err
+	}
+
+	defaultPages := "none"
+	if len(manifest.Pages) != 0 {
+		defaultPages = strings.Join(manifest.Pages, ",")
+	}
+	question := fmt.Sprintf("Starter pages to include (%v)", strings.Join(models.StarterPages, ", "))
+	pages, err := w.ask(question, defaultPages, func(answer string) error {
+		_, err := parsePages(answer)
+		return err
+	})
+	if err != nil {
+		return models.Manifest{}, err
+	}
+	manifest.Pages, _ = parsePages(pages)
+
+	fmt.Fprintln(w.out)
+
+	return manifest, nil
+}
+
+// Asks a question until the answer passes validate, returning defaultAnswer if nothing is entered
+func (w newProjectWizard) ask(question, defaultAnswer string, validate func(answer string) error) (string, error) {
+	for {
+		if defaultAnswer != "" {
+			fmt.Fprintf(w.out, "%v [%v]: ", question, defaultAnswer)
+		} else {
+			fmt.Fprintf(w.out, "%v: ", question)
+		}
+
+		line, err := w.in.ReadString('\n')
+		if err != nil && (err != io.EOF || line == "") {
+			return "", errors.New("no answer given, project creation cancelled")
+		}
+
+		answer := strings.TrimSpace(line)
+		if answer == "" {
+			answer = defaultAnswer
+		}
+
+		if validate == nil {
+			return answer, nil
+		}
+		if err := validate(answer); err != nil {
+			fmt.Fprintf(w.out, "  %v\n", err)
+			continue
+		}
+
+		return answer, nil
+	}
+}
+
+// Asks a yes or no question
+func (w newProjectWizard) confirm(question string, defaultAnswer bool) (bool, error) {
+	defaultText := "y/N"
+	if defaultAnswer {
+		defaultText = "Y/n"
+	}
+
+	answer, err := w.ask(fmt.Sprintf("%v (%v)", question, defaultText), "", func(answer string) error {
+		switch strings.ToLower(answer) {
+		case "", "y", "yes", "n", "no":
+			return nil
+		}
+		return errors.New("please answer y or n")
+	})
+	if err != nil {
+		return false, err
+	}
+
+	switch strings.ToLower(answer) {
+	case "y", "yes":
+		return true, nil
+	case "n", "no":
+		return false, nil
+	}
+
+	return defaultAnswer, nil
+}
@@ -0,0 +1,173 @@
+package controllers
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/danielronalds/gotm/models"
+)
+
+type fakeModules struct{}
+
+func (f fakeModules) ResolveModulePath(projectName, projectDir string) (string, error) {
+	return "github.com/user/" + projectName, nil
+}
+
+// Fake validator rejecting names with spaces or capital letters
+type fakeValidator struct{}
+
+func (f fakeValidator) ValidateProjectName(name string) error {
+	if name == "" || strings.ContainsRune(name, ' ') || strings.ToLower(name) != name {
+		return errors.New("invalid project name")
+	}
+	return nil
+}
+
+func (f fakeValidator) ValidateModulePath(path string) error {
+	if path == "" || strings.ContainsRune(path, ' ') {
+		return errors.New("invalid module path")
+	}
+	return nil
+}
+
+func (f fakeValidator) ValidateComponentName(componentType, name string) error {
+	return nil
+}
+
+// Runs the wizard with the answers given as lines of input, returning the manifest and everything
+// written to the user
+func runTestWizard(input string, args []string, t *testing.T) (models.Manifest, string, error) {
+	parsed, err := parseArgs(args, projectOptionFlags)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out strings.Builder
+	manifest, err := newNewProjectWizard(strings.NewReader(input), &out, fakeModules{}, fakeValidator{}).run(parsed)
+	return manifest, out.String(), err
+}
+
+func TestWizardAcceptsDefaultsOnEmptyInput(t *testing.T) {
+	// Act
+	manifest, _, err := runTestWizard("shop\n\n\n\n\n\n", nil, t)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Failed to run wizard: %v", err)
+	}
+	if manifest.Name != "shop" || manifest.Module != "github.com/user/shop" || manifest.Port != 3000 {
+		t.Fatalf("Expected the default module and port, got %+v", manifest)
+	}
+	if manifest.Database != nil || manifest.Dockerfile || len(manifest.Pages) != 0 {
+		t.Fatalf("Expected no database, Dockerfile or pages, got %+v", manifest)
+	}
+}
+
+func TestWizardUsesFlagsAsDefaults(t *testing.T) {
+	// Act
+	manifest, _, err := runTestWizard("shop\n\n\n\n\n\n", []string{"--port", "8080", "--database", "--pages=about"}, t)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Failed to run wizard: %v", err)
+	}
+	if manifest.Port != 8080 || manifest.Database == nil || !slices.Equal(manifest.Pages, []string{"about"}) {
+		t.Fatalf("Expected the flags to be used, got %+v", manifest)
+	}
+}
+
+func TestWizardRepromptsOnInvalidAnswers(t *testing.T) {
+	// Arrange
+	input := strings.Join([]string{"My Shop", "shop", "", "http", "80000", "8080", "maybe", "y", "n", "contact", "about, notfound"}, "\n") + "\n"
+
+	// Act
+	manifest, out, err := runTestWizard(input, nil, t)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Failed to run wizard: %v", err)
+	}
+	if manifest.Name != "shop" || manifest.Port != 8080 || manifest.Database == nil || manifest.Dockerfile {
+		t.Fatalf("Expected the valid answers to be used, got %+v", manifest)
+	}
+	if !slices.Equal(manifest.Pages, []string{"about", "notfound"}) {
+		t.Fatalf("Wanted [about notfound], got %v", manifest.Pages)
+	}
+	for _, problem := range []string{"invalid project name", `"http" is not a valid port`, `"80000" is not a valid port`, "please answer y or n", `"contact" is not a starter page`} {
+		if !strings.Contains(out, problem) {
+			t.Fatalf("Expected the wizard to say %v, got:\n%v", problem, out)
+		}
+	}
+}
+
+func TestWizardCancelsOnEndOfInput(t *testing.T) {
+	// Arrange
+	inputs := []string{"", "shop\n", "shop\n\n8080"}
+
+	for _, input := range inputs {
+		// Act
+		_, _, err := runTestWizard(input, nil, t)
+
+		// Assert
+		if err == nil || !strings.Contains(err.Error(), "cancelled") {
+			t.Fatalf("Expected input %q to cancel the wizard, got %v", input, err)
+		}
+	}
+}
+
+func TestWizardAcceptsFinalAnswerWithoutNewline(t *testing.T) {
+	// Act
+	manifest, _, err := runTestWizard("shop\n\n\n\n\nnotfound", nil, t)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Failed to run wizard: %v", err)
+	}
+	if !slices.Equal(manifest.Pages, []string{"notfound"}) {
+		t.Fatalf("Wanted [notfound], got %v", manifest.Pages)
+	}
+}
+
+func TestApplyProjectOptionsSetsManifest(t *testing.T) {
+	// Arrange
+	parsed, err := parseArgs([]string{"--port=4000", "--database", "--dockerfile", "--pages", "notfound,about,about"}, projectOptionFlags)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Act
+	manifest, err := applyProjectOptions(models.DefaultManifest("shop", "shop"), parsed)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Failed to apply options: %v", err)
+	}
+	if manifest.Port != 4000 || manifest.Database == nil || !manifest.Dockerfile {
+		t.Fatalf("Expected the port, database and Dockerfile to be set, got %+v", manifest)
+	}
+	if !slices.Equal(manifest.Pages, []string{"notfound", "about"}) {
+		t.Fatalf("Wanted [notfound about], got %v", manifest.Pages)
+	}
+}
+
+func TestApplyProjectOptionsRejectsInvalidOptions(t *testing.T) {
+	// Arrange
+	inputs := [][]string{{"--port", "0"}, {"--port", "abc"}, {"--pages", "about,contact"}}
+
+	for _, args := range inputs {
+		parsed, err := parseArgs(args, projectOptionFlags)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Act
+		_, err = applyProjectOptions(models.DefaultManifest("shop", "shop"), parsed)
+
+		// Assert
+		if err == nil {
+			t.Fatalf("Expected %v to be rejected", args)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 	"os"
+	"slices"
 	"strings"
 
 	c "github.com/danielronalds/gotm/controllers"
@@ -14,17 +15,76 @@ type Controller interface {
 	Handle(args []string) error
 }
 
+// Filesystem used by the services that generate files, swapped out for an in-memory one on dry runs
+type generationFilesystem interface {
+	s.InitialiserServiceFilesystem
+	s.ComponentServiceFilesystem
+}
+
+// Splits out the global flags that come before the command, i.e. `-C <dir>` or `--root <dir>`
+// which run gotm as if it was started in that directory
+func parseGlobalFlags(args []string) (startDir string, rest []string, err error) {
+	startDir = "."
+
+	for len(args) != 0 {
+		switch arg := args[0]; {
+		case arg == "-C" || arg == "--root":
+			if len(args) < 2 {
+				return "", nil, fmt.Errorf("flag \"%v\" expects a directory", arg)
+			}
+			startDir, args = args[1], args[2:]
+		case strings.HasPrefix(arg, "-C="), strings.HasPrefix(arg, "--root="):
+			_, startDir, _ = strings.Cut(arg, "=")
+			args = args[1:]
+		default:
+			return startDir, args, nil
+		}
+	}
+
+	return startDir, args, nil
+}
+
 func run(args []string) {
-	templates := r.NewTemplatesRepository()
+	startDir, args, err := parseGlobalFlags(args)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err.Error())
+		os.Exit(1)
+	}
+	if err := os.Chdir(startDir); err != nil {
+		fmt.Fprintf(os.Stderr, "unable to change to %v: %v\n", startDir, err.Error())
+		os.Exit(1)
+	}
+
 	shell := r.NewShellRepository()
-	filesystem := r.NewFilesystemRepository([]string{".git", "nodemodules"})
+	userConfig := r.NewUserConfigRepository()
+	filesystem := r.NewFilesystemRepository([]string{".git", "node_modules"}, ".")
+	templates := r.NewTemplatesRepository(filesystem, userConfig)
+	database := r.NewDatabaseRepository()
 
-	initService := s.NewInitialiserService(filesystem, templates)
-	componentService := s.NewComponentService(filesystem, templates)
+	dryRun := slices.Contains(args, "--dry-run")
+	overlay := r.NewOverlayFilesystemRepository(filesystem)
+	var generator generationFilesystem = filesystem
+	if dryRun {
+		generator = overlay
+	}
+
+	initService := s.NewInitialiserService(generator, templates)
+	componentService := s.NewComponentService(generator, templates)
 	buildService := s.NewBuildService(filesystem, shell)
 	filewatcherService := s.NewFilewatcherService(filesystem)
 	runnerService := s.NewRunnerService(filesystem)
 	npmService := s.NewNpmService(filesystem, shell)
+	modulePathService := s.NewModulePathService(userConfig, shell)
+	templatesService := s.NewTemplatesService(filesystem, templates)
+	gitService := s.NewGitService(filesystem, shell)
+	validationService := s.NewValidationService()
+	tableService := s.NewTableService(generator, templates, shell)
+	migrationService := s.NewMigrationService(filesystem, database)
+	typesService := s.NewTypesService(filesystem)
+	mocksService := s.NewMocksService(filesystem)
+
+	bootstrapper := c.NewBootstrapper(filesystem, buildService, buildService, gitService, userConfig)
+	removeController := c.NewRemoveController(componentService)
 
 	cmd := "help" // Default command is the help command
 	if len(args) != 0 {
@@ -32,17 +92,25 @@ func run(args []string) {
 	}
 
 	controllerMap := map[string]Controller{
-		"new":     c.NewNewController(initService, filesystem),
-		"init":    c.NewInitController(initService),
-		"install": c.NewInstallController(buildService),
-		"add":     c.NewAddController(componentService),
-		"watch":   c.NewWatchController(filewatcherService, buildService, &runnerService, filesystem),
-		"npm":     c.NewNpmController(npmService),
+		"new":       c.NewNewController(initService, modulePathService, validationService, bootstrapper),
+		"init":      c.NewInitController(initService, modulePathService, validationService, bootstrapper),
+		"install":   c.NewInstallController(buildService),
+		"add":       c.NewAddController(componentService, tableService, validationService),
+		"remove":    removeController,
+		"destroy":   removeController,
+		"watch":     c.NewWatchController(filewatcherService, buildService, &runnerService, filesystem, typesService, mocksService),
+		"npm":       c.NewNpmController(npmService),
+		"templates": c.NewTemplatesController(templatesService),
+		"db":        c.NewDbController(migrationService),
+		"gen":       c.NewGenController(typesService, mocksService),
 	}
 	controller, ok := controllerMap[cmd]
 	if !ok {
 		controller = c.NewHelpController()
 	}
+	if dryRun {
+		controller = c.NewDryRunController(controller, overlay)
+	}
 
 	if err := controller.Handle(args); err != nil {
 		fmt.Fprintf(os.Stderr, "\n%v\n", err.Error())
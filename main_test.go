@@ -28,6 +28,7 @@ func TestRunWithNewCmd(t *testing.T) {
 
 	// Assert
 	expectedFiles := []string{
+		"testproject/gotm.json",
 		"testproject/.gitignore",
 		"testproject/go.mod",
 		"testproject/main.go",
@@ -112,9 +113,12 @@ func TestRunWithAddCmd(t *testing.T) {
 		{args: []string{"add", "repository", "vehicle"}, expectedFile: "repositories/vehicle.go", cleanup: "repositories/vehicle.go"},
 		{args: []string{"add", "middleware", "auth"}, expectedFile: "middleware/auth.go", cleanup: "middleware"},
 		{args: []string{"add", "model", "vehicle"}, expectedFile: "frontend/src/models/vehicle.ts", cleanup: "frontend"},
-		{args: []string{"add", "view", "vehicle"}, expectedFile: "frontend/src/views/vehicle.ts", cleanup: "frontend"},
+		{args: []string{"add", "view", "vehicle"}, expectedFile: "frontend/src/views/Vehicle.ts", cleanup: "frontend"},
 	}
 
+	// Generated components are recorded in the project's ledger
+	defer delete(".gotm")
+
 	for _, input := range inputs {
 
 		// Act
@@ -0,0 +1,10 @@
+package models
+
+// What happened to each file when gotm adopted an existing project
+type AdoptionReport struct {
+	Created []string
+	Merged  []string
+	Skipped []string
+	// Anything the user needs to do by hand to finish adopting the project
+	Notes []string
+}
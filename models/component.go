@@ -0,0 +1,65 @@
+package models
+
+import "fmt"
+
+// Options passed to `gotm add` that change how a component is generated
+type ComponentOptions struct {
+	// Preset implementation to generate instead of the blank template, only used by middleware
+	Preset string
+	// Fields of the component as name:type pairs, only used by resources
+	Fields []string
+	// Path the page is routed to, defaulting to the page's name
+	Route string
+	// Skips registering the component where it's used, i.e. adding a controller to main.go
+	NoRegister bool
+	// Generates a test alongside the component, only used by controllers, services and repositories
+	WithTests bool
+	// Type names of the components the component is constructed with, i.e. BooksService, only used
+	// by controllers and services
+	Uses []string
+}
+
+// Returned when a component was generated but couldn't be registered where it's used, so it has to
+// be registered by hand
+type RegistrationError struct {
+	// What has to be done to register the component by hand
+	Instructions string
+	Err          error
+}
+
+func (e RegistrationError) Error() string {
+	return fmt.Sprintf("unable to register component (%v), %v", e.Err, e.Instructions)
+}
+
+func (e RegistrationError) Unwrap() error {
+	return e.Err
+}
+
+// Returned when a component was generated but its test wasn't, as there was nothing in the
+// component a generated test could check
+type NothingToTestError struct {
+	// Type of the component, i.e. service
+	ComponentType string
+	// What the component would need for a test to be generated
+	Needs string
+}
+
+func (e NothingToTestError) Error() string {
+	return fmt.Sprintf("the %v has nothing a generated test could check, it needs %v", e.ComponentType, e.Needs)
+}
+
+// What removing a component involves, worked out before anything is removed so it can be shown
+type ComponentRemoval struct {
+	// Files of the component, relative to the project root
+	Files []string
+	// Files that have changed since gotm generated them, or that gotm has no record of generating
+	Modified []string
+	// Constructors of the controllers registered in main.go, qualified by their import path i.e.
+	// example.com/app/controllers.NewBookController
+	Controllers []string
+	// Pages routed to in the router, i.e. BookListPage
+	Pages []string
+	// Directories the component is nested in, relative to the project root, which are removed
+	// along with it if nothing else is left in them
+	Directories []string
+}
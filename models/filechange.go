@@ -0,0 +1,11 @@
+package models
+
+// A change made to the filesystem, recorded by the in-memory filesystem during a dry run
+type FileChange struct {
+	Path    string
+	IsDir   bool
+	Deleted bool
+	// Whether the file already existed and has been overwritten
+	Modified bool
+	Contents string
+}
@@ -0,0 +1,158 @@
+package models
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Name of the project manifest file, found at the root of every gotm project
+const MANIFEST_FILENAME = "gotm.json"
+
+// The project manifest, describing the layout of a gotm project
+type Manifest struct {
+	Name   string `json:"name"`
+	Module string `json:"module"`
+	Port   int    `json:"port"`
+	// Directory containing the main package, relative to the project root
+	Main string `json:"main"`
+	// Directory containing the frontend, relative to the project root
+	Frontend string `json:"frontend"`
+	// Name of the binary built by `gotm watch`, placed in the project root
+	DevBinary   string      `json:"devBinary"`
+	IgnoredDirs []string    `json:"ignoredDirs"`
+	Directories Directories `json:"directories"`
+	// Database the project uses, nil if it doesn't have one
+	Database *Database `json:"database,omitempty"`
+	// Whether the project was created with a Dockerfile
+	Dockerfile bool `json:"dockerfile,omitempty"`
+	// Optional starter pages the project was created with
+	Pages []string `json:"pages,omitempty"`
+}
+
+// Optional pages that can be included when creating a project
+var StarterPages = []string{"about", "notfound"}
+
+// Database used by a project, with migrations managed by goose and queries generated by sqlc
+type Database struct {
+	// Database engine, as understood by sqlc
+	Engine string `json:"engine"`
+	// Location of the database, a file path for sqlite
+	Location string `json:"location"`
+}
+
+// Returns the database new projects use when one is requested
+func DefaultDatabase() *Database {
+	return &Database{Engine: "sqlite", Location: "app.db"}
+}
+
+// Directories components are generated in, relative to the project root
+type Directories struct {
+	Controllers  string `json:"controllers"`
+	Services     string `json:"services"`
+	Repositories string `json:"repositories"`
+	Middleware   string `json:"middleware"`
+	// Go structs shared between the backend packages, i.e. the models of resources
+	GoModels   string `json:"goModels"`
+	Migrations string `json:"migrations"`
+	Queries    string `json:"queries"`
+	Models     string `json:"models"`
+	Views      string `json:"views"`
+	Pages      string `json:"pages"`
+	// TypeScript declarations generated from the backend's Go structs
+	Types string `json:"types"`
+}
+
+// Returns the directory components of the given type are generated in
+func (d Directories) ForComponent(componentType string) (string, bool) {
+	dirs := map[string]string{
+		"controller": d.Controllers,
+		"service":    d.Services,
+		"repository": d.Repositories,
+		"middleware": d.Middleware,
+		"gomodel":    d.GoModels,
+		"migration":  d.Migrations,
+		"model":      d.Models,
+		"view":       d.Views,
+		"page":       d.Pages,
+	}
+
+	dir, ok := dirs[componentType]
+	return dir, ok && dir != ""
+}
+
+// Returns the manifest of a project using the default layout
+func DefaultManifest(name, module string) Manifest {
+	return Manifest{
+		Name:        name,
+		Module:      module,
+		Port:        3000,
+		Main:        ".",
+		Frontend:    "frontend",
+		DevBinary:   ".main.tmp",
+		IgnoredDirs: []string{".git", "node_modules"},
+		Directories: Directories{
+			Controllers:  "controllers",
+			Services:     "services",
+			Repositories: "repositories",
+			Middleware:   "middleware",
+			GoModels:     "models",
+			Migrations:   "migrations",
+			Queries:      "queries",
+			Models:       "frontend/src/models",
+			Views:        "frontend/src/views",
+			Pages:        "frontend/src/views/pages",
+			Types:        "frontend/src/types",
+		},
+	}
+}
+
+// Fills any fields missing from the manifest with their defaults, so that manifests only need to
+// specify what differs from the default layout
+func (m Manifest) WithDefaults() Manifest {
+	defaults := DefaultManifest(m.Name, m.Module)
+
+	setDefault(&m.Main, defaults.Main)
+	setDefault(&m.DevBinary, defaults.DevBinary)
+	if m.Port == 0 {
+		m.Port = defaults.Port
+	}
+	if m.IgnoredDirs == nil {
+		m.IgnoredDirs = defaults.IgnoredDirs
+	}
+
+	// Frontend component directories follow the frontend if it has been moved
+	if m.Frontend != "" && m.Frontend != defaults.Frontend {
+		setDefault(&m.Directories.Models, filepath.Join(m.Frontend, "src/models"))
+		setDefault(&m.Directories.Views, filepath.Join(m.Frontend, "src/views"))
+		setDefault(&m.Directories.Pages, filepath.Join(m.Frontend, "src/views/pages"))
+		setDefault(&m.Directories.Types, filepath.Join(m.Frontend, "src/types"))
+	}
+	setDefault(&m.Frontend, defaults.Frontend)
+
+	setDefault(&m.Directories.Controllers, defaults.Directories.Controllers)
+	setDefault(&m.Directories.Services, defaults.Directories.Services)
+	setDefault(&m.Directories.Repositories, defaults.Directories.Repositories)
+	setDefault(&m.Directories.Middleware, defaults.Directories.Middleware)
+	setDefault(&m.Directories.GoModels, defaults.Directories.GoModels)
+	setDefault(&m.Directories.Migrations, defaults.Directories.Migrations)
+	setDefault(&m.Directories.Queries, defaults.Directories.Queries)
+	setDefault(&m.Directories.Models, defaults.Directories.Models)
+	setDefault(&m.Directories.Views, defaults.Directories.Views)
+	setDefault(&m.Directories.Pages, defaults.Directories.Pages)
+	setDefault(&m.Directories.Types, defaults.Directories.Types)
+
+	return m
+}
+
+// Returns the main package in the form `go build` expects, i.e. `./cmd/server`
+func (m Manifest) MainPackage() string {
+	return fmt.Sprintf("./%v", strings.TrimPrefix(path.Clean(m.Main), "./"))
+}
+
+func setDefault(field *string, value string) {
+	if *field == "" {
+		*field = value
+	}
+}
@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// A goose migration found in the project's migrations directory
+type Migration struct {
+	Version  int64
+	Filename string
+	Applied  bool
+	// When the migration was applied, zero if it hasn't been or the time is unknown
+	AppliedAt time.Time
+}
+
+// A migration recorded as applied in the database's version table
+type AppliedMigration struct {
+	Version   int64
+	AppliedAt time.Time
+}
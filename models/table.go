@@ -0,0 +1,15 @@
+package models
+
+// A field of a table generated by `gotm add table`, parsed from arguments such as
+// `title=text:notnull:unique`
+type TableField struct {
+	Name string
+	// The type given in the command, i.e. text or int64
+	Type    string
+	NotNull bool
+	Unique  bool
+	// SQL expression used as the column's default, empty if it has none
+	Default string
+	// Table the field is a foreign key of, empty if it isn't one
+	References string
+}
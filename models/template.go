@@ -0,0 +1,17 @@
+package models
+
+// Where a template was loaded from, in order of precedence
+type TemplateSource string
+
+const (
+	PROJECT_TEMPLATE  TemplateSource = "project"
+	USER_TEMPLATE     TemplateSource = "user"
+	EMBEDDED_TEMPLATE TemplateSource = "embedded"
+)
+
+type TemplateInfo struct {
+	Name   string
+	Source TemplateSource
+	// Path of the template on disk, empty for embedded templates
+	Path string
+}
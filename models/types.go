@@ -0,0 +1,25 @@
+package models
+
+// TypeScript declarations generated from the project's Go structs
+type GeneratedTypes struct {
+	// File the declarations were written to, relative to the project root
+	Filename string
+	// Names of the declared types
+	Types []string
+	// Whether the file was written, it's left alone when the declarations haven't changed
+	Changed bool
+}
+
+// Fakes generated for the interfaces of a package
+type GeneratedMocks struct {
+	// File the fakes were written to, relative to the project root
+	Filename string
+	// Names of the interfaces fakes were generated for
+	Interfaces []string
+	// Interfaces that couldn't be faked, along with why
+	Skipped []string
+	// Whether the file was written, it's left alone when the fakes haven't changed
+	Changed bool
+	// Whether the file was deleted, as the package no longer declares any interfaces
+	Deleted bool
+}
@@ -0,0 +1,25 @@
+package models
+
+// Global, per-user configuration for gotm, stored at `$XDG_CONFIG_HOME/gotm/config.json`
+type UserConfig struct {
+	// Prefix prepended to the project name to form the module path, e.g. `gitlab.com/team`.
+	// Takes priority over Host and Owner when set
+	ModulePrefix string `json:"modulePrefix,omitempty"`
+	// Host the module is published under, defaults to `github.com`
+	Host string `json:"host,omitempty"`
+	// Owner of the module on the host, defaults to the git username
+	Owner string `json:"owner,omitempty"`
+	// Steps run after creating a project, unless overridden by flags
+	Bootstrap BootstrapConfig `json:"bootstrap"`
+}
+
+type BootstrapConfig struct {
+	// Initialise a git repository in the project
+	Git bool `json:"git"`
+	// Install the npm and go dependencies of the project
+	Install bool `json:"install"`
+	// Build the project to check it compiles
+	Build bool `json:"build"`
+	// Commit the generated project, implies Git
+	Commit bool `json:"commit"`
+}
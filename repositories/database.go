@@ -0,0 +1,211 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/danielronalds/gotm/models"
+	_ "modernc.org/sqlite"
+)
+
+// Name of the table goose tracks applied migrations in, shared so gotm and goose can be used on the
+// same database
+const GOOSE_VERSION_TABLE = "goose_db_version"
+
+// Maps the database engines of the project manifest to their sql drivers
+var databaseDrivers = map[string]string{
+	"sqlite": "sqlite",
+}
+
+// Repository for running migrations against the project's database
+type DatabaseRepository struct {
+	// Shared between copies of the repository so the connection opened is the one used
+	conn *databaseConnection
+}
+
+type databaseConnection struct {
+	mu sync.Mutex
+	db *sql.DB
+}
+
+func NewDatabaseRepository() DatabaseRepository {
+	return DatabaseRepository{&databaseConnection{}}
+}
+
+// Opens the database of the given engine at location, closing any previously opened database
+func (r DatabaseRepository) Open(engine, location string) error {
+	driver, ok := databaseDrivers[engine]
+	if !ok {
+		return fmt.Errorf("%v databases are not supported", engine)
+	}
+
+	if err := r.Close(); err != nil {
+		return err
+	}
+
+	dsn := location
+	if engine == "sqlite" {
+		dsn = fmt.Sprintf("file:%v?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)", location)
+	}
+
+	db, err := sql.Open(driver, dsn)
+	if err != nil {
+		return fmt.Errorf("unable to open database: %v", err)
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return fmt.Errorf("unable to connect to database: %v", err)
+	}
+
+	r.conn.mu.Lock()
+	r.conn.db = db
+	r.conn.mu.Unlock()
+
+	return nil
+}
+
+func (r DatabaseRepository) Close() error {
+	r.conn.mu.Lock()
+	defer r.conn.mu.Unlock()
+
+	if r.conn.db == nil {
+		return nil
+	}
+
+	err := r.conn.db.Close()
+	r.conn.db = nil
+	return err
+}
+
+func (r DatabaseRepository) database() (*sql.DB, error) {
+	r.conn.mu.Lock()
+	defer r.conn.mu.Unlock()
+
+	if r.conn.db == nil {
+		return nil, errors.New("no database has been opened")
+	}
+
+	return r.conn.db, nil
+}
+
+// Creates goose's version table if it doesn't exist yet, recording version 0 as goose does
+func (r DatabaseRepository) EnsureVersionTable() error {
+	db, err := r.database()
+	if err != nil {
+		return err
+	}
+
+	var count int
+	err = db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?", GOOSE_VERSION_TABLE).Scan(&count)
+	if err != nil {
+		return fmt.Errorf("unable to check for version table: %v", err)
+	}
+	if count != 0 {
+		return nil
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	create := fmt.Sprintf(`CREATE TABLE %v (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		version_id INTEGER NOT NULL,
+		is_applied INTEGER NOT NULL,
+		tstamp TIMESTAMP DEFAULT (datetime('now'))
+	)`, GOOSE_VERSION_TABLE)
+	if _, err := tx.Exec(create); err != nil {
+		return fmt.Errorf("unable to create version table: %v", err)
+	}
+	if _, err := tx.Exec(fmt.Sprintf("INSERT INTO %v (version_id, is_applied) VALUES (0, 1)", GOOSE_VERSION_TABLE)); err != nil {
+		return fmt.Errorf("unable to initialise version table: %v", err)
+	}
+
+	return tx.Commit()
+}
+
+// Returns the migrations recorded as applied, in the order they were applied
+func (r DatabaseRepository) AppliedMigrations() ([]models.AppliedMigration, error) {
+	db, err := r.database()
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf("SELECT version_id, CAST(tstamp AS TEXT) FROM %v WHERE version_id != 0 AND is_applied = 1 ORDER BY id", GOOSE_VERSION_TABLE)
+	rows, err := db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read applied migrations: %v", err)
+	}
+	defer rows.Close()
+
+	applied := make([]models.AppliedMigration, 0)
+	for rows.Next() {
+		var migration models.AppliedMigration
+		var timestamp sql.NullString
+		if err := rows.Scan(&migration.Version, &timestamp); err != nil {
+			return nil, fmt.Errorf("unable to read applied migrations: %v", err)
+		}
+		migration.AppliedAt = parseTimestamp(timestamp.String)
+		applied = append(applied, migration)
+	}
+
+	return applied, rows.Err()
+}
+
+// Runs the statements of a migration, recording it as applied when migrating up or removing the
+// record when migrating down. Unless useTransaction is false, everything happens in one
+// transaction so a failing migration leaves the database unchanged
+func (r DatabaseRepository) ApplyMigration(version int64, statements []string, up, useTransaction bool) error {
+	db, err := r.database()
+	if err != nil {
+		return err
+	}
+
+	record := fmt.Sprintf("INSERT INTO %v (version_id, is_applied) VALUES (?, 1)", GOOSE_VERSION_TABLE)
+	if !up {
+		record = fmt.Sprintf("DELETE FROM %v WHERE version_id = ?", GOOSE_VERSION_TABLE)
+	}
+
+	if !useTransaction {
+		for _, statement := range statements {
+			if _, err := db.Exec(statement); err != nil {
+				return fmt.Errorf("failed to run statement: %v\n%v", err, statement)
+			}
+		}
+		_, err := db.Exec(record, version)
+		return err
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, statement := range statements {
+		if _, err := tx.Exec(statement); err != nil {
+			return fmt.Errorf("failed to run statement: %v\n%v", err, statement)
+		}
+	}
+	if _, err := tx.Exec(record, version); err != nil {
+		return fmt.Errorf("unable to record migration: %v", err)
+	}
+
+	return tx.Commit()
+}
+
+// Parses the timestamps sqlite stores, returning the zero time if the format isn't recognised
+func parseTimestamp(timestamp string) time.Time {
+	for _, layout := range []string{time.DateTime, time.RFC3339Nano, "2006-01-02 15:04:05.999999999-07:00"} {
+		if parsed, err := time.Parse(layout, timestamp); err == nil {
+			return parsed
+		}
+	}
+
+	return time.Time{}
+}
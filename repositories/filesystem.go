@@ -3,51 +3,137 @@ package repositories
 import (
 	"errors"
 	"fmt"
+	"io"
 	"io/fs"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
+
+	"github.com/danielronalds/gotm/models"
 )
 
+// Returned when no project root could be found above the start directory
+var ErrNoProjectRoot = errors.New("unable to locate project root, are you sure you're in a GOTM project?")
+
 // Repository for handling filesystem operations
 type FilesystemRepository struct {
 	ignoredDirs map[string]bool
+	// Shared between copies of the repository so the root is only discovered once
+	root *projectRoot
+	// Shared between copies of the repository so the manifest is only parsed when it changes
+	manifest *projectManifest
+}
+
+// The lazily discovered root of the project
+type projectRoot struct {
+	mu       sync.Mutex
+	startDir string
+	resolved bool
+	path     string
+	err      error
 }
 
-func NewFilesystemRepository(dirsToIgnore []string) FilesystemRepository {
+// Creates a filesystem repository, where the project root is searched for starting at `startDir`
+func NewFilesystemRepository(dirsToIgnore []string, startDir string) FilesystemRepository {
 	ignoredDirs := make(map[string]bool, 0)
 	for _, dir := range dirsToIgnore {
 		ignoredDirs[dir] = true
 	}
 
-	return FilesystemRepository{ignoredDirs}
+	return FilesystemRepository{ignoredDirs, &projectRoot{startDir: startDir}, &projectManifest{}}
 }
 
-// Recursive algorithim to find the root directory of the project, in order to set the programs context
+// Finds the root directory of the project, in order to set the programs context
 //
-// Note: the project root is considred the first parent directory to contain `main.go`
-func findProjectRoot(startDir string) string {
-	entries, err := os.ReadDir(startDir)
+// Note: the project root is considered the first parent directory to contain the project manifest,
+// or for projects without one, the first parent directory to contain `main.go`. The search stops at
+// the root of the git repository or the filesystem, whichever comes first
+func findProjectRoot(startDir string) (string, error) {
+	start, err := filepath.Abs(startDir)
+	if err != nil {
+		return "", fmt.Errorf("unable to resolve %v: %v", startDir, err)
+	}
+
+	info, err := os.Stat(start)
 	if err != nil {
-		fmt.Fprintln(os.Stderr, "unable to locate project root, are you sure you're in a GOTM project?")
-		os.Exit(1)
+		return "", fmt.Errorf("unable to read %v: %v", startDir, err)
+	}
+	if !info.IsDir() {
+		return "", fmt.Errorf("%v is not a directory", startDir)
 	}
 
-	for _, entry := range entries {
-		if entry.Name() == "main.go" {
-			return startDir
+	for _, marker := range []string{models.MANIFEST_FILENAME, "main.go"} {
+		if root, ok := findDirContaining(start, marker); ok {
+			return root, nil
 		}
 	}
 
-	return findProjectRoot(fmt.Sprintf("../%v", startDir))
+	return "", ErrNoProjectRoot
+}
+
+// Walks up from dir to find the first directory containing the given file
+func findDirContaining(dir, filename string) (string, bool) {
+	for {
+		if exists(filepath.Join(dir, filename)) {
+			return dir, true
+		}
+
+		// Not searching outside of the current repository
+		if exists(filepath.Join(dir, ".git")) {
+			return "", false
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+		dir = parent
+	}
 }
 
-func (r FilesystemRepository) Root() string {
-	return findProjectRoot(".")
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
 }
 
-func (r FilesystemRepository) FromRoot(path string) string {
-	return fmt.Sprintf("%v/%v", findProjectRoot("."), strings.TrimPrefix(path, "/"))
+// Returns the absolute path of the project root
+func (r FilesystemRepository) Root() (string, error) {
+	r.root.mu.Lock()
+	defer r.root.mu.Unlock()
+
+	if !r.root.resolved {
+		r.root.path, r.root.err = findProjectRoot(r.root.startDir)
+		r.root.resolved = true
+	}
+
+	return r.root.path, r.root.err
+}
+
+// Sets the project root to dir instead of discovering it, e.g. to work in a project that has
+// just been created
+func (r FilesystemRepository) SetRoot(dir string) error {
+	root, err := filepath.Abs(dir)
+	if err != nil {
+		return fmt.Errorf("unable to resolve %v: %v", dir, err)
+	}
+
+	r.root.mu.Lock()
+	defer r.root.mu.Unlock()
+
+	r.root.path, r.root.err, r.root.resolved = root, nil, true
+
+	return nil
+}
+
+// Returns the absolute path of the given path, relative to the project root
+func (r FilesystemRepository) FromRoot(path string) (string, error) {
+	root, err := r.Root()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(root, path), nil
 }
 
 func (r FilesystemRepository) HasDirectoryOrFile(directory string) (bool, error) {
@@ -109,12 +195,17 @@ func (r FilesystemRepository) CreateDirectory(directory string) error {
 	return os.MkdirAll(directory, 0755)
 }
 
-func (r FilesystemRepository) CreateFile(filename string) (*os.File, error) {
+func (r FilesystemRepository) CreateFile(filename string) (io.WriteCloser, error) {
 	if hasFile, err := r.HasDirectoryOrFile(filename); err != nil || hasFile {
 		return nil, errors.New("file with that name already exists")
 	}
 
-	return os.Create(filename)
+	file, err := os.Create(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	return file, nil
 }
 
 func (r FilesystemRepository) DeleteFileRecursive(filename string) error {
@@ -124,3 +215,31 @@ func (r FilesystemRepository) DeleteFileRecursive(filename string) error {
 
 	return os.RemoveAll(filename)
 }
+
+// Writes contents to the file, creating it if it doesn't exist and replacing it if it does
+func (r FilesystemRepository) WriteFile(filename, contents string) error {
+	// Keeping the permissions of files that already exist
+	mode := fs.FileMode(0644)
+	if info, err := os.Stat(filename); err == nil {
+		mode = info.Mode().Perm()
+	}
+
+	return os.WriteFile(filename, []byte(contents), mode)
+}
+
+// Creates a new uniquely named directory in parent, see os.MkdirTemp for how pattern is used
+func (r FilesystemRepository) CreateTempDirectory(parent, pattern string) (string, error) {
+	return os.MkdirTemp(parent, pattern)
+}
+
+func (r FilesystemRepository) Chmod(filename string, mode fs.FileMode) error {
+	return os.Chmod(filename, mode)
+}
+
+func (r FilesystemRepository) Rename(oldpath, newpath string) error {
+	if hasFile, err := r.HasDirectoryOrFile(newpath); err != nil || hasFile {
+		return errors.New("file with that name already exists")
+	}
+
+	return os.Rename(oldpath, newpath)
+}
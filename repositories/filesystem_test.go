@@ -1,8 +1,10 @@
 package repositories
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"slices"
 	"testing"
 )
@@ -10,7 +12,7 @@ import (
 const TEST_DIR string = "testing"
 
 // Util function for removing a directory and file
-func delete(file string, t *testing.T) {
+func remove(file string, t *testing.T) {
 	if err := os.RemoveAll(file); err != nil {
 		t.Fatalf("Failed to remove directory/file: %v", err.Error())
 	}
@@ -39,8 +41,8 @@ func createFile(filename, content string, t *testing.T) {
 func TestHasDirectoryOrFileReturnsTrueIfDirectoryExists(t *testing.T) {
 	// Arrange
 	mkdir(TEST_DIR, t)
-	defer delete(TEST_DIR, t)
-	filesystem := NewFilesystemRepository([]string{})
+	defer remove(TEST_DIR, t)
+	filesystem := NewFilesystemRepository([]string{}, ".")
 
 	// Act
 	hasDir, err := filesystem.HasDirectoryOrFile(TEST_DIR)
@@ -56,7 +58,7 @@ func TestHasDirectoryOrFileReturnsTrueIfDirectoryExists(t *testing.T) {
 
 func TestHasDirectoryOrFileReturnsFalseIfDirectoryDoesntExists(t *testing.T) {
 	// Arrange
-	filesystem := NewFilesystemRepository([]string{})
+	filesystem := NewFilesystemRepository([]string{}, ".")
 
 	// Act
 	hasDir, err := filesystem.HasDirectoryOrFile("non-existent")
@@ -73,14 +75,14 @@ func TestHasDirectoryOrFileReturnsFalseIfDirectoryDoesntExists(t *testing.T) {
 func TestReadDirRecursive(t *testing.T) {
 	// Arrange
 	mkdir("testdir", t)
-	defer delete("testdir", t)
+	defer remove("testdir", t)
 	createFile("testdir/a.txt", "mock-content", t)
 	createFile("testdir/test.txt", "mock-content", t)
 	mkdir("testdir/nested", t)
 	createFile("testdir/nested/test.txt", "mock-content", t)
 	mkdir("testdir/nested/verynested", t)
 	createFile("testdir/nested/verynested/test.txt", "mock-content", t)
-	filesystem := NewFilesystemRepository([]string{})
+	filesystem := NewFilesystemRepository([]string{}, ".")
 
 	// Act
 	files, err := filesystem.ReadDirRecursive("testdir")
@@ -106,8 +108,8 @@ func TestReadFileWorks(t *testing.T) {
 	filename := "test.txt"
 	filecontent := "mock-content"
 	createFile(filename, filecontent, t)
-	defer delete(filename, t)
-	filesystem := NewFilesystemRepository([]string{})
+	defer remove(filename, t)
+	filesystem := NewFilesystemRepository([]string{}, ".")
 
 	// Act
 	content, err := filesystem.ReadFile(filename)
@@ -123,13 +125,13 @@ func TestReadFileWorks(t *testing.T) {
 
 func TestCreateDirectoryWorks(t *testing.T) {
 	// Arrange
-	filesystem := NewFilesystemRepository([]string{})
+	filesystem := NewFilesystemRepository([]string{}, ".")
 
 	// Act
 	if err := filesystem.CreateDirectory(TEST_DIR); err != nil {
 		t.Fatalf("Failed to create directory: %v", err.Error())
 	}
-	defer delete(TEST_DIR, t)
+	defer remove(TEST_DIR, t)
 
 	// Assert
 	if hasDir, err := filesystem.HasDirectoryOrFile(TEST_DIR); err != nil || !hasDir {
@@ -140,13 +142,13 @@ func TestCreateDirectoryWorks(t *testing.T) {
 func TestCreateDirCreatesRequiredParentDirectories(t *testing.T) {
 	// Arrange
 	SECOND_DIR := fmt.Sprintf("%v/test", TEST_DIR)
-	filesystem := NewFilesystemRepository([]string{})
+	filesystem := NewFilesystemRepository([]string{}, ".")
 
 	// Act
 	if err := filesystem.CreateDirectory(SECOND_DIR); err != nil {
 		t.Fatalf("Failed to create directory: %v", err.Error())
 	}
-	defer delete(TEST_DIR, t)
+	defer remove(TEST_DIR, t)
 
 	// Assert
 	if hasDir, err := filesystem.HasDirectoryOrFile(SECOND_DIR); err != nil || !hasDir {
@@ -156,17 +158,132 @@ func TestCreateDirCreatesRequiredParentDirectories(t *testing.T) {
 
 func TestCreateDirectoryReturnsExpectedErrorIfFileExists(t *testing.T) {
 	// Arrange
-	filesystem := NewFilesystemRepository([]string{})
+	filesystem := NewFilesystemRepository([]string{}, ".")
 
 	// Act
 	if err := filesystem.CreateDirectory(TEST_DIR); err != nil {
 		t.Fatalf("Failed to create directory: %v", err.Error())
 	}
 	err := filesystem.CreateDirectory(TEST_DIR)
-	defer delete(TEST_DIR, t)
+	defer remove(TEST_DIR, t)
 
 	// Assert
 	if err.Error() != "directory with that name already exists" {
 		t.Fatalf("error did not return with expected content: %v", err.Error())
 	}
 }
+
+func TestManifestFillsInDefaultsForMissingFields(t *testing.T) {
+	// Arrange
+	createFile("gotm.json", `{"name": "app", "frontend": "web", "main": "cmd/server"}`, t)
+	defer remove("gotm.json", t)
+	filesystem := NewFilesystemRepository([]string{}, ".")
+
+	// Act
+	manifest, err := filesystem.Manifest()
+	if err != nil {
+		t.Fatalf("Failed to read manifest: %v", err)
+	}
+
+	// Assert
+	if manifest.Main != "cmd/server" || manifest.Frontend != "web" {
+		t.Fatalf("Manifest did not keep specified fields: %+v", manifest)
+	}
+	if manifest.Directories.Pages != "web/src/views/pages" {
+		t.Fatalf("Expected pages to follow the frontend directory, got %v", manifest.Directories.Pages)
+	}
+	if manifest.Directories.Controllers != "controllers" || manifest.DevBinary != ".main.tmp" || manifest.Port != 3000 {
+		t.Fatalf("Manifest did not fill in defaults: %+v", manifest)
+	}
+}
+
+func TestManifestIsOnlyReloadedWhenItChanges(t *testing.T) {
+	// Arrange
+	root := t.TempDir()
+	createFile(filepath.Join(root, "gotm.json"), `{"name": "app"}`, t)
+	filesystem := NewFilesystemRepository([]string{}, root)
+	if _, err := filesystem.Manifest(); err != nil {
+		t.Fatalf("Failed to read manifest: %v", err)
+	}
+	filesystem.manifest.manifest.Name = "cached"
+
+	// Act
+	cached, err := filesystem.Manifest()
+	if err != nil {
+		t.Fatalf("Failed to read manifest: %v", err)
+	}
+	createFile(filepath.Join(root, "gotm.json"), `{"name": "changed"}`, t)
+	changed, err := filesystem.Manifest()
+	if err != nil {
+		t.Fatalf("Failed to read manifest: %v", err)
+	}
+
+	// Assert
+	if cached.Name != "cached" {
+		t.Fatalf("Expected the cached manifest to be used, got %v", cached.Name)
+	}
+	if changed.Name != "changed" {
+		t.Fatalf("Expected the manifest to be reloaded after changing, got %v", changed.Name)
+	}
+}
+
+func TestRootPrefersManifestOverNearerMainFile(t *testing.T) {
+	// Arrange
+	root := t.TempDir()
+	mainDir := filepath.Join(root, "cmd", "server")
+	if err := os.MkdirAll(mainDir, 0755); err != nil {
+		t.Fatalf("Failed to create test directory: %v", err.Error())
+	}
+	createFile(filepath.Join(root, "gotm.json"), "{}", t)
+	createFile(filepath.Join(mainDir, "main.go"), "package main", t)
+	filesystem := NewFilesystemRepository([]string{}, mainDir)
+
+	// Act
+	result, err := filesystem.Root()
+	if err != nil {
+		t.Fatalf("Failed to find root: %v", err)
+	}
+
+	// Assert
+	if result != root {
+		t.Fatalf("Expected %v, got %v", root, result)
+	}
+}
+
+func TestRootStopsAtGitRepositoryBoundary(t *testing.T) {
+	// Arrange
+	outer := t.TempDir()
+	startDir := filepath.Join(outer, "repo", "sub")
+	if err := os.MkdirAll(filepath.Join(outer, "repo", ".git"), 0755); err != nil {
+		t.Fatalf("Failed to create test directory: %v", err.Error())
+	}
+	mkdir(startDir, t)
+	createFile(filepath.Join(outer, "main.go"), "package main", t)
+	filesystem := NewFilesystemRepository([]string{}, startDir)
+
+	// Act
+	_, err := filesystem.Root()
+
+	// Assert
+	if !errors.Is(err, ErrNoProjectRoot) {
+		t.Fatalf("Expected ErrNoProjectRoot, got %v", err)
+	}
+}
+
+func TestFromRootReturnsAbsolutePaths(t *testing.T) {
+	// Arrange
+	root := t.TempDir()
+	createFile(filepath.Join(root, "main.go"), "package main", t)
+	filesystem := NewFilesystemRepository([]string{}, root)
+
+	// Act
+	result, err := filesystem.FromRoot("/frontend/../frontend")
+	if err != nil {
+		t.Fatalf("Failed to find root: %v", err)
+	}
+
+	// Assert
+	if result != filepath.Join(root, "frontend") {
+		t.Fatalf("Expected %v, got %v", filepath.Join(root, "frontend"), result)
+	}
+}
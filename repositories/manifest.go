@@ -0,0 +1,71 @@
+package repositories
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/danielronalds/gotm/models"
+)
+
+// The last manifest read, along with what gotm.json looked like when it was read
+type projectManifest struct {
+	mu       sync.Mutex
+	loaded   bool
+	path     string
+	modTime  time.Time
+	size     int64
+	manifest models.Manifest
+}
+
+// Reads the manifest of the current project. Projects created before manifests existed are
+// assumed to use the default layout. The parsed manifest is cached, and only read again once
+// gotm.json has changed, as the watcher asks for it on every tick
+func (r FilesystemRepository) Manifest() (models.Manifest, error) {
+	root, err := r.Root()
+	if err != nil {
+		return models.Manifest{}, err
+	}
+
+	path := filepath.Join(root, models.MANIFEST_FILENAME)
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return models.DefaultManifest(filepath.Base(root), ""), nil
+	}
+	if err != nil {
+		return models.Manifest{}, fmt.Errorf("unable to read %v: %v", models.MANIFEST_FILENAME, err)
+	}
+
+	r.manifest.mu.Lock()
+	defer r.manifest.mu.Unlock()
+
+	cached := r.manifest
+	if cached.loaded && cached.path == path && cached.modTime.Equal(info.ModTime()) && cached.size == info.Size() {
+		return cached.manifest, nil
+	}
+
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return models.Manifest{}, fmt.Errorf("unable to read %v: %v", models.MANIFEST_FILENAME, err)
+	}
+
+	manifest, err := parseManifest(contents)
+	if err != nil {
+		return models.Manifest{}, err
+	}
+
+	cached.loaded, cached.path, cached.modTime, cached.size, cached.manifest = true, path, info.ModTime(), info.Size(), manifest
+	return manifest, nil
+}
+
+func parseManifest(contents []byte) (models.Manifest, error) {
+	manifest := models.Manifest{}
+	if err := json.Unmarshal(contents, &manifest); err != nil {
+		return models.Manifest{}, fmt.Errorf("unable to parse %v: %v", models.MANIFEST_FILENAME, err)
+	}
+
+	return manifest.WithDefaults(), nil
+}
@@ -0,0 +1,430 @@
+package repositories
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/danielronalds/gotm/models"
+)
+
+// The filesystem reads fall through to for paths that haven't been touched in memory
+type underlyingFilesystem interface {
+	Root() (string, error)
+	HasDirectoryOrFile(directory string) (bool, error)
+	ReadDirRecursive(directory string) ([]string, error)
+	ReadFile(filename string) (string, error)
+}
+
+// Repository implementing the filesystem operations in memory. When created over another
+// filesystem, reads fall through to it for anything that hasn't been written in memory, so that
+// changes can be previewed against a real project without touching the disk
+type MemoryFilesystemRepository struct {
+	state *memoryState
+	base  underlyingFilesystem
+	root  string
+}
+
+type memoryState struct {
+	mu    sync.Mutex
+	dirs  map[string]bool
+	files map[string]*bytes.Buffer
+	// Paths from the underlying filesystem that have been deleted
+	deleted  map[string]bool
+	tempDirs int
+}
+
+func newMemoryState() *memoryState {
+	return &memoryState{dirs: make(map[string]bool), files: make(map[string]*bytes.Buffer), deleted: make(map[string]bool)}
+}
+
+// Creates an empty in-memory filesystem, with a project rooted at root. The root, the current
+// directory and all of their parents are considered to exist
+func NewMemoryFilesystemRepository(root string) MemoryFilesystemRepository {
+	r := MemoryFilesystemRepository{state: newMemoryState(), root: filepath.Clean(root)}
+
+	cwd, _ := filepath.Abs(".")
+	for _, dir := range []string{r.root, cwd} {
+		for ; ; dir = filepath.Dir(dir) {
+			r.state.dirs[dir] = true
+			if filepath.Dir(dir) == dir {
+				break
+			}
+		}
+	}
+
+	return r
+}
+
+// Creates an in-memory filesystem layered over base, which is never written to
+func NewOverlayFilesystemRepository(base FilesystemRepository) MemoryFilesystemRepository {
+	return MemoryFilesystemRepository{state: newMemoryState(), base: base}
+}
+
+func normalisePath(path string) string {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return filepath.Clean(path)
+	}
+	return abs
+}
+
+func (r MemoryFilesystemRepository) Root() (string, error) {
+	if r.base != nil {
+		return r.base.Root()
+	}
+	return r.root, nil
+}
+
+func (r MemoryFilesystemRepository) FromRoot(path string) (string, error) {
+	root, err := r.Root()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(root, path), nil
+}
+
+func (r MemoryFilesystemRepository) Manifest() (models.Manifest, error) {
+	root, err := r.Root()
+	if err != nil {
+		return models.Manifest{}, err
+	}
+
+	manifestPath := filepath.Join(root, models.MANIFEST_FILENAME)
+	hasManifest, err := r.HasDirectoryOrFile(manifestPath)
+	if err != nil {
+		return models.Manifest{}, err
+	}
+	if !hasManifest {
+		return models.DefaultManifest(filepath.Base(root), ""), nil
+	}
+
+	contents, err := r.ReadFile(manifestPath)
+	if err != nil {
+		return models.Manifest{}, fmt.Errorf("unable to read %v: %v", models.MANIFEST_FILENAME, err)
+	}
+
+	return parseManifest([]byte(contents))
+}
+
+// Whether the path exists, must be called with the lock held
+func (r MemoryFilesystemRepository) exists(path string) (bool, error) {
+	if r.state.dirs[path] || r.state.files[path] != nil {
+		return true, nil
+	}
+
+	if r.base == nil || r.isDeleted(path) {
+		return false, nil
+	}
+
+	return r.base.HasDirectoryOrFile(path)
+}
+
+// Whether the path, or one of its parents, has been deleted. Must be called with the lock held
+func (r MemoryFilesystemRepository) isDeleted(path string) bool {
+	for dir := path; ; dir = filepath.Dir(dir) {
+		if r.state.deleted[dir] {
+			return true
+		}
+		if filepath.Dir(dir) == dir {
+			return false
+		}
+	}
+}
+
+func (r MemoryFilesystemRepository) HasDirectoryOrFile(directory string) (bool, error) {
+	r.state.mu.Lock()
+	defer r.state.mu.Unlock()
+
+	return r.exists(normalisePath(directory))
+}
+
+func (r MemoryFilesystemRepository) ReadDirRecursive(directory string) ([]string, error) {
+	r.state.mu.Lock()
+	defer r.state.mu.Unlock()
+
+	dir := normalisePath(directory)
+	files := make([]string, 0)
+
+	if r.base != nil {
+		baseFiles, err := r.base.ReadDirRecursive(directory)
+		if err != nil {
+			return files, err
+		}
+		for _, file := range baseFiles {
+			if !r.isDeleted(normalisePath(file)) && r.state.files[normalisePath(file)] == nil {
+				files = append(files, file)
+			}
+		}
+	}
+
+	for path := range r.state.files {
+		if isWithin(dir, path) {
+			rel, _ := filepath.Rel(dir, path)
+			files = append(files, filepath.Join(directory, rel))
+		}
+	}
+
+	slices.Sort(files)
+
+	return files, nil
+}
+
+func (r MemoryFilesystemRepository) ReadFile(filename string) (string, error) {
+	r.state.mu.Lock()
+	defer r.state.mu.Unlock()
+
+	path := normalisePath(filename)
+	if file := r.state.files[path]; file != nil {
+		return file.String(), nil
+	}
+
+	if r.base == nil || r.isDeleted(path) {
+		return "", nil // Matching the real filesystem, missing files are read as empty
+	}
+
+	return r.base.ReadFile(filename)
+}
+
+// Marks the directory and any missing parents as existing, must be called with the lock held
+func (r MemoryFilesystemRepository) mkdirAll(dir string) error {
+	for ; ; dir = filepath.Dir(dir) {
+		exists, err := r.exists(dir)
+		if err != nil {
+			return err
+		}
+		if exists {
+			return nil
+		}
+
+		r.state.dirs[dir] = true
+		delete(r.state.deleted, dir)
+	}
+}
+
+func (r MemoryFilesystemRepository) CreateDirectory(directory string) error {
+	r.state.mu.Lock()
+	defer r.state.mu.Unlock()
+
+	dir := normalisePath(directory)
+	if exists, err := r.exists(dir); err != nil || exists {
+		return errors.New("directory with that name already exists")
+	}
+
+	return r.mkdirAll(dir)
+}
+
+// Checks the parent of path exists, must be called with the lock held
+func (r MemoryFilesystemRepository) checkParent(path string) error {
+	exists, err := r.exists(filepath.Dir(path))
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("%v does not exist", filepath.Dir(path))
+	}
+
+	return nil
+}
+
+// A file being written to the in-memory filesystem
+type memoryFile struct {
+	state  *memoryState
+	buffer *bytes.Buffer
+}
+
+func (f memoryFile) Write(p []byte) (int, error) {
+	f.state.mu.Lock()
+	defer f.state.mu.Unlock()
+
+	return f.buffer.Write(p)
+}
+
+func (f memoryFile) Close() error {
+	return nil
+}
+
+func (r MemoryFilesystemRepository) CreateFile(filename string) (io.WriteCloser, error) {
+	r.state.mu.Lock()
+	defer r.state.mu.Unlock()
+
+	path := normalisePath(filename)
+	if exists, err := r.exists(path); err != nil || exists {
+		return nil, errors.New("file with that name already exists")
+	}
+	if err := r.checkParent(path); err != nil {
+		return nil, err
+	}
+
+	buffer := &bytes.Buffer{}
+	r.state.files[path] = buffer
+	delete(r.state.deleted, path)
+
+	return memoryFile{r.state, buffer}, nil
+}
+
+func (r MemoryFilesystemRepository) WriteFile(filename, contents string) error {
+	r.state.mu.Lock()
+	defer r.state.mu.Unlock()
+
+	path := normalisePath(filename)
+	if r.state.dirs[path] {
+		return fmt.Errorf("%v is a directory", filename)
+	}
+	if err := r.checkParent(path); err != nil {
+		return err
+	}
+
+	r.state.files[path] = bytes.NewBufferString(contents)
+	delete(r.state.deleted, path)
+
+	return nil
+}
+
+func (r MemoryFilesystemRepository) DeleteFileRecursive(filename string) error {
+	r.state.mu.Lock()
+	defer r.state.mu.Unlock()
+
+	path := normalisePath(filename)
+	if exists, err := r.exists(path); err != nil || !exists {
+		return errors.New("file with that name does not exist")
+	}
+
+	createdInMemory := r.state.dirs[path] || r.state.files[path] != nil
+	r.removeTree(path)
+
+	if r.base != nil && !createdInMemory {
+		r.state.deleted[path] = true
+	}
+
+	return nil
+}
+
+// Removes the path and everything under it from memory, must be called with the lock held
+func (r MemoryFilesystemRepository) removeTree(path string) {
+	for dir := range r.state.dirs {
+		if isWithin(path, dir) {
+			delete(r.state.dirs, dir)
+		}
+	}
+	for file := range r.state.files {
+		if isWithin(path, file) {
+			delete(r.state.files, file)
+		}
+	}
+}
+
+func isWithin(dir, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, "../")
+}
+
+func (r MemoryFilesystemRepository) CreateTempDirectory(parent, pattern string) (string, error) {
+	r.state.mu.Lock()
+	defer r.state.mu.Unlock()
+
+	parentPath := normalisePath(parent)
+	if exists, err := r.exists(parentPath); err != nil || !exists {
+		return "", fmt.Errorf("%v does not exist", parent)
+	}
+
+	for {
+		r.state.tempDirs++
+		name := strings.Replace(pattern, "*", fmt.Sprint(r.state.tempDirs), 1)
+		if !strings.Contains(pattern, "*") {
+			name = fmt.Sprintf("%v%v", pattern, r.state.tempDirs)
+		}
+
+		dir := filepath.Join(parentPath, name)
+		if exists, err := r.exists(dir); err != nil {
+			return "", err
+		} else if !exists {
+			r.state.dirs[dir] = true
+			return dir, nil
+		}
+	}
+}
+
+// Checks the file exists, as permissions aren't tracked in memory
+func (r MemoryFilesystemRepository) Chmod(filename string, mode fs.FileMode) error {
+	r.state.mu.Lock()
+	defer r.state.mu.Unlock()
+
+	if exists, err := r.exists(normalisePath(filename)); err != nil || !exists {
+		return fmt.Errorf("%v does not exist", filename)
+	}
+
+	return nil
+}
+
+// Renames a file or directory. Only paths that were created in memory can be renamed
+func (r MemoryFilesystemRepository) Rename(oldpath, newpath string) error {
+	r.state.mu.Lock()
+	defer r.state.mu.Unlock()
+
+	from, to := normalisePath(oldpath), normalisePath(newpath)
+	if exists, err := r.exists(to); err != nil || exists {
+		return errors.New("file with that name already exists")
+	}
+	if !r.state.dirs[from] && r.state.files[from] == nil {
+		return fmt.Errorf("%v was not created in memory", oldpath)
+	}
+	if err := r.checkParent(to); err != nil {
+		return err
+	}
+
+	for dir := range r.state.dirs {
+		if isWithin(from, dir) {
+			delete(r.state.dirs, dir)
+			r.state.dirs[to+strings.TrimPrefix(dir, from)] = true
+		}
+	}
+	for file, contents := range r.state.files {
+		if isWithin(from, file) {
+			delete(r.state.files, file)
+			r.state.files[to+strings.TrimPrefix(file, from)] = contents
+		}
+	}
+	delete(r.state.deleted, to)
+
+	return nil
+}
+
+// Returns every change made in memory, sorted by path
+func (r MemoryFilesystemRepository) Changes() []models.FileChange {
+	r.state.mu.Lock()
+	defer r.state.mu.Unlock()
+
+	changes := make([]models.FileChange, 0)
+
+	for dir := range r.state.dirs {
+		// Directories that exist outside of the memory filesystem weren't created by gotm
+		if r.base == nil && (isWithin(dir, r.root) || isWithin(dir, normalisePath("."))) {
+			continue
+		}
+		changes = append(changes, models.FileChange{Path: dir, IsDir: true})
+	}
+	for file, contents := range r.state.files {
+		modified := false
+		if r.base != nil {
+			modified, _ = r.base.HasDirectoryOrFile(file)
+		}
+		changes = append(changes, models.FileChange{Path: file, Modified: modified, Contents: contents.String()})
+	}
+	for path := range r.state.deleted {
+		changes = append(changes, models.FileChange{Path: path, Deleted: true})
+	}
+
+	slices.SortFunc(changes, func(a, b models.FileChange) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+
+	return changes
+}
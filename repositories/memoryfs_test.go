@@ -0,0 +1,61 @@
+package repositories
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestOverlayFilesystemReadsThroughWithoutWriting(t *testing.T) {
+	// Arrange
+	root := t.TempDir()
+	createFile(filepath.Join(root, "main.go"), "package main", t)
+	overlay := NewOverlayFilesystemRepository(NewFilesystemRepository([]string{}, root))
+
+	// Act
+	if err := overlay.CreateDirectory(filepath.Join(root, "services")); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	file, err := overlay.CreateFile(filepath.Join(root, "services", "book.go"))
+	if err != nil {
+		t.Fatalf("Failed to create file: %v", err)
+	}
+	file.Write([]byte("package services"))
+	file.Close()
+	_, mainErr := overlay.CreateFile(filepath.Join(root, "main.go"))
+
+	// Assert
+	if mainErr == nil {
+		t.Fatal("Expected creating a file that exists on disk to fail")
+	}
+	if contents, _ := overlay.ReadFile(filepath.Join(root, "services", "book.go")); contents != "package services" {
+		t.Fatalf("Expected to read back written contents, got %v", contents)
+	}
+	if _, err := os.Stat(filepath.Join(root, "services")); !os.IsNotExist(err) {
+		t.Fatal("Expected nothing to be written to disk")
+	}
+	changes := overlay.Changes()
+	if len(changes) != 2 || changes[1].Path != filepath.Join(root, "services", "book.go") {
+		t.Fatalf("Unexpected changes: %+v", changes)
+	}
+}
+
+func TestMemoryFilesystemRenameMovesNestedFiles(t *testing.T) {
+	// Arrange
+	filesystem := NewMemoryFilesystemRepository("/project")
+	filesystem.CreateDirectory("/project/staging/src")
+	filesystem.CreateFile("/project/staging/src/index.ts")
+
+	// Act
+	if err := filesystem.Rename("/project/staging", "/project/app"); err != nil {
+		t.Fatalf("Failed to rename: %v", err)
+	}
+
+	// Assert
+	if hasFile, _ := filesystem.HasDirectoryOrFile("/project/app/src/index.ts"); !hasFile {
+		t.Fatal("Expected nested file to be moved")
+	}
+	if hasDir, _ := filesystem.HasDirectoryOrFile("/project/staging"); hasDir {
+		t.Fatal("Expected old directory to no longer exist")
+	}
+}
@@ -3,6 +3,7 @@ package repositories
 import (
 	"os"
 	"os/exec"
+	"strings"
 )
 
 type ShellRepository struct{}
@@ -31,3 +32,16 @@ func (r ShellRepository) RunCmdWithPipedOutput(dir, program string, args ...stri
 
 	return os.Chdir(workdir)
 }
+
+// Runs the given command in dir, returning its trimmed stdout
+func (r ShellRepository) RunCmdWithOutput(dir, program string, args ...string) (string, error) {
+	cmd := exec.Command(program, args...)
+	cmd.Dir = dir
+
+	output, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
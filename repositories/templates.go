@@ -2,18 +2,36 @@ package repositories
 
 import (
 	"embed"
+	"fmt"
 	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
 	"text/template"
+
+	"github.com/danielronalds/gotm/models"
 )
 
 //go:embed all:templates/*
 var templateFS embed.FS
 
+// Directory, relative to the project root, containing project specific template overrides
+const PROJECT_TEMPLATES_DIR = ".gotm/templates"
+
+// Repository for rendering templates. Templates are looked up in the projects `.gotm/templates/`
+// directory, then the users `~/.config/gotm/templates/` directory, before falling back to the
+// templates embedded in gotm
 type TemplatesRepository struct {
 	templates *template.Template
+	// Maps the name of each embedded template to its path in the embedded filesystem
+	embeddedPaths map[string]string
+	filesystem    FilesystemRepository
+	userConfig    UserConfigRepository
 }
 
-func NewTemplatesRepository() TemplatesRepository {
+func NewTemplatesRepository(filesystem FilesystemRepository, userConfig UserConfigRepository) TemplatesRepository {
 	templates, err := template.New("").ParseFS(templateFS,
 		"templates/*.tmpl",
 		// Initial project stuff
@@ -29,9 +47,151 @@ func NewTemplatesRepository() TemplatesRepository {
 		panic(err)
 	}
 
-	return TemplatesRepository{templates}
+	embeddedPaths := make(map[string]string)
+	err = fs.WalkDir(templateFS, "templates", func(p string, d fs.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			embeddedPaths[path.Base(p)] = p
+		}
+		return err
+	})
+
+	if err != nil {
+		panic(err)
+	}
+
+	return TemplatesRepository{templates, embeddedPaths, filesystem, userConfig}
+}
+
+// Returns the directory overrides of the given source are stored in
+func (r TemplatesRepository) TemplateOverrideDir(source models.TemplateSource) (string, error) {
+	switch source {
+	case models.PROJECT_TEMPLATE:
+		return r.filesystem.FromRoot(PROJECT_TEMPLATES_DIR)
+	case models.USER_TEMPLATE:
+		dir, err := r.userConfig.ConfigDir()
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(dir, "templates"), nil
+	}
+
+	return "", fmt.Errorf("%v templates cannot be overridden", source)
+}
+
+// Returns the override directories that are available, in order of precedence. The project
+// directory is skipped when not inside a project, e.g. when creating one
+func (r TemplatesRepository) overrideDirs() []models.TemplateInfo {
+	dirs := make([]models.TemplateInfo, 0, 2)
+
+	for _, source := range []models.TemplateSource{models.PROJECT_TEMPLATE, models.USER_TEMPLATE} {
+		if dir, err := r.TemplateOverrideDir(source); err == nil {
+			dirs = append(dirs, models.TemplateInfo{Source: source, Path: dir})
+		}
+	}
+
+	return dirs
+}
+
+// Finds the template that will be used for the given name
+func (r TemplatesRepository) findTemplate(name string) (models.TemplateInfo, error) {
+	for _, dir := range r.overrideDirs() {
+		templatePath := filepath.Join(dir.Path, name)
+		if _, err := os.Stat(templatePath); err == nil {
+			return models.TemplateInfo{Name: name, Source: dir.Source, Path: templatePath}, nil
+		}
+	}
+
+	if _, ok := r.embeddedPaths[name]; ok {
+		return models.TemplateInfo{Name: name, Source: models.EMBEDDED_TEMPLATE}, nil
+	}
+
+	return models.TemplateInfo{}, fmt.Errorf("no template named \"%v\"", name)
 }
 
 func (r TemplatesRepository) WriteTemplate(wr io.Writer, name string, data any) error {
-	return r.templates.ExecuteTemplate(wr, name, data)
+	info, err := r.findTemplate(name)
+	if err != nil {
+		return err
+	}
+
+	if info.Source == models.EMBEDDED_TEMPLATE {
+		return r.templates.ExecuteTemplate(wr, name, data)
+	}
+
+	override, err := template.New(name).ParseFiles(info.Path)
+	if err != nil {
+		return fmt.Errorf("unable to parse %v template override: %v", info.Source, err)
+	}
+
+	return override.ExecuteTemplate(wr, name, data)
+}
+
+// Lists every available template, along with where it will be loaded from
+func (r TemplatesRepository) ListTemplates() ([]models.TemplateInfo, error) {
+	names := make([]string, 0, len(r.embeddedPaths))
+	for name := range r.embeddedPaths {
+		names = append(names, name)
+	}
+
+	// Overrides may add templates that don't exist in gotm, e.g. for the user to use in another override
+	for _, dir := range r.overrideDirs() {
+		entries, err := os.ReadDir(dir.Path)
+		if err != nil {
+			continue
+		}
+
+		for _, entry := range entries {
+			if !entry.IsDir() && !slices.Contains(names, entry.Name()) {
+				names = append(names, entry.Name())
+			}
+		}
+	}
+
+	slices.Sort(names)
+
+	templates := make([]models.TemplateInfo, 0, len(names))
+	for _, name := range names {
+		info, err := r.findTemplate(name)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, info)
+	}
+
+	return templates, nil
+}
+
+// Returns the source of the template that will be used for the given name
+func (r TemplatesRepository) TemplateSource(name string) (models.TemplateInfo, string, error) {
+	info, err := r.findTemplate(name)
+	if err != nil {
+		return info, "", err
+	}
+
+	if info.Source == models.EMBEDDED_TEMPLATE {
+		source, err := r.EmbeddedTemplateSource(name)
+		return info, source, err
+	}
+
+	contents, err := os.ReadFile(info.Path)
+	if err != nil {
+		return info, "", err
+	}
+
+	return info, string(contents), nil
+}
+
+// Returns the source of the template embedded in gotm, ignoring any overrides
+func (r TemplatesRepository) EmbeddedTemplateSource(name string) (string, error) {
+	embeddedPath, ok := r.embeddedPaths[name]
+	if !ok {
+		return "", fmt.Errorf("no built in template named \"%v\"", name)
+	}
+
+	contents, err := templateFS.ReadFile(embeddedPath)
+	if err != nil {
+		return "", err
+	}
+
+	return string(contents), nil
 }
@@ -0,0 +1,98 @@
+package repositories
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/danielronalds/gotm/models"
+)
+
+// Creates a project with a manifest in a temporary directory, pointing the user config there too
+func setupTemplatesRepository(t *testing.T) (TemplatesRepository, string, string) {
+	root := t.TempDir()
+	createFile(filepath.Join(root, "gotm.json"), "{}", t)
+
+	userDir := t.TempDir()
+	t.Setenv(USER_CONFIG_ENV, filepath.Join(userDir, "config.json"))
+
+	filesystem := NewFilesystemRepository([]string{}, root)
+	return NewTemplatesRepository(filesystem, NewUserConfigRepository()), root, userDir
+}
+
+func TestWriteTemplateUsesEmbeddedTemplateWithoutOverrides(t *testing.T) {
+	// Arrange
+	templates, _, _ := setupTemplatesRepository(t)
+	var output bytes.Buffer
+
+	// Act
+	if err := templates.WriteTemplate(&output, "service.go.tmpl", struct {
+		Name, Package string
+		Dependencies  []struct{}
+		Imports       []string
+	}{"Book", "services", nil, nil}); err != nil {
+		t.Fatalf("Failed to write template: %v", err)
+	}
+
+	// Assert
+	if !bytes.Contains(output.Bytes(), []byte("type BookService struct{}")) {
+		t.Fatalf("Unexpected output: %v", output.String())
+	}
+}
+
+func TestWriteTemplatePrefersProjectOverUserOverride(t *testing.T) {
+	// Arrange
+	templates, root, userDir := setupTemplatesRepository(t)
+	if err := os.MkdirAll(filepath.Join(root, PROJECT_TEMPLATES_DIR), 0755); err != nil {
+		t.Fatalf("Failed to create test directory: %v", err.Error())
+	}
+	mkdir(filepath.Join(userDir, "templates"), t)
+	createFile(filepath.Join(root, PROJECT_TEMPLATES_DIR, "service.go.tmpl"), "project {{ .Name }}", t)
+	createFile(filepath.Join(userDir, "templates", "service.go.tmpl"), "user {{ .Name }}", t)
+	createFile(filepath.Join(userDir, "templates", "model.ts.tmpl"), "user {{ .Name }}", t)
+	var serviceOutput, modelOutput bytes.Buffer
+
+	// Act
+	if err := templates.WriteTemplate(&serviceOutput, "service.go.tmpl", struct{ Name string }{"Book"}); err != nil {
+		t.Fatalf("Failed to write template: %v", err)
+	}
+	if err := templates.WriteTemplate(&modelOutput, "model.ts.tmpl", struct{ Name string }{"Book"}); err != nil {
+		t.Fatalf("Failed to write template: %v", err)
+	}
+
+	// Assert
+	if serviceOutput.String() != "project Book" {
+		t.Fatalf("Expected project override, got %v", serviceOutput.String())
+	}
+	if modelOutput.String() != "user Book" {
+		t.Fatalf("Expected user override, got %v", modelOutput.String())
+	}
+}
+
+func TestListTemplatesReportsSources(t *testing.T) {
+	// Arrange
+	templates, root, _ := setupTemplatesRepository(t)
+	if err := os.MkdirAll(filepath.Join(root, PROJECT_TEMPLATES_DIR), 0755); err != nil {
+		t.Fatalf("Failed to create test directory: %v", err.Error())
+	}
+	createFile(filepath.Join(root, PROJECT_TEMPLATES_DIR, "controller.go.tmpl"), "override", t)
+
+	// Act
+	list, err := templates.ListTemplates()
+	if err != nil {
+		t.Fatalf("Failed to list templates: %v", err)
+	}
+
+	// Assert
+	sources := make(map[string]models.TemplateSource)
+	for _, info := range list {
+		sources[info.Name] = info.Source
+	}
+	if sources["controller.go.tmpl"] != models.PROJECT_TEMPLATE {
+		t.Fatalf("Expected controller template to be overridden, got %v", sources["controller.go.tmpl"])
+	}
+	if sources["main.go.tmpl"] != models.EMBEDDED_TEMPLATE {
+		t.Fatalf("Expected main template to be embedded, got %v", sources["main.go.tmpl"])
+	}
+}
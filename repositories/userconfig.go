@@ -0,0 +1,72 @@
+package repositories
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/danielronalds/gotm/models"
+)
+
+// Environment variable that can be used to point gotm at a different config file
+const USER_CONFIG_ENV = "GOTM_CONFIG"
+
+// Repository for reading the global user config
+type UserConfigRepository struct{}
+
+func NewUserConfigRepository() UserConfigRepository {
+	return UserConfigRepository{}
+}
+
+// Returns the directory the user config and template overrides live in, i.e. ~/.config/gotm, or
+// the directory of the config file GOTM_CONFIG points at. The directory may not exist
+func (r UserConfigRepository) ConfigDir() (string, error) {
+	if path := os.Getenv(USER_CONFIG_ENV); path != "" {
+		return filepath.Dir(path), nil
+	}
+
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(configDir, "gotm"), nil
+}
+
+func (r UserConfigRepository) configPath() (string, error) {
+	if path := os.Getenv(USER_CONFIG_ENV); path != "" {
+		return path, nil
+	}
+
+	dir, err := r.ConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "config.json"), nil
+}
+
+// Reads the user config, returning an empty config if the user has not created one
+func (r UserConfigRepository) UserConfig() (models.UserConfig, error) {
+	config := models.UserConfig{}
+
+	path, err := r.configPath()
+	if err != nil {
+		return config, nil // No config directory means there's no config to read
+	}
+
+	contents, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return config, nil
+	}
+	if err != nil {
+		return config, fmt.Errorf("unable to read %v: %v", path, err)
+	}
+
+	if err := json.Unmarshal(contents, &config); err != nil {
+		return config, fmt.Errorf("unable to parse %v: %v", path, err)
+	}
+
+	return config, nil
+}
@@ -0,0 +1,294 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/danielronalds/gotm/models"
+)
+
+// File added to the main package to register controllers when the project already has a main.go
+const ADOPTED_ROUTES_FILE = "routes.go"
+
+// Entries every gotm project needs in its .gitignore
+var requiredGitignoreEntries = []string{"node_modules", "dist", "build", ".main.tmp"}
+
+// Adds gotm to an existing Go or frontend project in projectDir, only creating the files that are
+// missing. An existing go.mod has its module path reused, and an existing package.json and
+// .gitignore are merged with what gotm requires rather than overwritten. Like InitProject, new
+// files are staged first and everything is undone if any step fails
+func (s InitialiserService) AdoptProject(ctx context.Context, manifest models.Manifest, projectDir string) (models.AdoptionReport, error) {
+	report := models.AdoptionReport{}
+
+	if exists, err := s.exists(projectDir, models.MANIFEST_FILENAME); err != nil || exists {
+		return report, fmt.Errorf("%v already exists, this is already a gotm project", models.MANIFEST_FILENAME)
+	}
+
+	goMod, err := s.readExisting(projectDir, "go.mod")
+	if err != nil {
+		return report, err
+	}
+	if goMod != "" {
+		if modulePath := modulePathFromGoMod(goMod); modulePath != "" {
+			manifest.Module = modulePath
+		}
+	}
+
+	config := newProjectTemplateConfig(manifest)
+
+	// Files to render into the staging directory, mapped to the template they're rendered from
+	toCreate := make(map[string]string)
+	// Existing files to overwrite with merged contents once everything else is in place
+	toMerge := make(map[string]string)
+
+	for _, file := range projectFiles {
+		existing, err := s.exists(projectDir, file)
+		if err != nil {
+			return report, err
+		}
+		if !existing {
+			toCreate[file] = fmt.Sprintf("%v.tmpl", filepath.Base(file))
+			continue
+		}
+
+		switch file {
+		case ".gitignore":
+			contents, err := s.readExisting(projectDir, file)
+			if err != nil {
+				return report, err
+			}
+			if merged := mergeGitignore(contents, requiredGitignoreEntries); merged != contents {
+				toMerge[file] = merged
+			} else {
+				report.Skipped = append(report.Skipped, file)
+			}
+		case "frontend/package.json":
+			merged, conflicts, err := s.mergedPackageJson(projectDir, file, config)
+			if err != nil {
+				return report, err
+			}
+			toMerge[file] = merged
+			report.Notes = append(report.Notes, conflicts...)
+		case "main.go":
+			report.Skipped = append(report.Skipped, file)
+			if note, err := s.adoptMainFile(projectDir, toCreate); err != nil {
+				return report, err
+			} else if note != "" {
+				report.Notes = append(report.Notes, note)
+			}
+		default:
+			report.Skipped = append(report.Skipped, file)
+		}
+	}
+
+	// The lock file of gotm's package.json would be wrong for a merged one, npm will regenerate it
+	if _, merged := toMerge["frontend/package.json"]; merged {
+		delete(toCreate, "frontend/package-lock.json")
+		report.Skipped = append(report.Skipped, "frontend/package-lock.json")
+		report.Notes = append(report.Notes, "run `gotm install` to install the merged frontend dependencies")
+	}
+
+	stagingDir, err := s.filesystem.CreateTempDirectory(projectDir, ".gotm-adopt-*")
+	if err != nil {
+		return report, fmt.Errorf("failed to create staging directory: %v", err)
+	}
+
+	created := make([]string, 0, len(toCreate)+1)
+	for _, file := range append(slices.Clone(projectFiles), ADOPTED_ROUTES_FILE) {
+		template, ok := toCreate[file]
+		if !ok {
+			continue
+		}
+
+		if err := s.stageFile(ctx, stagingDir, file, template, config); err != nil {
+			return report, s.rollback(stagingDir, err)
+		}
+		created = append(created, file)
+	}
+
+	if err := s.writeManifest(manifest, stagingDir); err != nil {
+		return report, s.rollback(stagingDir, err)
+	}
+	created = append(created, models.MANIFEST_FILENAME)
+
+	if err := ctx.Err(); err != nil {
+		return report, s.rollback(stagingDir, err)
+	}
+
+	if err := s.moveIntoPlace(stagingDir, projectDir, nil, created); err != nil {
+		return report, s.rollback(stagingDir, err)
+	}
+
+	if err := s.writeMerged(projectDir, toMerge); err != nil {
+		for _, file := range created {
+			s.filesystem.DeleteFileRecursive(filepath.Join(projectDir, file))
+		}
+		return report, s.rollback(stagingDir, err)
+	}
+
+	report.Created = created
+	report.Merged = sortedKeys(toMerge)
+	slices.Sort(report.Skipped)
+
+	return report, s.filesystem.DeleteFileRecursive(stagingDir)
+}
+
+func (s InitialiserService) exists(projectDir, file string) (bool, error) {
+	exists, err := s.filesystem.HasDirectoryOrFile(filepath.Join(projectDir, file))
+	if err != nil {
+		return false, fmt.Errorf("unable to check if %v exists: %v", file, err)
+	}
+	return exists, nil
+}
+
+// Returns the contents of an existing file, or an empty string if it doesn't exist
+func (s InitialiserService) readExisting(projectDir, file string) (string, error) {
+	contents, err := s.filesystem.ReadFile(filepath.Join(projectDir, file))
+	if err != nil {
+		return "", fmt.Errorf("unable to read %v: %v", file, err)
+	}
+	return contents, nil
+}
+
+// Renders a file into the staging directory, creating its parent directories if needed
+func (s InitialiserService) stageFile(ctx context.Context, stagingDir, file, template string, config projectTemplateConfig) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	dir := filepath.Join(stagingDir, filepath.Dir(file))
+	if hasDir, err := s.filesystem.HasDirectoryOrFile(dir); err != nil {
+		return err
+	} else if !hasDir {
+		if err := s.filesystem.CreateDirectory(dir); err != nil {
+			return fmt.Errorf("failed to create '%v' directory", filepath.Dir(file))
+		}
+	}
+
+	if err := s.renderFile(filepath.Join(stagingDir, file), template, config); err != nil {
+		return fmt.Errorf("failed to create '%v' file: %v", file, err)
+	}
+
+	return nil
+}
+
+// Works out how to register gotm's controllers with an existing main.go. If the main package
+// doesn't already register controllers, routes.go is added with a function for main to call
+func (s InitialiserService) adoptMainFile(projectDir string, toCreate map[string]string) (string, error) {
+	for _, file := range []string{"main.go", ADOPTED_ROUTES_FILE} {
+		contents, err := s.readExisting(projectDir, file)
+		if err != nil {
+			return "", err
+		}
+		if strings.Contains(contents, "RegisterRoutes(") {
+			return "", nil
+		}
+	}
+
+	if exists, err := s.exists(projectDir, ADOPTED_ROUTES_FILE); err != nil {
+		return "", err
+	} else if exists {
+		return fmt.Sprintf("%v already exists, so controllers need to be registered in main.go by hand", ADOPTED_ROUTES_FILE), nil
+	}
+
+	toCreate[ADOPTED_ROUTES_FILE] = "routes.go.tmpl"
+
+	return fmt.Sprintf("call registerRoutes(mux) from main in main.go to serve the frontend and controllers, see %v", ADOPTED_ROUTES_FILE), nil
+}
+
+func (s InitialiserService) mergedPackageJson(projectDir, file string, config projectTemplateConfig) (string, []string, error) {
+	existing, err := s.readExisting(projectDir, file)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var required bytes.Buffer
+	if err := s.templates.WriteTemplate(&required, "package.json.tmpl", config); err != nil {
+		return "", nil, fmt.Errorf("unable to write template: %v", err)
+	}
+
+	merged, conflicts, err := mergePackageJson([]byte(existing), required.Bytes())
+	if err != nil {
+		return "", nil, err
+	}
+
+	return string(merged), conflicts, nil
+}
+
+// Overwrites existing files with their merged contents, restoring the originals if any fail
+func (s InitialiserService) writeMerged(projectDir string, toMerge map[string]string) error {
+	originals := make(map[string]string)
+
+	for _, file := range sortedKeys(toMerge) {
+		path := filepath.Join(projectDir, file)
+
+		original, err := s.filesystem.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("unable to read %v: %v", file, err)
+		}
+
+		if err := s.filesystem.WriteFile(path, toMerge[file]); err != nil {
+			restoreErrs := []error{fmt.Errorf("failed to write %v: %v", file, err)}
+			for restore, contents := range originals {
+				if err := s.filesystem.WriteFile(restore, contents); err != nil {
+					restoreErrs = append(restoreErrs, fmt.Errorf("failed to restore %v: %v", restore, err))
+				}
+			}
+			return errors.Join(restoreErrs...)
+		}
+		originals[path] = original
+	}
+
+	return nil
+}
+
+// Appends any of the required entries missing from a .gitignore
+func mergeGitignore(contents string, required []string) string {
+	existing := make(map[string]bool)
+	for _, line := range strings.Split(contents, "\n") {
+		existing[strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(line), "/"), "/")] = true
+	}
+
+	missing := make([]string, 0)
+	for _, entry := range required {
+		if !existing[entry] {
+			missing = append(missing, entry)
+		}
+	}
+
+	if len(missing) == 0 {
+		return contents
+	}
+
+	if contents != "" && !strings.HasSuffix(contents, "\n") {
+		contents += "\n"
+	}
+
+	return fmt.Sprintf("%v\n# Added by gotm\n%v\n", contents, strings.Join(missing, "\n"))
+}
+
+var moduleDirectiveRegex = regexp.MustCompile(`(?m)^module\s+"?([^\s"]+)"?`)
+
+// Returns the module path declared in the contents of a go.mod file
+func modulePathFromGoMod(contents string) string {
+	matches := moduleDirectiveRegex.FindStringSubmatch(contents)
+	if matches == nil {
+		return ""
+	}
+	return matches[1]
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
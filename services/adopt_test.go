@@ -0,0 +1,100 @@
+package services
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/danielronalds/gotm/models"
+	"github.com/danielronalds/gotm/repositories"
+)
+
+// Creates a file with the given contents in the memory filesystem
+func writeMemoryFile(filesystem repositories.MemoryFilesystemRepository, filename, contents string, t *testing.T) {
+	if err := filesystem.WriteFile(filename, contents); err != nil {
+		t.Fatalf("Failed to write %v: %v", filename, err)
+	}
+}
+
+func TestAdoptProjectKeepsExistingGoProject(t *testing.T) {
+	// Arrange
+	filesystem := repositories.NewMemoryFilesystemRepository("/project")
+	writeMemoryFile(filesystem, "/project/go.mod", "module example.com/team/api\n\ngo 1.23\n", t)
+	writeMemoryFile(filesystem, "/project/main.go", "package main\n", t)
+	initService := NewInitialiserService(filesystem, mockTemplates{})
+	manifest := models.DefaultManifest("api", "github.com/someone/api")
+
+	// Act
+	report, err := initService.AdoptProject(context.Background(), manifest, "/project")
+	if err != nil {
+		t.Fatalf("Failed to adopt project: %v", err)
+	}
+
+	// Assert
+	if contents, _ := filesystem.ReadFile("/project/main.go"); contents != "package main\n" {
+		t.Fatalf("Expected main.go to be left alone, got %v", contents)
+	}
+	if !slices.Contains(report.Skipped, "go.mod") || !slices.Contains(report.Skipped, "main.go") {
+		t.Fatalf("Expected go.mod and main.go to be skipped, got %v", report.Skipped)
+	}
+	if !slices.Contains(report.Created, ADOPTED_ROUTES_FILE) || !slices.Contains(report.Created, "frontend/src/index.ts") {
+		t.Fatalf("Expected routes and frontend to be created, got %v", report.Created)
+	}
+	adopted, err := filesystem.Manifest()
+	if err != nil {
+		t.Fatalf("Failed to read manifest: %v", err)
+	}
+	if adopted.Module != "example.com/team/api" {
+		t.Fatalf("Expected module path to be reused from go.mod, got %v", adopted.Module)
+	}
+}
+
+func TestMergePackageJsonKeepsExistingVersionsAndScripts(t *testing.T) {
+	// Arrange
+	existing := `{"name": "legacy", "scripts": {"build": "vite build && tsc"}, "dependencies": {"mithril": "^2.0.0", "react": "^18.0.0"}}`
+	required := `{"name": "gotm", "scripts": {"build": "esbuild", "css": "tailwindcss"}, "dependencies": {"mithril": "^2.2.9"}, "devDependencies": {"esbuild": "^0.25.3"}}`
+
+	// Act
+	merged, conflicts, err := mergePackageJson([]byte(existing), []byte(required))
+	if err != nil {
+		t.Fatalf("Failed to merge: %v", err)
+	}
+
+	// Assert
+	expected := `{
+  "name": "legacy",
+  "scripts": {
+    "build": "vite build && tsc",
+    "css": "tailwindcss"
+  },
+  "dependencies": {
+    "mithril": "^2.0.0",
+    "react": "^18.0.0"
+  },
+  "devDependencies": {
+    "esbuild": "^0.25.3"
+  }
+}
+`
+	if string(merged) != expected {
+		t.Fatalf("Expected:\n%v\ngot:\n%v", expected, string(merged))
+	}
+	if len(conflicts) != 1 || !strings.Contains(conflicts[0], "build") {
+		t.Fatalf("Expected build script conflict to be reported, got %v", conflicts)
+	}
+}
+
+func TestMergeGitignoreOnlyAddsMissingEntries(t *testing.T) {
+	// Arrange
+	existing := "/node_modules/\nbin"
+
+	// Act
+	merged := mergeGitignore(existing, []string{"node_modules", "dist"})
+
+	// Assert
+	expected := "/node_modules/\nbin\n\n# Added by gotm\ndist\n"
+	if merged != expected {
+		t.Fatalf("Expected %q, got %q", expected, merged)
+	}
+}
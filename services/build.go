@@ -2,12 +2,13 @@ package services
 
 import (
 	"fmt"
-)
 
-const DEV_BULID_GO_BIN = ".main.tmp"
+	"github.com/danielronalds/gotm/models"
+)
 
 type BuildServiceFilesystem interface {
 	ProjectRoot
+	ProjectManifest
 	DirReader
 	FileDeleter
 }
@@ -22,32 +23,62 @@ func NewBuildService(filesystem BuildServiceFilesystem, shell CmdRunner) BuildSe
 }
 
 func (s BuildService) InstallNpmDeps() error {
-	return s.shell.RunCmdWithPipedOutput(s.filesystem.FromRoot("frontend"), "npm", "install")
+	manifest, err := s.filesystem.Manifest()
+	if err != nil {
+		return fmt.Errorf("unable to read project manifest: %v", err)
+	}
+
+	frontendDir, err := s.filesystem.FromRoot(manifest.Frontend)
+	if err != nil {
+		return err
+	}
+
+	return s.shell.RunCmdWithPipedOutput(frontendDir, "npm", "install")
 }
 
 func (s BuildService) InstallGoDeps() error {
-	return s.shell.RunCmdWithPipedOutput(s.filesystem.Root(), "go", "mod", "tidy")
+	root, err := s.filesystem.Root()
+	if err != nil {
+		return err
+	}
+
+	return s.shell.RunCmdWithPipedOutput(root, "go", "mod", "tidy")
 }
 
-func (s BuildService) buildGoBin(binName string) error {
-	return s.shell.RunCmdWithPipedOutput(s.filesystem.Root(), "go", "build", "-o", binName, ".")
+func (s BuildService) buildGoBin(manifest models.Manifest, binName string) error {
+	root, err := s.filesystem.Root()
+	if err != nil {
+		return err
+	}
+
+	return s.shell.RunCmdWithPipedOutput(root, "go", "build", "-o", binName, manifest.MainPackage())
 }
 
-func (s BuildService) buildFrontend() error {
-	return s.shell.RunCmdWithPipedOutput(s.filesystem.FromRoot("frontend"), "npm", "run", "build")
+func (s BuildService) buildFrontend(manifest models.Manifest) error {
+	frontendDir, err := s.filesystem.FromRoot(manifest.Frontend)
+	if err != nil {
+		return err
+	}
+
+	return s.shell.RunCmdWithPipedOutput(frontendDir, "npm", "run", "build")
 }
 
 func (s BuildService) BuildDev(frontend, backend bool) error {
+	manifest, err := s.filesystem.Manifest()
+	if err != nil {
+		return fmt.Errorf("unable to read project manifest: %v", err)
+	}
+
 	// Building go project
 	if backend {
-		if err := s.buildGoBin(DEV_BULID_GO_BIN); err != nil {
+		if err := s.buildGoBin(manifest, manifest.DevBinary); err != nil {
 			return fmt.Errorf("unable to build go binary: %v", err)
 		}
 	}
 
 	// Building frontend
 	if frontend {
-		if err := s.buildFrontend(); err != nil {
+		if err := s.buildFrontend(manifest); err != nil {
 			return fmt.Errorf("unable to build frontend: %v", err)
 		}
 	}
@@ -56,7 +87,17 @@ func (s BuildService) BuildDev(frontend, backend bool) error {
 }
 
 func (s BuildService) CleanupDev() error {
-	hasFile, err := s.filesystem.HasDirectoryOrFile(s.filesystem.FromRoot(DEV_BULID_GO_BIN))
+	manifest, err := s.filesystem.Manifest()
+	if err != nil {
+		return fmt.Errorf("unable to read project manifest: %v", err)
+	}
+
+	devBinary, err := s.filesystem.FromRoot(manifest.DevBinary)
+	if err != nil {
+		return err
+	}
+
+	hasFile, err := s.filesystem.HasDirectoryOrFile(devBinary)
 	if err != nil {
 		return fmt.Errorf("failed to detect if dev go binary exists: %v", err)
 	}
@@ -64,9 +105,18 @@ func (s BuildService) CleanupDev() error {
 		return nil
 	}
 
-	if err := s.filesystem.DeleteFileRecursive(DEV_BULID_GO_BIN); err != nil {
+	if err := s.filesystem.DeleteFileRecursive(devBinary); err != nil {
 		return fmt.Errorf("failed to delete dev go binary: %v", err)
 	}
 
 	return nil
 }
+
+// Builds the frontend and the go binary to check the project compiles, removing the binary after
+func (s BuildService) VerifyBuild() error {
+	if err := s.BuildDev(true, true); err != nil {
+		return err
+	}
+
+	return s.CleanupDev()
+}
@@ -1,25 +1,27 @@
 package services
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
+	"go/format"
+	"path"
+	"path/filepath"
 	"strings"
 	"time"
-)
 
-const CONTROLLERS_DIR string = "controllers"
-const SERVICES_DIR string = "services"
-const REPOSITORIES_DIR string = "repositories"
-const MIGRATION_DIR string = "migrations"
-const MODELS_DIR string = "frontend/src/models"
-const VIEWS_DIR string = "frontend/src/views"
-const PAGES_DIR string = "frontend/src/views/pages"
+	"github.com/danielronalds/gotm/models"
+)
 
 const VIEW_COMPONENT_TYPE = "view"
 
 type ComponentServiceFilesystem interface {
 	ProjectRoot
+	ProjectManifest
 	FileCreater
+	FileReader
+	FileWriter
+	FileDeleter
 	DirCreater
 	DirReader
 }
@@ -33,48 +35,373 @@ func NewComponentService(filesystem ComponentServiceFilesystem, templates Templa
 	return ComponentService{filesystem, templates}
 }
 
-func (s ComponentService) GenerateController(name string) error {
-	filename := fmt.Sprintf("%v.go", name)
-	return s.generateComponent(name, "controller", s.filesystem.FromRoot(CONTROLLERS_DIR), filename, "controller.go.tmpl")
+// Generates a controller, registering it in main.go unless the options say otherwise. Registering
+// it constructs the components it uses along with it, i.e. c.NewBooksController(s.NewBooksService())
+func (s ComponentService) GenerateController(name string, options models.ComponentOptions) error {
+	componentName := parseComponentName(name)
+	dependencies, err := s.componentDependencies("controller", componentName, options.Uses)
+	if err != nil {
+		return err
+	}
+
+	// Controllers are still registered when there was nothing for their test to check
+	var nothingToTest models.NothingToTestError
+	testErr := s.generateComponentWithTest(name, "controller", "controller.go.tmpl", dependencies, options.WithTests)
+	if testErr != nil && !errors.As(testErr, &nothingToTest) {
+		return testErr
+	}
+
+	if options.NoRegister {
+		return testErr
+	}
+
+	manifest, err := s.filesystem.Manifest()
+	if err != nil {
+		return fmt.Errorf("unable to read project manifest: %v", err.Error())
+	}
+
+	modulePath, err := s.modulePath(manifest)
+	if err != nil {
+		return err
+	}
+
+	call, err := s.constructorCall(modulePath, manifest, "controller", componentName, nil)
+	if err != nil {
+		return errors.Join(testErr, models.RegistrationError{
+			Instructions: "construct the controller with what it uses and add it to the controllers slice in main.go by hand",
+			Err:          err,
+		})
+	}
+
+	var registrationErr models.RegistrationError
+	err = s.registerController(call)
+	if err != nil && !errors.As(err, &registrationErr) {
+		return err
+	}
+
+	return errors.Join(testErr, err)
+}
+
+// Returns the import path of the package the component is generated in, along with the names to
+// prefer importing it under. Nested packages are imported under their own name, i.e. admin, or
+// their name followed by the first letter of the type's package, i.e. adminc, if that's taken
+func packageImport(modulePath, typeDir, componentType string, name componentName) (string, []string) {
+	importPath := path.Join(modulePath, filepath.ToSlash(typeDir), filepath.ToSlash(name.dir(true)))
+	if len(name.namespace) == 0 {
+		return importPath, nil
+	}
+
+	pkg := name.namespace[len(name.namespace)-1].LowerCaseName
+	return importPath, []string{pkg, pkg + componentPackages[componentType][:1]}
+}
+
+// Adds the call constructing a controller to the controllers slice in main.go, or routes.go for
+// adopted projects
+func (s ComponentService) registerController(call constructorCall) error {
+	manifest, err := s.filesystem.Manifest()
+	if err != nil {
+		return fmt.Errorf("unable to read project manifest: %v", err.Error())
+	}
+
+	var registrationErr error
+	for _, candidate := range []string{"main.go", ADOPTED_ROUTES_FILE} {
+		filename, err := s.filesystem.FromRoot(filepath.Join(manifest.Main, candidate))
+		if err != nil {
+			return err
+		}
+
+		source, err := s.filesystem.ReadFile(filename)
+		if err != nil {
+			return fmt.Errorf("unable to read %v: %v", candidate, err.Error())
+		}
+		if source == "" {
+			continue
+		}
+
+		registered, err := registerController(source, call)
+		if err != nil {
+			registrationErr = errors.Join(registrationErr, fmt.Errorf("%v: %v", candidate, err))
+			continue
+		}
+
+		if registered == source {
+			return nil
+		}
+		return s.filesystem.WriteFile(filename, registered)
+	}
+
+	if registrationErr == nil {
+		registrationErr = errors.New("main.go doesn't exist")
+	}
+
+	return models.RegistrationError{
+		Instructions: fmt.Sprintf("add %v to the controllers slice in main.go by hand", call.render(defaultImportNames(call))),
+		Err:          registrationErr,
+	}
+}
+
+// Returns the module path of the project, falling back to go.mod for projects without a manifest
+func (s ComponentService) modulePath(manifest models.Manifest) (string, error) {
+	return projectModulePath(s.filesystem, manifest)
 }
 
-func (s ComponentService) GenerateService(name string) error {
-	filename := fmt.Sprintf("%v.go", name)
-	return s.generateComponent(name, "service", s.filesystem.FromRoot(SERVICES_DIR), filename, "service.go.tmpl")
+func (s ComponentService) GenerateService(name string, options models.ComponentOptions) error {
+	dependencies, err := s.componentDependencies("service", parseComponentName(name), options.Uses)
+	if err != nil {
+		return err
+	}
+
+	return s.generateComponentWithTest(name, "service", "service.go.tmpl", dependencies, options.WithTests)
 }
 
-func (s ComponentService) GenerateRepository(name string) error {
-	filename := fmt.Sprintf("%v.go", name)
-	return s.generateComponent(name, "repository", s.filesystem.FromRoot(REPOSITORIES_DIR), filename, "repository.go.tmpl")
+func (s ComponentService) GenerateRepository(name string, options models.ComponentOptions) error {
+	return s.generateComponentWithTest(name, "repository", "repository.go.tmpl", nil, options.WithTests)
 }
 
-func (s ComponentService) GenerateMigration(name string) error {
+// Data passed to the templates of components that can depend on other components
+type dependentTemplateData struct {
+	componentTemplateData
+	// Components passed to the component's constructor, in the order they're passed
+	Dependencies []componentDependency
+	// Import specs the methods of the dependencies need, i.e. "example.com/app/models"
+	Imports []string
+}
+
+// Generates the component with its dependencies, followed by its test if one was asked for
+func (s ComponentService) generateComponentWithTest(name, componentType, templateName string, dependencies []componentDependency, withTest bool) error {
+	componentName := parseComponentName(name)
+	imports, err := s.dependencyMethods(componentType, componentName, dependencies)
+	if err != nil {
+		return err
+	}
+	data := dependentTemplateData{newComponentTemplateData(componentType, componentName), dependencies, imports}
+
+	if err := s.renderComponent(componentType, componentFilename(componentType, componentName), templateName, data); err != nil {
+		return err
+	}
+	if !withTest {
+		return nil
+	}
+
+	return s.GenerateTest(componentType, name)
+}
+
+func (s ComponentService) GenerateMigration(name string, options models.ComponentOptions) error {
 	// Generating timestamp that matches how goose generates timestamps
 	timestamp := time.Now().UTC().Format("20060102150405")
 
-	filename := fmt.Sprintf("%v_%v.sql", timestamp, name)
-	return s.generateComponent(name, "migration", s.filesystem.FromRoot(MIGRATION_DIR), filename, "migration.sql.tmpl")
+	filename := fmt.Sprintf("%v_%v.sql", timestamp, newIdentifier(name).SnakeName)
+	return s.renderComponent("migration", filename, "migration.sql.tmpl", newIdentifier(name))
 }
 
-func (s ComponentService) GenerateModel(name string) error {
-	filename := fmt.Sprintf("%v.ts", name)
-	return s.generateComponent(name, "model", s.filesystem.FromRoot(MODELS_DIR), filename, "model.ts.tmpl")
+func (s ComponentService) GenerateModel(name string, options models.ComponentOptions) error {
+	return s.generateComponent(name, "model", "model.ts.tmpl")
 }
 
-func (s ComponentService) GenerateView(name string) error {
-	filename := fmt.Sprintf("%v.ts", name)
-	return s.generateComponent(name, VIEW_COMPONENT_TYPE, s.filesystem.FromRoot(VIEWS_DIR), filename, "view.ts.tmpl")
+func (s ComponentService) GenerateView(name string, options models.ComponentOptions) error {
+	return s.generateComponent(name, VIEW_COMPONENT_TYPE, "view.ts.tmpl")
 }
 
-func (s ComponentService) GeneratePage(name string) error {
-	filename := fmt.Sprintf("%vPage.ts", toSentenceCase(name))
-	return s.generateComponent(name, "page", s.filesystem.FromRoot(PAGES_DIR), filename, "page.ts.tmpl")
+// Templates of the middleware presets, keyed by the name they're chosen with
+var middlewarePresets = map[string]string{
+	"logging":   "middleware_logging.go.tmpl",
+	"recover":   "middleware_recover.go.tmpl",
+	"cors":      "middleware_cors.go.tmpl",
+	"ratelimit": "middleware_ratelimit.go.tmpl",
+	"requestid": "middleware_requestid.go.tmpl",
+	"basicauth": "middleware_basicauth.go.tmpl",
+}
+
+// Generates a middleware, using the implementation of the preset given in the options if there is one
+func (s ComponentService) GenerateMiddleware(name string, options models.ComponentOptions) error {
+	template := "middleware.go.tmpl"
+	if options.Preset != "" {
+		presetTemplate, ok := middlewarePresets[strings.ToLower(options.Preset)]
+		if !ok {
+			return fmt.Errorf("\"%v\" is not a middleware preset, expected one of %v", options.Preset, strings.Join(sortedKeys(middlewarePresets), ", "))
+		}
+		template = presetTemplate
+	}
+
+	return s.generateComponent(name, "middleware", template)
+}
+
+// Generates a page, adding a route to it in the router unless the options say otherwise
+func (s ComponentService) GeneratePage(name string, options models.ComponentOptions) error {
+	componentName := parseComponentName(name)
+
+	if options.NoRegister {
+		return s.generateComponent(name, "page", "page.ts.tmpl")
+	}
+
+	route := options.Route
+	if route == "" {
+		route = componentName.routePath(componentName.identifier)
+	}
+
+	// Working out the new router first, so nothing is created if the page can't be routed to
+	page := pageRoute{
+		component: fmt.Sprintf("%vPage", componentName.qualified().Name),
+		filename:  componentFilename("page", componentName),
+		route:     route,
+	}
+	routerFile, router, err := s.routerWithPages(page)
+	if err != nil {
+		return err
+	}
+
+	if err := s.generateComponent(name, "page", "page.ts.tmpl"); err != nil {
+		return err
+	}
+
+	return s.filesystem.WriteFile(routerFile, router)
+}
+
+// A page along with the path it's routed to
+type pageRoute struct {
+	// Name the page is imported under in the router, including the directories it's nested in so
+	// pages with the same name don't clash, i.e. AdminUsersPage
+	component string
+	// File of the page, relative to the pages directory
+	filename string
+	route    string
+}
+
+// Returns the path of the router file and its contents with routes to the pages added
+func (s ComponentService) routerWithPages(routes ...pageRoute) (string, string, error) {
+	manifest, err := s.filesystem.Manifest()
+	if err != nil {
+		return "", "", fmt.Errorf("unable to read project manifest: %v", err.Error())
+	}
+
+	for _, route := range routes {
+		if !strings.HasPrefix(route.route, "/") {
+			return "", "", fmt.Errorf("route \"%v\" must start with a /", route.route)
+		}
+	}
+
+	routerPath := filepath.Join(manifest.Frontend, "src", "index.ts")
+	routerFile, err := s.filesystem.FromRoot(routerPath)
+	if err != nil {
+		return "", "", err
+	}
+	pagesDir, err := s.filesystem.FromRoot(manifest.Directories.Pages)
+	if err != nil {
+		return "", "", err
+	}
+
+	source, err := s.filesystem.ReadFile(routerFile)
+	if err != nil {
+		return "", "", fmt.Errorf("unable to read %v: %v", routerPath, err.Error())
+	}
+	if source == "" {
+		return "", "", fmt.Errorf("unable to find the router in %v, pass --no-register to create the page without a route", routerPath)
+	}
+
+	router := source
+	for _, route := range routes {
+		page := strings.TrimSuffix(route.filename, filepath.Ext(route.filename))
+		importPath, err := filepath.Rel(filepath.Dir(routerFile), filepath.Join(pagesDir, page))
+		if err != nil {
+			return "", "", err
+		}
+		importPath = filepath.ToSlash(importPath)
+		if !strings.HasPrefix(importPath, ".") {
+			importPath = "./" + importPath
+		}
+
+		router, err = registerPageRoute(router, importPath, route.component, route.route)
+		if err != nil {
+			return "", "", fmt.Errorf("unable to add the page to the router in %v, as %v. Pass --no-register to create the page without a route", routerPath, err)
+		}
+	}
+
+	return routerFile, router, nil
+}
+
+// Names of the packages Go components are generated in when they aren't nested
+var componentPackages = map[string]string{
+	"controller": "controllers",
+	"service":    "services",
+	"repository": "repositories",
+	"middleware": "middleware",
+	"gomodel":    "models",
+}
+
+// Data passed to the templates of components
+type componentTemplateData struct {
+	// Forms of the component's name, without the directories it's nested in
+	identifier
+	// Package Go components are declared in, i.e. controllers, or admin for admin/users
+	Package string
+	// Path the component is served under, i.e. /admin/users
+	RoutePath string
+}
+
+func newComponentTemplateData(componentType string, name componentName) componentTemplateData {
+	pkg := componentPackages[componentType]
+	if len(name.namespace) != 0 {
+		pkg = name.namespace[len(name.namespace)-1].LowerCaseName
+	}
+
+	return componentTemplateData{name.identifier, pkg, name.routePath(name.identifier)}
 }
 
 // general method for dealing with the logic of generating a component.
 //
-// `fileExtension` should include the dot, i.e. ".go"
-func (s ComponentService) generateComponent(name, componentType, componentDir, filename, templateName string) error {
+// The component is placed in the directory the project manifest specifies for its type, nested in
+// any directories given in its name, and its template is given every form of its name
+func (s ComponentService) generateComponent(name, componentType, templateName string) error {
+	componentName := parseComponentName(name)
+	data := newComponentTemplateData(componentType, componentName)
+
+	return s.renderComponent(componentType, componentFilename(componentType, componentName), templateName, data)
+}
+
+// Returns the path of the file generated for the component, relative to the directory of its type.
+// Go files are snake case, while frontend files follow the casing of what they export
+func componentFilename(componentType string, name componentName) string {
+	switch componentType {
+	case "model":
+		return filepath.Join(name.dir(false), fmt.Sprintf("%v.ts", name.CamelName))
+	case VIEW_COMPONENT_TYPE:
+		return filepath.Join(name.dir(false), fmt.Sprintf("%v.ts", name.Name))
+	case "page":
+		return filepath.Join(name.dir(false), fmt.Sprintf("%vPage.ts", name.Name))
+	}
+
+	return filepath.Join(name.dir(true), fmt.Sprintf("%v.go", name.SnakeName))
+}
+
+// Returns the path of the component file, in the directory the project manifest specifies for
+// its type
+func (s ComponentService) componentPath(componentType, filename string) (string, error) {
+	manifest, err := s.filesystem.Manifest()
+	if err != nil {
+		return "", fmt.Errorf("unable to read project manifest: %v", err.Error())
+	}
+
+	dir, ok := manifest.Directories.ForComponent(componentType)
+	if !ok {
+		return "", fmt.Errorf("project manifest has no directory for %v components", componentType)
+	}
+	componentDir, err := s.filesystem.FromRoot(dir)
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(componentDir, filename), nil
+}
+
+// Renders the template with the given data into the component file, which must not already exist.
+// Go files are gofmt formatted so templates don't have to worry about alignment
+func (s ComponentService) renderComponent(componentType, filename, templateName string, data any) error {
+	componentFilepath, err := s.componentPath(componentType, filename)
+	if err != nil {
+		return err
+	}
+	componentDir := filepath.Dir(componentFilepath)
+
 	hasDir, err := s.filesystem.HasDirectoryOrFile(componentDir)
 	if err != nil {
 		return fmt.Errorf("unable to check if %v directory exists: %v", componentDir, err.Error())
@@ -86,8 +413,6 @@ func (s ComponentService) generateComponent(name, componentType, componentDir, f
 		}
 	}
 
-	componentFilepath := fmt.Sprintf("%v/%v", componentDir, filename)
-
 	hasFile, err := s.filesystem.HasDirectoryOrFile(componentFilepath)
 	if err != nil {
 		return fmt.Errorf("unable to check if %v with that name already exists: %v", componentType, err.Error())
@@ -96,51 +421,29 @@ func (s ComponentService) generateComponent(name, componentType, componentDir, f
 		return fmt.Errorf("%v with that name already exists", componentType)
 	}
 
-	file, err := s.filesystem.CreateFile(componentFilepath)
-	if err != nil {
-		return fmt.Errorf("unable to create %v file: %v", componentType, err.Error())
-	}
-	defer file.Close()
-
-	componentName := toSentenceCase(name)
-	if componentType == VIEW_COMPONENT_TYPE {
-		componentName = name
-	}
-	if err := s.templates.WriteTemplate(file, templateName, struct {
-		Name          string
-		LowerCaseName string
-	}{Name: componentName, LowerCaseName: strings.ToLower(componentName)}); err != nil {
+	var contents bytes.Buffer
+	if err := s.templates.WriteTemplate(&contents, templateName, data); err != nil {
 		return fmt.Errorf("unable to write template: %v", err.Error())
 	}
 
-	return nil
-}
-
-func (s ComponentService) GenerateDockerfile() error {
-	hasFile, err := s.filesystem.HasDirectoryOrFile(s.filesystem.FromRoot("Dockerfile"))
-	if err != nil {
-		return fmt.Errorf("unable to check if Dockerfile already exists: %v", err.Error())
-	}
-	if hasFile {
-		return errors.New("Dockerfile already exists")
+	rendered := contents.Bytes()
+	if filepath.Ext(filename) == ".go" {
+		// Leaving the output as is if it isn't valid Go, so a broken template override can be fixed
+		// by looking at what it produced
+		if formatted, err := format.Source(rendered); err == nil {
+			rendered = formatted
+		}
 	}
 
-	file, err := s.filesystem.CreateFile(s.filesystem.FromRoot("Dockerfile"))
+	file, err := s.filesystem.CreateFile(componentFilepath)
 	if err != nil {
-		return fmt.Errorf("unable to create dockerfile: %v", err.Error())
+		return fmt.Errorf("unable to create %v file: %v", componentType, err.Error())
 	}
 	defer file.Close()
 
-	if err := s.templates.WriteTemplate(file, "Dockerfile.tmpl", nil); err != nil {
-		return fmt.Errorf("unable to write template: %v", err.Error())
+	if _, err := file.Write(rendered); err != nil {
+		return fmt.Errorf("unable to write %v file: %v", componentType, err.Error())
 	}
 
-	return nil
-}
-
-func toSentenceCase(s string) string {
-	firstLetter := strings.ToUpper(string(s[0]))
-	rest := strings.ToLower(s[1:])
-
-	return fmt.Sprintf("%v%v", firstLetter, rest)
+	return s.recordGenerated(componentFilepath, string(rendered))
 }
@@ -1,30 +1,139 @@
 package services
 
-import "testing"
+import (
+	"errors"
+	"testing"
 
-func TestToSentanceCase(t *testing.T) {
+	"github.com/danielronalds/gotm/models"
+	"github.com/danielronalds/gotm/repositories"
+)
+
+func TestNewIdentifierWithOneLetter(t *testing.T) {
 	// Arrange
-	word := "cRazyStYlEs"
-	expected := "Crazystyles"
+	word := "c"
+	expected := "C"
 
 	// Act
-	result := toSentenceCase(word)
+	result := newIdentifier(word).Name
 
 	// Assert
 	if result != expected {
 		t.Fatalf("Wanted %v, got %v", expected, result)
 	}
 }
-func TestToSentanceCaseWithOneLetter(t *testing.T) {
+
+func TestNewIdentifierWithEmptyString(t *testing.T) {
 	// Arrange
-	word := "c"
-	expected := "C"
+	word := ""
+	expected := identifier{}
 
 	// Act
-	result := toSentenceCase(word)
+	result := newIdentifier(word)
 
 	// Assert
 	if result != expected {
 		t.Fatalf("Wanted %v, got %v", expected, result)
 	}
 }
+
+func TestGenerateComponentsNameFilesByCasing(t *testing.T) {
+	// Arrange
+	filesystem := repositories.NewMemoryFilesystemRepository("/project")
+	writeMemoryFile(filesystem, "/project/gotm.json", `{"name": "project"}`, t)
+	componentService := NewComponentService(filesystem, mockTemplates{})
+	options := models.ComponentOptions{NoRegister: true}
+
+	expectedFiles := []string{
+		"/project/controllers/user_profile.go",
+		"/project/frontend/src/models/userProfile.ts",
+		"/project/frontend/src/views/UserProfile.ts",
+		"/project/frontend/src/views/pages/UserProfilePage.ts",
+	}
+
+	// Act
+	err := errors.Join(
+		componentService.GenerateController("userProfile", options),
+		componentService.GenerateModel("user-profile", options),
+		componentService.GenerateView("user_profile", options),
+		componentService.GeneratePage("UserProfile", options),
+	)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Failed to generate components: %v", err)
+	}
+	for _, file := range expectedFiles {
+		if hasFile, _ := filesystem.HasDirectoryOrFile(file); !hasFile {
+			t.Fatalf("Expected %v to be created", file)
+		}
+	}
+}
+
+func TestGenerateMiddlewareCreatesFileInMiddlewareDirectory(t *testing.T) {
+	// Arrange
+	filesystem := repositories.NewMemoryFilesystemRepository("/project")
+	writeMemoryFile(filesystem, "/project/gotm.json", `{"name": "project"}`, t)
+	componentService := NewComponentService(filesystem, mockTemplates{})
+
+	// Act
+	err := componentService.GenerateMiddleware("logging", models.ComponentOptions{Preset: "logging"})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Failed to generate middleware: %v", err)
+	}
+	if hasFile, _ := filesystem.HasDirectoryOrFile("/project/middleware/logging.go"); !hasFile {
+		t.Fatal("Expected middleware/logging.go to be created")
+	}
+}
+
+func TestGenerateMiddlewareRejectsUnknownPreset(t *testing.T) {
+	// Arrange
+	filesystem := repositories.NewMemoryFilesystemRepository("/project")
+	writeMemoryFile(filesystem, "/project/gotm.json", `{"name": "project"}`, t)
+	componentService := NewComponentService(filesystem, mockTemplates{})
+
+	// Act
+	err := componentService.GenerateMiddleware("auth", models.ComponentOptions{Preset: "oauth"})
+
+	// Assert
+	if err == nil {
+		t.Fatal("Expected an unknown preset to fail")
+	}
+	if changes := filesystem.Changes(); len(changes) != 1 {
+		t.Fatalf("Expected only the manifest to exist, found %v", changes)
+	}
+}
+
+func TestGenerateComponentsNestsNamesWithSlashes(t *testing.T) {
+	// Arrange
+	filesystem := repositories.NewMemoryFilesystemRepository("/project")
+	writeMemoryFile(filesystem, "/project/gotm.json", `{"name": "project"}`, t)
+	componentService := NewComponentService(filesystem, mockTemplates{})
+	options := models.ComponentOptions{NoRegister: true}
+
+	expectedFiles := []string{
+		"/project/controllers/admin/users.go",
+		"/project/services/billing/invoice_item.go",
+		"/project/frontend/src/views/admin/UserList.ts",
+		"/project/frontend/src/views/pages/admin-area/SettingsPage.ts",
+	}
+
+	// Act
+	err := errors.Join(
+		componentService.GenerateController("admin/users", options),
+		componentService.GenerateService("billing/invoiceItem", options),
+		componentService.GenerateView("admin/userList", options),
+		componentService.GeneratePage("adminArea/settings", options),
+	)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Failed to generate components: %v", err)
+	}
+	for _, file := range expectedFiles {
+		if hasFile, _ := filesystem.HasDirectoryOrFile(file); !hasFile {
+			t.Fatalf("Expected %v to be created", file)
+		}
+	}
+}
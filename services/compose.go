@@ -0,0 +1,109 @@
+package services
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Name of the compose file `gotm add compose` generates
+const COMPOSE_FILE = "compose.yaml"
+
+// Files docker compose reads by default, any of which stops a compose file being generated
+var composeFilenames = []string{COMPOSE_FILE, "compose.yml", "docker-compose.yaml", "docker-compose.yml"}
+
+// Services that can be run alongside the app in the compose file
+var composeServices = []string{"postgres", "redis"}
+
+// Environment file compose passes to the app when the project has one
+const ENV_FILE = ".env"
+
+// Data passed to the compose template
+type composeTemplateData struct {
+	// Forms of the project's name, used for the compose project and the database's credentials
+	Project identifier
+	Port    int
+	// Environment file passed to the app, empty if the project doesn't have one
+	EnvFile  string
+	Postgres bool
+	Redis    bool
+}
+
+// Returns whether any of the services are run alongside the app
+func (d composeTemplateData) HasServices() bool {
+	return d.Postgres || d.Redis
+}
+
+// Generates a compose file running the app from the project's Dockerfile, along with the chosen
+// services. Projects with a postgres database run postgres without it being chosen. The Dockerfile
+// is generated too if the project doesn't have one. Returns the files that were created
+func (s ComponentService) GenerateCompose(services []string) ([]string, error) {
+	manifest, err := s.filesystem.Manifest()
+	if err != nil {
+		return nil, fmt.Errorf("unable to read project manifest: %v", err.Error())
+	}
+
+	data := composeTemplateData{Project: newIdentifier(manifest.Name), Port: manifest.Port}
+	if manifest.Database != nil && manifest.Database.Engine == "postgresql" {
+		data.Postgres = true
+	}
+	for _, service := range services {
+		switch strings.ToLower(service) {
+		case "postgres":
+			data.Postgres = true
+		case "redis":
+			data.Redis = true
+		default:
+			return nil, fmt.Errorf("\"%v\" is not a compose service, expected one of %v", service, strings.Join(composeServices, ", "))
+		}
+	}
+
+	for _, filename := range composeFilenames {
+		composeFile, err := s.filesystem.FromRoot(filename)
+		if err != nil {
+			return nil, err
+		}
+		hasFile, err := s.filesystem.HasDirectoryOrFile(composeFile)
+		if err != nil {
+			return nil, fmt.Errorf("unable to check if %v already exists: %v", filename, err.Error())
+		}
+		if hasFile {
+			return nil, fmt.Errorf("%v already exists", filename)
+		}
+	}
+
+	envFile, err := s.filesystem.FromRoot(ENV_FILE)
+	if err != nil {
+		return nil, err
+	}
+	if hasEnvFile, err := s.filesystem.HasDirectoryOrFile(envFile); err != nil {
+		return nil, fmt.Errorf("unable to check if %v exists: %v", ENV_FILE, err.Error())
+	} else if hasEnvFile {
+		data.EnvFile = ENV_FILE
+	}
+
+	created := make([]string, 0)
+	dockerfile, err := s.filesystem.FromRoot("Dockerfile")
+	if err != nil {
+		return nil, err
+	}
+	hasDockerfile, err := s.filesystem.HasDirectoryOrFile(dockerfile)
+	if err != nil {
+		return nil, fmt.Errorf("unable to check if Dockerfile exists: %v", err.Error())
+	}
+	if !hasDockerfile {
+		if created, err = s.GenerateDockerfile(); err != nil {
+			return created, err
+		}
+	}
+
+	composeFile, err := s.filesystem.FromRoot(COMPOSE_FILE)
+	if err != nil {
+		return created, err
+	}
+	if err := s.renderProjectFile(composeFile, "compose.yaml.tmpl", data); err != nil {
+		return created, err
+	}
+
+	return slices.Insert(created, 0, COMPOSE_FILE), nil
+}
@@ -0,0 +1,327 @@
+package services
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/danielronalds/gotm/models"
+)
+
+// Types of the components each type can depend on with --uses, so controllers use services and
+// services use repositories or other services
+var dependencyTypes = map[string][]string{
+	"controller": {"service"},
+	"service":    {"service", "repository"},
+}
+
+// A component another component depends on, which is passed to its constructor
+type componentDependency struct {
+	componentType string
+	name          componentName
+	// Type name of the dependency, i.e. BooksService
+	Type string
+	// Interface the consumer declares for what it uses of the dependency, i.e. BooksControllerService
+	Interface string
+	// Name of the consumer's field and constructor parameter holding the dependency, i.e. service
+	Field string
+	// Exported methods of the dependency the consumer's interface is seeded with, i.e.
+	// Get(id int) (models.Book, error)
+	Methods []string
+}
+
+// Returns the type name of a Go component, i.e. BooksService
+func componentTypeName(componentType string, name identifier) string {
+	return name.Name + capitalise(componentType)
+}
+
+// Parses a dependency given to --uses, i.e. BooksService or admin/UsersRepository. The consumer's
+// interface and field are named after the dependency's type, leaving out its name when it's the
+// consumer's own like the resource templates do, i.e. BooksControllerService and service
+func parseDependency(consumerType string, consumer componentName, use string) (componentDependency, error) {
+	namespace, leaf, _ := cutNamespace(use)
+	words := nameWords(leaf)
+
+	componentType := ""
+	if len(words) > 1 {
+		componentType = words[len(words)-1]
+	}
+	if !slices.Contains(dependencyTypes[consumerType], componentType) {
+		return componentDependency{}, fmt.Errorf("\"%v\" can't be used by a %v, expected the type name of a %v i.e. Books%v", use, consumerType, strings.Join(dependencyTypes[consumerType], " or "), capitalise(dependencyTypes[consumerType][0]))
+	}
+
+	name := parseComponentName(path.Join(namespace, identifierFromWords(words[:len(words)-1]).Name))
+	if componentType == consumerType && name.dir(true) == consumer.dir(true) && name.Name == consumer.Name {
+		return componentDependency{}, fmt.Errorf("the %v can't use itself", consumerType)
+	}
+
+	// Dependencies nested in other directories are referred to with their directories, i.e.
+	// BooksServiceAdminUsersRepository, so main.go can find them again
+	referredTo := name.identifier
+	if len(name.namespace) != 0 && name.dir(true) != consumer.dir(true) {
+		referredTo = name.qualified()
+	}
+
+	dependency := componentDependency{
+		componentType: componentType,
+		name:          name,
+		Type:          componentTypeName(componentType, name.identifier),
+		Interface:     componentTypeName(consumerType, consumer.identifier),
+	}
+	if referredTo.Name == consumer.Name {
+		dependency.Interface += capitalise(componentType)
+		dependency.Field = componentType
+	} else {
+		dependency.Interface += componentTypeName(componentType, referredTo)
+		dependency.Field = newIdentifier(componentTypeName(componentType, referredTo)).CamelName
+	}
+
+	return dependency, nil
+}
+
+// Parses the dependencies of a component, checking each of them has been generated. Dependencies
+// without directories are looked for alongside the component before the top level
+func (s ComponentService) componentDependencies(consumerType string, consumer componentName, uses []string) ([]componentDependency, error) {
+	dependencies := make([]componentDependency, 0, len(uses))
+	for _, use := range uses {
+		candidates := []string{use}
+		if _, _, nested := cutNamespace(use); !nested && len(consumer.namespace) != 0 {
+			candidates = []string{path.Join(filepath.ToSlash(consumer.dir(true)), use), use}
+		}
+
+		var dependency componentDependency
+		for _, candidate := range candidates {
+			var err error
+			if dependency, err = parseDependency(consumerType, consumer, candidate); err != nil {
+				return nil, err
+			}
+
+			exists, err := s.dependencyExists(dependency)
+			if err != nil {
+				return nil, err
+			}
+			if exists {
+				break
+			}
+			if candidate == use {
+				return nil, fmt.Errorf("%v doesn't exist, generate it first with `gotm add %v %v`", dependency.Type, dependency.componentType, path.Join(filepath.ToSlash(dependency.name.dir(true)), dependency.name.KebabName))
+			}
+		}
+
+		if slices.ContainsFunc(dependencies, func(existing componentDependency) bool { return existing.Field == dependency.Field }) {
+			return nil, fmt.Errorf("%v is used more than once", dependency.Type)
+		}
+
+		dependencies = append(dependencies, dependency)
+	}
+
+	return dependencies, nil
+}
+
+// Seeds the consumer's interface for each dependency with the dependency's exported methods, returning
+// the import specs their signatures need. Methods referring to types the consumer can't import, such as
+// ones declared in the dependency's own package, are left out for the consumer to add by hand
+func (s ComponentService) dependencyMethods(consumerType string, consumer componentName, dependencies []componentDependency) ([]string, error) {
+	if len(dependencies) == 0 {
+		return nil, nil
+	}
+
+	manifest, err := s.filesystem.Manifest()
+	if err != nil {
+		return nil, fmt.Errorf("unable to read project manifest: %v", err.Error())
+	}
+	modulePath, err := s.modulePath(manifest)
+	if err != nil {
+		return nil, err
+	}
+	consumerDir, _ := manifest.Directories.ForComponent(consumerType)
+	consumerImport, _ := packageImport(modulePath, consumerDir, consumerType, consumer)
+
+	fileset := token.NewFileSet()
+	writer := testWriter{modulePath: modulePath, fileset: fileset, usedImports: make(map[string]bool)}
+	files := make([]*ast.File, 0)
+
+	for i, dependency := range dependencies {
+		filename, err := s.componentPath(dependency.componentType, componentFilename(dependency.componentType, dependency.name))
+		if err != nil {
+			return nil, err
+		}
+		source, err := s.filesystem.ReadFile(filename)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read %v: %v", filepath.Base(filename), err.Error())
+		}
+		file, err := parser.ParseFile(fileset, filepath.Base(filename), source, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse %v: %v", filepath.Base(filename), err)
+		}
+		files = append(files, file)
+
+		dependencies[i].Methods = make([]string, 0)
+		for _, decl := range file.Decls {
+			method, ok := decl.(*ast.FuncDecl)
+			if !ok || method.Recv == nil || !method.Name.IsExported() || receiverName(method) != dependency.Type {
+				continue
+			}
+			if !importableSignature(method.Type, file, consumerImport) {
+				continue
+			}
+
+			signature := strings.TrimPrefix(writer.source(method.Type), "func")
+			dependencies[i].Methods = append(dependencies[i].Methods, method.Name.Name+signature)
+		}
+	}
+
+	return writer.imports(nil, files...), nil
+}
+
+// Returns whether every type in the function's signature can be referred to from the consumer's
+// package, being either predeclared or from a package the consumer can import
+func importableSignature(function *ast.FuncType, file *ast.File, consumerImport string) bool {
+	importable := true
+
+	var inspect func(node ast.Node) bool
+	inspect = func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.Field:
+			// Names of the parameters of function types aren't types
+			ast.Inspect(node.Type, inspect)
+			return false
+		case *ast.SelectorExpr:
+			pkg, ok := node.X.(*ast.Ident)
+			if !ok {
+				importable = false
+				return false
+			}
+			// The consumer's own types can't be imported, as its package would import itself
+			for _, spec := range file.Imports {
+				importPath, _ := strconv.Unquote(spec.Path.Value)
+				name := path.Base(importPath)
+				if spec.Name != nil {
+					name = spec.Name.Name
+				}
+				if name == pkg.Name && importPath == consumerImport {
+					importable = false
+				}
+			}
+			return false
+		case *ast.Ident:
+			// Types declared alongside the dependency can't be referred to without importing its package
+			if types.Universe.Lookup(node.Name) == nil {
+				importable = false
+			}
+		}
+		return importable
+	}
+
+	for _, expr := range append(fieldTypes(function.Params), fieldTypes(function.Results)...) {
+		ast.Inspect(expr, inspect)
+	}
+
+	return importable
+}
+
+func (s ComponentService) dependencyExists(dependency componentDependency) (bool, error) {
+	filename, err := s.componentPath(dependency.componentType, componentFilename(dependency.componentType, dependency.name))
+	if err != nil {
+		return false, err
+	}
+
+	hasFile, err := s.filesystem.HasDirectoryOrFile(filename)
+	if err != nil {
+		return false, fmt.Errorf("unable to check if %v exists: %v", dependency.Type, err.Error())
+	}
+
+	return hasFile, nil
+}
+
+// Returns the call constructing the component, along with the calls constructing what its
+// constructor takes. Parameters are matched to components by the names of their interfaces, so
+// BooksServiceRepository is given the BooksRepository, looked for in the component's own directory
+// before the top level
+func (s ComponentService) constructorCall(modulePath string, manifest models.Manifest, componentType string, name componentName, constructing []string) (constructorCall, error) {
+	typeName := componentTypeName(componentType, name.identifier)
+	if slices.Contains(constructing, typeName) {
+		return constructorCall{}, fmt.Errorf("%v depends on itself through %v", typeName, strings.Join(constructing, " -> "))
+	}
+	constructing = append(constructing, typeName)
+
+	dir, _ := manifest.Directories.ForComponent(componentType)
+	importPath, importNames := packageImport(modulePath, dir, componentType, name)
+	call := constructorCall{importPath: importPath, importNames: importNames, function: "New" + typeName}
+
+	filename, err := s.componentPath(componentType, componentFilename(componentType, name))
+	if err != nil {
+		return call, err
+	}
+	source, err := s.filesystem.ReadFile(filename)
+	if err != nil {
+		return call, fmt.Errorf("unable to read %v: %v", typeName, err.Error())
+	}
+	file, err := parser.ParseFile(token.NewFileSet(), filename, source, parser.SkipObjectResolution)
+	if err != nil {
+		return call, fmt.Errorf("unable to parse %v: %v", typeName, err)
+	}
+
+	index := slices.IndexFunc(file.Decls, func(decl ast.Decl) bool {
+		function, ok := decl.(*ast.FuncDecl)
+		return ok && function.Recv == nil && function.Name.Name == call.function
+	})
+	if index == -1 {
+		return call, fmt.Errorf("unable to find %v", call.function)
+	}
+
+	for _, param := range file.Decls[index].(*ast.FuncDecl).Type.Params.List {
+		dependency, err := s.paramDependency(componentType, name, typeName, param.Type)
+		if err != nil {
+			return call, fmt.Errorf("unable to work out what to pass %v: %v", call.function, err)
+		}
+
+		arg, err := s.constructorCall(modulePath, manifest, dependency.componentType, dependency.name, constructing)
+		if err != nil {
+			return call, err
+		}
+		for range max(len(param.Names), 1) {
+			call.args = append(call.args, arg)
+		}
+	}
+
+	return call, nil
+}
+
+// Returns the component a constructor parameter of the consumer takes, worked out from the name of
+// the interface it's declared with
+func (s ComponentService) paramDependency(consumerType string, consumer componentName, consumerTypeName string, param ast.Expr) (componentDependency, error) {
+	ident, ok := param.(*ast.Ident)
+	if !ok || !strings.HasPrefix(ident.Name, consumerTypeName) {
+		return componentDependency{}, fmt.Errorf("%v isn't an interface gotm generated", types.ExprString(param))
+	}
+
+	use := strings.TrimPrefix(ident.Name, consumerTypeName)
+	if slices.Contains(dependencyTypes[consumerType], strings.ToLower(use)) {
+		use = consumer.Name + use
+	}
+
+	// Looking for the dependency alongside the consumer, then at the top level, then in the
+	// directories its leading words could be, i.e. admin/UsersRepository for AdminUsersRepository
+	candidates := []string{path.Join(filepath.ToSlash(consumer.dir(true)), use), use}
+	words := nameWords(use)
+	for i := 1; i < len(words)-1; i++ {
+		candidates = append(candidates, path.Join(append(slices.Clone(words[:i]), identifierFromWords(words[i:]).Name)...))
+	}
+
+	for _, candidate := range slices.Compact(candidates) {
+		dependencies, err := s.componentDependencies(consumerType, consumer, []string{candidate})
+		if err == nil && dependencies[0].Interface == ident.Name {
+			return dependencies[0], nil
+		}
+	}
+
+	return componentDependency{}, fmt.Errorf("no %v matches %v", strings.Join(dependencyTypes[consumerType], " or "), ident.Name)
+}
@@ -0,0 +1,209 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/danielronalds/gotm/models"
+	"github.com/danielronalds/gotm/repositories"
+)
+
+func TestParseDependencyNamesInterfaceAfterType(t *testing.T) {
+	// Arrange
+	tests := []struct {
+		consumerType, consumer, use string
+		expectedInterface           string
+		expectedField               string
+	}{
+		{"controller", "books", "BooksService", "BooksControllerService", "service"},
+		{"controller", "books", "users-service", "BooksControllerUsersService", "usersService"},
+		{"service", "books", "admin/UsersRepository", "BooksServiceAdminUsersRepository", "adminUsersRepository"},
+		{"service", "admin/books", "admin/BooksRepository", "BooksServiceRepository", "repository"},
+	}
+
+	for _, test := range tests {
+		// Act
+		dependency, err := parseDependency(test.consumerType, parseComponentName(test.consumer), test.use)
+
+		// Assert
+		if err != nil {
+			t.Fatalf("Failed to parse %v: %v", test.use, err)
+		}
+		if dependency.Interface != test.expectedInterface || dependency.Field != test.expectedField {
+			t.Fatalf("Wanted %v %v, got %v %v", test.expectedField, test.expectedInterface, dependency.Field, dependency.Interface)
+		}
+	}
+}
+
+func TestParseDependencyRejectsOtherLayers(t *testing.T) {
+	// Arrange
+	uses := []string{"BooksRepository", "Books", "BooksController"}
+
+	for _, use := range uses {
+		// Act
+		_, err := parseDependency("controller", parseComponentName("books"), use)
+
+		// Assert
+		if err == nil {
+			t.Fatalf("Expected a controller using %v to fail", use)
+		}
+	}
+}
+
+func TestGenerateServiceRequiresDependenciesToExist(t *testing.T) {
+	// Arrange
+	filesystem := repositories.NewMemoryFilesystemRepository("/project")
+	writeMemoryFile(filesystem, "/project/gotm.json", `{"name": "project"}`, t)
+	componentService := NewComponentService(filesystem, mockTemplates{})
+
+	// Act
+	err := componentService.GenerateService("books", models.ComponentOptions{Uses: []string{"BooksRepository"}})
+
+	// Assert
+	if err == nil {
+		t.Fatal("Expected using a repository that doesn't exist to fail")
+	}
+	if hasFile, _ := filesystem.HasDirectoryOrFile("/project/services/books.go"); hasFile {
+		t.Fatal("Expected the service not to be created")
+	}
+}
+
+func TestConstructorCallConstructsDependencies(t *testing.T) {
+	// Arrange
+	filesystem := repositories.NewMemoryFilesystemRepository("/project")
+	writeMemoryFile(filesystem, "/project/gotm.json", `{"name": "project", "module": "example.com/project"}`, t)
+	for _, dir := range []string{"/project/controllers", "/project/services", "/project/repositories/admin"} {
+		if err := filesystem.CreateDirectory(dir); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeMemoryFile(filesystem, "/project/controllers/books.go", "package controllers\n\nfunc NewBooksController(service BooksControllerService) BooksController {\n\treturn BooksController{service}\n}\n", t)
+	writeMemoryFile(filesystem, "/project/services/books.go", "package services\n\nfunc NewBooksService(repository BooksServiceRepository, users BooksServiceAdminUsersRepository) BooksService {\n\treturn BooksService{repository, users}\n}\n", t)
+	writeMemoryFile(filesystem, "/project/repositories/books.go", "package repositories\n\nfunc NewBooksRepository() BooksRepository {\n\treturn BooksRepository{}\n}\n", t)
+	writeMemoryFile(filesystem, "/project/repositories/admin/users.go", "package admin\n\nfunc NewUsersRepository() UsersRepository {\n\treturn UsersRepository{}\n}\n", t)
+	componentService := NewComponentService(filesystem, mockTemplates{})
+	manifest, _ := filesystem.Manifest()
+	expected := "c.NewBooksController(s.NewBooksService(r.NewBooksRepository(), admin.NewUsersRepository()))"
+
+	// Act
+	call, err := componentService.constructorCall("example.com/project", manifest, "controller", parseComponentName("books"), nil)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Failed to work out the constructor call: %v", err)
+	}
+	if result := call.render(defaultImportNames(call)); result != expected {
+		t.Fatalf("Wanted %v, got %v", expected, result)
+	}
+}
+
+func TestConstructorCallFailsOnUnknownParameters(t *testing.T) {
+	// Arrange
+	filesystem := repositories.NewMemoryFilesystemRepository("/project")
+	writeMemoryFile(filesystem, "/project/gotm.json", `{"name": "project", "module": "example.com/project"}`, t)
+	if err := filesystem.CreateDirectory("/project/controllers"); err != nil {
+		t.Fatal(err)
+	}
+	writeMemoryFile(filesystem, "/project/controllers/books.go", "package controllers\n\nimport \"database/sql\"\n\nfunc NewBooksController(db *sql.DB) BooksController {\n\treturn BooksController{db}\n}\n", t)
+	componentService := NewComponentService(filesystem, mockTemplates{})
+	manifest, _ := filesystem.Manifest()
+
+	// Act
+	_, err := componentService.constructorCall("example.com/project", manifest, "controller", parseComponentName("books"), nil)
+
+	// Assert
+	if err == nil {
+		t.Fatal("Expected a parameter gotm didn't generate to fail")
+	}
+}
+
+func TestGenerateControllerSeedsInterfaceWithMethodsOfUsedService(t *testing.T) {
+	// Arrange
+	root := t.TempDir()
+	t.Setenv(repositories.USER_CONFIG_ENV, filepath.Join(t.TempDir(), "config.json"))
+	for _, dir := range []string{"controllers", "services"} {
+		if err := os.Mkdir(filepath.Join(root, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for filename, contents := range map[string]string{
+		"gotm.json": `{"name": "shop", "module": "shop"}`,
+		"services/books.go": `package services
+
+import (
+	"context"
+
+	"shop/models"
+)
+
+type BooksService struct{}
+
+func (s BooksService) Get(ctx context.Context, id int) (models.Book, error) {
+	return models.Book{}, nil
+}
+
+func (s BooksService) Filter(filter BookFilter) []models.Book {
+	return nil
+}
+
+func (s BooksService) count() int {
+	return 0
+}
+`,
+	} {
+		if err := os.WriteFile(filepath.Join(root, filename), []byte(contents), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	filesystem := repositories.NewFilesystemRepository([]string{}, root)
+	templates := repositories.NewTemplatesRepository(filesystem, repositories.NewUserConfigRepository())
+	componentService := NewComponentService(filesystem, templates)
+	expected := "type AuthorsControllerBooksService interface {\n\tGet(ctx context.Context, id int) (models.Book, error)\n}"
+
+	// Act
+	err := componentService.GenerateController("authors", models.ComponentOptions{Uses: []string{"BooksService"}, NoRegister: true})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Failed to generate controller: %v", err)
+	}
+	contents, err := os.ReadFile(filepath.Join(root, "controllers", "authors.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(contents), expected) {
+		t.Fatalf("Expected the interface to be seeded with Get, got:\n%v", string(contents))
+	}
+	if !strings.Contains(string(contents), "\"context\"\n") || !strings.Contains(string(contents), "\"shop/models\"\n") {
+		t.Fatalf("Expected the packages Get refers to be imported, got:\n%v", string(contents))
+	}
+}
+
+func TestGenerateControllerSaysWhatToAddWhenUsedServiceHasNoMethods(t *testing.T) {
+	// Arrange
+	root := t.TempDir()
+	t.Setenv(repositories.USER_CONFIG_ENV, filepath.Join(t.TempDir(), "config.json"))
+	if err := os.WriteFile(filepath.Join(root, "gotm.json"), []byte(`{"name": "shop", "module": "shop"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	filesystem := repositories.NewFilesystemRepository([]string{}, root)
+	templates := repositories.NewTemplatesRepository(filesystem, repositories.NewUserConfigRepository())
+	componentService := NewComponentService(filesystem, templates)
+	if err := componentService.GenerateService("books", models.ComponentOptions{}); err != nil {
+		t.Fatalf("Failed to generate service: %v", err)
+	}
+
+	// Act
+	err := componentService.GenerateController("books", models.ComponentOptions{Uses: []string{"BooksService"}, NoRegister: true})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Failed to generate controller: %v", err)
+	}
+	contents, _ := os.ReadFile(filepath.Join(root, "controllers", "books.go"))
+	if !strings.Contains(string(contents), "type BooksControllerService interface {\n\t// Add the methods of BooksService this controller uses\n}") {
+		t.Fatalf("Expected the empty interface to say what to add, got:\n%v", string(contents))
+	}
+}
@@ -0,0 +1,235 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/danielronalds/gotm/models"
+)
+
+// Modules that only build with cgo, so projects requiring them are built with a C toolchain
+var cgoModules = []string{"github.com/mattn/go-sqlite3"}
+
+// sqlite drivers written in pure Go, which spare sqlite projects from needing cgo
+var pureGoSqliteModules = []string{"modernc.org/sqlite", "github.com/ncruces/go-sqlite3", "github.com/glebarez/go-sqlite"}
+
+var goDirectiveRegex = regexp.MustCompile(`(?m)^go\s+(\d+(?:\.\d+){0,2})\s*$`)
+var toolchainDirectiveRegex = regexp.MustCompile(`(?m)^toolchain\s+go(\d+(?:\.\d+){0,2})\s*$`)
+var requireRegex = regexp.MustCompile(`(?m)^(?:require\s+)?\s*(\S+)\s+v\d\S*`)
+
+// Data passed to the Dockerfile and .dockerignore templates, worked out from the project so the
+// image builds without changes
+type dockerfileTemplateData struct {
+	// Tag of the golang image the backend is built with, i.e. 1.23.5-alpine
+	GoImage string
+	// Whether the backend is built with cgo, which sqlite drivers like go-sqlite3 need
+	CGO bool
+	// Top level directories containing the project's Go packages
+	Packages []string
+	// Whether the project root contains Go files
+	RootFiles bool
+	// Package built into the server, i.e. . or ./cmd/server
+	Main string
+	Port int
+	// Directory containing the frontend, empty if the project doesn't have one
+	Frontend string
+	// Files of the sqlite database, kept out of the image, i.e. app.db
+	Database  string
+	DevBinary string
+}
+
+// Works out the data the Dockerfile is rendered with from the project's manifest, go.mod and Go
+// files, which are slash separated and relative to the project root
+func newDockerfileTemplateData(manifest models.Manifest, goMod string, goFiles []string, hasFrontend bool) dockerfileTemplateData {
+	data := dockerfileTemplateData{
+		GoImage:   "alpine",
+		Main:      "./" + path.Clean(filepath.ToSlash(manifest.Main)),
+		Port:      manifest.Port,
+		DevBinary: manifest.DevBinary,
+		Packages:  make([]string, 0),
+	}
+
+	// The toolchain the project asks for is preferred over the minimum version it supports
+	if matches := toolchainDirectiveRegex.FindStringSubmatch(goMod); matches != nil {
+		data.GoImage = matches[1] + "-alpine"
+	} else if matches := goDirectiveRegex.FindStringSubmatch(goMod); matches != nil {
+		data.GoImage = matches[1] + "-alpine"
+	}
+	if data.Main == "./." {
+		data.Main = "."
+	}
+
+	for _, file := range goFiles {
+		dir, _, nested := strings.Cut(file, "/")
+		if !nested {
+			data.RootFiles = true
+		} else if !slices.Contains(data.Packages, dir) {
+			data.Packages = append(data.Packages, dir)
+		}
+	}
+	slices.Sort(data.Packages)
+
+	if hasFrontend {
+		data.Frontend = path.Clean(filepath.ToSlash(manifest.Frontend))
+	}
+
+	requires := make([]string, 0)
+	for _, matches := range requireRegex.FindAllStringSubmatch(goMod, -1) {
+		requires = append(requires, matches[1])
+	}
+
+	usesSqlite := manifest.Database != nil && manifest.Database.Engine == "sqlite"
+	data.CGO = slices.ContainsFunc(requires, func(module string) bool { return slices.Contains(cgoModules, module) })
+	if usesSqlite && !slices.ContainsFunc(requires, func(module string) bool { return slices.Contains(pureGoSqliteModules, module) }) {
+		data.CGO = true
+	}
+	if usesSqlite && !filepath.IsAbs(manifest.Database.Location) {
+		data.Database = path.Clean(filepath.ToSlash(manifest.Database.Location))
+	}
+
+	return data
+}
+
+// Generates a Dockerfile for the project along with a .dockerignore, unless the project already
+// has one, returning the files that were created
+func (s ComponentService) GenerateDockerfile() ([]string, error) {
+	dockerfile, err := s.filesystem.FromRoot("Dockerfile")
+	if err != nil {
+		return nil, err
+	}
+
+	hasFile, err := s.filesystem.HasDirectoryOrFile(dockerfile)
+	if err != nil {
+		return nil, fmt.Errorf("unable to check if Dockerfile already exists: %v", err.Error())
+	}
+	if hasFile {
+		return nil, errors.New("Dockerfile already exists")
+	}
+
+	data, err := s.dockerfileTemplateData()
+	if err != nil {
+		return nil, err
+	}
+
+	created := []string{"Dockerfile"}
+	if err := s.renderProjectFile(dockerfile, "Dockerfile.tmpl", data); err != nil {
+		return nil, err
+	}
+
+	dockerignore, err := s.filesystem.FromRoot(".dockerignore")
+	if err != nil {
+		return created, err
+	}
+	hasFile, err = s.filesystem.HasDirectoryOrFile(dockerignore)
+	if err != nil {
+		return created, fmt.Errorf("unable to check if .dockerignore already exists: %v", err.Error())
+	}
+	if hasFile {
+		return created, nil
+	}
+
+	if err := s.renderProjectFile(dockerignore, ".dockerignore.tmpl", data); err != nil {
+		return created, err
+	}
+
+	return append(created, ".dockerignore"), nil
+}
+
+// Inspects the project for what its image needs
+func (s ComponentService) dockerfileTemplateData() (dockerfileTemplateData, error) {
+	manifest, err := s.filesystem.Manifest()
+	if err != nil {
+		return dockerfileTemplateData{}, fmt.Errorf("unable to read project manifest: %v", err.Error())
+	}
+
+	goMod, err := s.filesystem.FromRoot("go.mod")
+	if err != nil {
+		return dockerfileTemplateData{}, err
+	}
+	goModContents, err := s.filesystem.ReadFile(goMod)
+	if err != nil {
+		return dockerfileTemplateData{}, fmt.Errorf("unable to read go.mod: %v", err.Error())
+	}
+
+	goFiles, err := projectGoFiles(s.filesystem, manifest)
+	if err != nil {
+		return dockerfileTemplateData{}, err
+	}
+
+	packageJSON, err := s.filesystem.FromRoot(filepath.Join(manifest.Frontend, "package.json"))
+	if err != nil {
+		return dockerfileTemplateData{}, err
+	}
+	hasFrontend, err := s.filesystem.HasDirectoryOrFile(packageJSON)
+	if err != nil {
+		return dockerfileTemplateData{}, fmt.Errorf("unable to check if the frontend exists: %v", err.Error())
+	}
+
+	data := newDockerfileTemplateData(manifest, goModContents, goFiles, hasFrontend)
+	if !data.CGO {
+		if data.CGO, err = s.importsC(goFiles); err != nil {
+			return dockerfileTemplateData{}, err
+		}
+	}
+
+	return data, nil
+}
+
+// Returns whether any of the Go files use cgo themselves, by importing "C"
+func (s ComponentService) importsC(goFiles []string) (bool, error) {
+	root, err := s.filesystem.Root()
+	if err != nil {
+		return false, err
+	}
+
+	fileset := token.NewFileSet()
+	for _, relative := range goFiles {
+		contents, err := s.filesystem.ReadFile(filepath.Join(root, filepath.FromSlash(relative)))
+		if err != nil {
+			return false, fmt.Errorf("unable to read %v: %v", relative, err.Error())
+		}
+
+		// Files that don't parse are left for the build to complain about
+		file, err := parser.ParseFile(fileset, relative, contents, parser.ImportsOnly)
+		if err != nil {
+			continue
+		}
+		for _, spec := range file.Imports {
+			if importPath, _ := strconv.Unquote(spec.Path.Value); importPath == "C" {
+				return true, nil
+			}
+		}
+	}
+
+	return false, nil
+}
+
+// Renders the template into a file at the project root, which isn't a component so it's left out
+// of the ledger of generated files
+func (s ComponentService) renderProjectFile(filename, templateName string, data any) error {
+	var contents bytes.Buffer
+	if err := s.templates.WriteTemplate(&contents, templateName, data); err != nil {
+		return fmt.Errorf("unable to write template: %v", err.Error())
+	}
+
+	file, err := s.filesystem.CreateFile(filename)
+	if err != nil {
+		return fmt.Errorf("unable to create %v: %v", filepath.Base(filename), err.Error())
+	}
+	defer file.Close()
+
+	if _, err := file.Write(contents.Bytes()); err != nil {
+		return fmt.Errorf("unable to write %v: %v", filepath.Base(filename), err.Error())
+	}
+
+	return nil
+}
@@ -0,0 +1,114 @@
+package services
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/danielronalds/gotm/models"
+	"github.com/danielronalds/gotm/repositories"
+)
+
+const testDockerGoMod = `module example.com/project
+
+go 1.22
+
+toolchain go1.23.4
+
+require (
+	github.com/mattn/go-sqlite3 v1.14.24
+	golang.org/x/sys v0.22.0 // indirect
+)
+`
+
+func TestNewDockerfileTemplateDataInspectsProject(t *testing.T) {
+	// Arrange
+	manifest := models.DefaultManifest("project", "example.com/project")
+	goFiles := []string{"main.go", "controllers/books.go", "controllers/admin/users.go", "internal/auth/auth.go"}
+
+	// Act
+	data := newDockerfileTemplateData(manifest, testDockerGoMod, goFiles, true)
+
+	// Assert
+	if data.GoImage != "1.23.4-alpine" {
+		t.Fatalf("Wanted 1.23.4-alpine, got %v", data.GoImage)
+	}
+	if !slices.Equal(data.Packages, []string{"controllers", "internal"}) || !data.RootFiles {
+		t.Fatalf("Wanted [controllers internal] and the root files, got %v and %v", data.Packages, data.RootFiles)
+	}
+	if !data.CGO {
+		t.Fatal("Expected go-sqlite3 to need cgo")
+	}
+	if data.Main != "." || data.Port != 3000 || data.Frontend != "frontend" {
+		t.Fatalf("Unexpected main, port or frontend: %v %v %v", data.Main, data.Port, data.Frontend)
+	}
+}
+
+func TestNewDockerfileTemplateDataOnlyUsesCgoForSqlite(t *testing.T) {
+	// Arrange
+	manifest := models.DefaultManifest("project", "example.com/project")
+	sqliteManifest := models.DefaultManifest("project", "example.com/project")
+	sqliteManifest.Database = models.DefaultDatabase()
+	tests := []struct {
+		manifest models.Manifest
+		goMod    string
+		expected bool
+	}{
+		{manifest, "module example.com/project\n\ngo 1.23\n", false},
+		{sqliteManifest, "module example.com/project\n\ngo 1.23\n", true},
+		{sqliteManifest, "module example.com/project\n\ngo 1.23\n\nrequire modernc.org/sqlite v1.34.5\n", false},
+	}
+
+	for _, test := range tests {
+		// Act
+		data := newDockerfileTemplateData(test.manifest, test.goMod, []string{"main.go"}, false)
+
+		// Assert
+		if data.CGO != test.expected {
+			t.Fatalf("Wanted cgo to be %v for %v", test.expected, test.goMod)
+		}
+	}
+}
+
+func TestGenerateDockerfileKeepsExistingDockerignore(t *testing.T) {
+	// Arrange
+	filesystem := repositories.NewMemoryFilesystemRepository("/project")
+	writeMemoryFile(filesystem, "/project/gotm.json", `{"name": "project"}`, t)
+	writeMemoryFile(filesystem, "/project/go.mod", "module project\n\ngo 1.23.5\n", t)
+	writeMemoryFile(filesystem, "/project/main.go", "package main\n", t)
+	writeMemoryFile(filesystem, "/project/.dockerignore", "node_modules\n", t)
+	componentService := NewComponentService(filesystem, mockTemplates{})
+
+	// Act
+	created, err := componentService.GenerateDockerfile()
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Failed to generate dockerfile: %v", err)
+	}
+	if !slices.Equal(created, []string{"Dockerfile"}) {
+		t.Fatalf("Wanted [Dockerfile], got %v", created)
+	}
+	if contents, _ := filesystem.ReadFile("/project/.dockerignore"); contents != "node_modules\n" {
+		t.Fatalf("Expected .dockerignore to be left alone, got %v", contents)
+	}
+}
+
+func TestGenerateDockerfileUsesCgoWhenProjectImportsC(t *testing.T) {
+	// Arrange
+	filesystem := repositories.NewMemoryFilesystemRepository("/project")
+	writeMemoryFile(filesystem, "/project/gotm.json", `{"name": "project"}`, t)
+	writeMemoryFile(filesystem, "/project/go.mod", "module project\n\ngo 1.23.5\n", t)
+	writeMemoryFile(filesystem, "/project/main.go", "package main\n\n// #include <stdio.h>\nimport \"C\"\n", t)
+	componentService := NewComponentService(filesystem, mockTemplates{})
+
+	// Act
+	data, err := componentService.dockerfileTemplateData()
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Failed to inspect project: %v", err)
+	}
+	if !data.CGO {
+		t.Fatal("Expected importing C to need cgo")
+	}
+}
@@ -1,6 +1,13 @@
 package services
 
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
 type FilewatcherServiceFilesystem interface {
+	ProjectManifest
 	DirReader
 	FileReader
 }
@@ -16,13 +23,45 @@ func NewFilewatcherService(filesystem FilewatcherServiceFilesystem) FilewatcherS
 	return FilewatcherService{filesystem, cache}
 }
 
+// Returns the files in the given directory, excluding those in the manifests ignored directories
+func (s FilewatcherService) watchedFiles(directory string) ([]string, error) {
+	manifest, err := s.filesystem.Manifest()
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := s.filesystem.ReadDirRecursive(directory)
+	if err != nil {
+		return nil, err
+	}
+
+	watched := make([]string, 0, len(files))
+	for _, file := range files {
+		if !isInIgnoredDir(file, manifest.IgnoredDirs) {
+			watched = append(watched, file)
+		}
+	}
+
+	return watched, nil
+}
+
+func isInIgnoredDir(file string, ignoredDirs []string) bool {
+	for _, part := range strings.Split(filepath.ToSlash(filepath.Dir(file)), "/") {
+		if slices.Contains(ignoredDirs, part) {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (s FilewatcherService) UpdateCache(directory string) error {
 	// Cleaning cache
 	for k := range s.cache {
 		delete(s.cache, k)
 	}
 
-	projectFiles, err := s.filesystem.ReadDirRecursive(directory)
+	projectFiles, err := s.watchedFiles(directory)
 	if err != nil {
 		return err
 	}
@@ -42,7 +81,7 @@ func (s FilewatcherService) UpdateCache(directory string) error {
 func (s FilewatcherService) HaveFilesChanged(directory string) ([]string, error) {
 	filesChanged := make([]string, 0)
 
-	projectFiles, err := s.filesystem.ReadDirRecursive(directory)
+	projectFiles, err := s.watchedFiles(directory)
 	if err != nil {
 		return filesChanged, err
 	}
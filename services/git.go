@@ -0,0 +1,53 @@
+package services
+
+import (
+	"fmt"
+	"path/filepath"
+)
+
+type GitServiceFilesystem interface {
+	ProjectRoot
+	DirReader
+}
+
+// Service for managing the git repository of a project
+type GitService struct {
+	filesystem GitServiceFilesystem
+	shell      CmdRunner
+}
+
+func NewGitService(filesystem GitServiceFilesystem, shell CmdRunner) GitService {
+	return GitService{filesystem, shell}
+}
+
+// Initialises a git repository in the project root, unless it already is one
+func (s GitService) InitRepository() error {
+	root, err := s.filesystem.Root()
+	if err != nil {
+		return err
+	}
+
+	isRepository, err := s.filesystem.HasDirectoryOrFile(filepath.Join(root, ".git"))
+	if err != nil {
+		return fmt.Errorf("unable to check for existing repository: %v", err)
+	}
+	if isRepository {
+		return nil
+	}
+
+	return s.shell.RunCmdWithPipedOutput(root, "git", "init", "--quiet")
+}
+
+// Stages every file in the project and commits them with the given message
+func (s GitService) CommitAll(message string) error {
+	root, err := s.filesystem.Root()
+	if err != nil {
+		return err
+	}
+
+	if err := s.shell.RunCmdWithPipedOutput(root, "git", "add", "-A"); err != nil {
+		return fmt.Errorf("unable to stage files: %v", err)
+	}
+
+	return s.shell.RunCmdWithPipedOutput(root, "git", "commit", "--quiet", "-m", message)
+}
@@ -1,14 +1,82 @@
 package services
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"path/filepath"
+	"slices"
 	"strings"
+
+	"github.com/danielronalds/gotm/models"
 )
 
 type InitialiserServiceFilesystem interface {
 	DirCreater
 	DirReader
+	FileReader
+	FileWriter
 	FileCreater
+	FileDeleter
+	FileRenamer
+	FileModeChanger
+	TempDirCreater
+}
+
+// Directories every new project contains
+var projectDirectories = []string{
+	"controllers",
+	"frontend",
+	"frontend/src",
+	"frontend/src/models",
+	"frontend/src/views",
+	"frontend/src/views/pages",
+}
+
+// Files every new project contains, each rendered from the template sharing its filename
+var projectFiles = []string{
+	".gitignore",
+	// Backend stuff
+	"go.mod",
+	"main.go",
+	"controllers/hello.go",
+	// Frontend stuff
+	"frontend/favicon.ico",
+	"frontend/global.css",
+	"frontend/index.html",
+	"frontend/package.json",
+	"frontend/package-lock.json",
+	"frontend/tailwind.config.js",
+	"frontend/tsconfig.json",
+	"frontend/src/index.ts",
+	"frontend/src/models/hello.ts",
+	"frontend/src/views/Button.ts",
+	"frontend/src/views/pages/HomePage.ts",
+}
+
+// Components of the optional starter pages, keyed by the name they're chosen with
+var starterPageComponents = map[string]string{
+	"about":    "AboutPage",
+	"notfound": "NotFoundPage",
+}
+
+// Returns the optional files the manifest asks for, each rendered from the template sharing its
+// filename
+func optionalProjectFiles(manifest models.Manifest) []string {
+	files := make([]string, 0)
+
+	if manifest.Database != nil {
+		files = append(files, "sqlc.yml")
+	}
+	if manifest.Dockerfile {
+		files = append(files, "Dockerfile", ".dockerignore")
+	}
+	for _, page := range manifest.Pages {
+		files = append(files, fmt.Sprintf("frontend/src/views/pages/%v.ts", starterPageComponents[page]))
+	}
+
+	return files
 }
 
 // Service for handling initialising new projects
@@ -21,81 +89,290 @@ func NewInitialiserService(filesystem InitialiserServiceFilesystem, templates Te
 	return InitialiserService{filesystem, templates}
 }
 
-func (s InitialiserService) InitProject(username, projectName, projectDir string) error {
-	// Only create a directory if we're not working in the current dir
-	if projectDir != "." {
-		if hasDir, err := s.filesystem.HasDirectoryOrFile(projectDir); err != nil || hasDir {
-			return fmt.Errorf("%v already exists in this directory", projectDir)
+// Creates a new project in projectDir. The project is rendered into a staging directory first and
+// only moved into place once every file has been written, so a failed or cancelled init leaves
+// nothing behind
+func (s InitialiserService) InitProject(ctx context.Context, manifest models.Manifest, projectDir string) error {
+	inPlace := projectDir == "."
+
+	for _, page := range manifest.Pages {
+		if _, ok := starterPageComponents[page]; !ok {
+			return fmt.Errorf("\"%v\" is not a starter page, expected one of %v", page, strings.Join(models.StarterPages, ", "))
+		}
+	}
+
+	if inPlace {
+		if err := s.checkForConflicts(manifest, projectDir); err != nil {
+			return err
+		}
+	} else if hasDir, err := s.filesystem.HasDirectoryOrFile(projectDir); err != nil || hasDir {
+		return fmt.Errorf("%v already exists in this directory", projectDir)
+	}
+
+	stagingPattern := fmt.Sprintf(".%v-gotm-*", filepath.Base(projectDir))
+	if inPlace {
+		stagingPattern = ".gotm-init-*"
+	}
+
+	stagingDir, err := s.filesystem.CreateTempDirectory(filepath.Dir(projectDir), stagingPattern)
+	if err != nil {
+		return fmt.Errorf("failed to create staging directory: %v", err)
+	}
+
+	if err := s.renderProject(ctx, manifest, stagingDir); err != nil {
+		return s.rollback(stagingDir, err)
+	}
+
+	// Last chance to bail out before anything outside of the staging directory is touched
+	if err := ctx.Err(); err != nil {
+		return s.rollback(stagingDir, err)
+	}
+
+	if !inPlace {
+		// Temporary directories are only accessible by their owner, which the project shouldn't be
+		if err := s.filesystem.Chmod(stagingDir, 0755); err != nil {
+			return s.rollback(stagingDir, fmt.Errorf("failed to set the project's permissions: %v", err))
+		}
+		if err := s.filesystem.Rename(stagingDir, projectDir); err != nil {
+			return s.rollback(stagingDir, fmt.Errorf("failed to move project into place: %v", err))
+		}
+		return nil
+	}
+
+	if err := s.moveIntoPlace(stagingDir, projectDir, s.projectDirectories(manifest), s.projectFiles(manifest)); err != nil {
+		return s.rollback(stagingDir, err)
+	}
+
+	return s.filesystem.DeleteFileRecursive(stagingDir)
+}
+
+// Returns the paths, relative to the project directory, of every directory created by InitProject,
+// including those that start out empty
+func (s InitialiserService) projectDirectories(manifest models.Manifest) []string {
+	directories := slices.Clone(projectDirectories)
+	if manifest.Database != nil {
+		directories = append(directories, manifest.Directories.Migrations, manifest.Directories.Queries)
+	}
+	return directories
+}
+
+// Returns the paths, relative to the project directory, of every file created by InitProject
+func (s InitialiserService) projectFiles(manifest models.Manifest) []string {
+	files := append([]string{models.MANIFEST_FILENAME}, projectFiles...)
+	return append(files, optionalProjectFiles(manifest)...)
+}
+
+// Checks that none of the project files already exist, reporting all of the conflicts at once
+func (s InitialiserService) checkForConflicts(manifest models.Manifest, projectDir string) error {
+	conflicts := make([]string, 0)
+
+	for _, file := range s.projectFiles(manifest) {
+		hasFile, err := s.filesystem.HasDirectoryOrFile(filepath.Join(projectDir, file))
+		if err != nil {
+			return fmt.Errorf("unable to check if %v exists: %v", file, err)
+		}
+		if hasFile {
+			conflicts = append(conflicts, file)
 		}
+	}
+
+	if len(conflicts) != 0 {
+		return fmt.Errorf("the following files already exist:\n  %v\nuse --adopt to add gotm to an existing project", strings.Join(conflicts, "\n  "))
+	}
 
-		if err := s.filesystem.CreateDirectory(projectDir); err != nil {
+	return nil
+}
+
+// Writes every directory and file of the project into dir, which must already exist
+func (s InitialiserService) renderProject(ctx context.Context, manifest models.Manifest, dir string) error {
+	for _, projectDir := range s.projectDirectories(manifest) {
+		if err := s.filesystem.CreateDirectory(filepath.Join(dir, projectDir)); err != nil {
 			return fmt.Errorf("failed to create '%v' directory", projectDir)
 		}
 	}
 
-	// Creating required directories
-	directories := []string{
-		"controllers",
-		"frontend",
-		"frontend/src",
-		"frontend/src/models",
-		"frontend/src/views",
-		"frontend/src/views/pages",
+	config := newProjectTemplateConfig(manifest)
+
+	for _, file := range append(slices.Clone(projectFiles), optionalProjectFiles(manifest)...) {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		var data any = config
+		if file == "Dockerfile" || file == ".dockerignore" {
+			dockerfileData, err := s.dockerfileTemplateData(manifest, dir)
+			if err != nil {
+				return err
+			}
+			data = dockerfileData
+		}
+
+		template := fmt.Sprintf("%v.tmpl", filepath.Base(file))
+		if err := s.renderFile(filepath.Join(dir, file), template, data); err != nil {
+			return fmt.Errorf("failed to create '%v' file: %v", file, err)
+		}
+	}
+
+	return s.writeManifest(manifest, dir)
+}
+
+// Data passed to every template rendered when creating a project
+type projectTemplateConfig struct {
+	ProjectName string
+	ModulePath  string
+	Port        int
+	Database    *models.Database
+	Directories models.Directories
+	Pages       []starterPage
+}
+
+// A starter page along with the route it's served on
+type starterPage struct {
+	Component string
+	Route     string
+}
+
+func newProjectTemplateConfig(manifest models.Manifest) projectTemplateConfig {
+	pages := make([]starterPage, 0, len(manifest.Pages))
+	for _, page := range manifest.Pages {
+		if page != "notfound" {
+			pages = append(pages, starterPage{starterPageComponents[page], fmt.Sprintf("/%v", page)})
+		}
+	}
+	// Mithril's catch all route, matching any path not matched by another route. Routes are tried in
+	// order, so it has to come last
+	if slices.Contains(manifest.Pages, "notfound") {
+		pages = append(pages, starterPage{starterPageComponents["notfound"], "/:404..."})
+	}
+
+	return projectTemplateConfig{
+		ProjectName: manifest.Name,
+		ModulePath:  manifest.Module,
+		Port:        manifest.Port,
+		Database:    manifest.Database,
+		Directories: manifest.Directories,
+		Pages:       pages,
+	}
+}
+
+// Returns the data the Dockerfile of the project rendered into dir is rendered with, from its
+// go.mod and the Go files every project starts with
+func (s InitialiserService) dockerfileTemplateData(manifest models.Manifest, dir string) (dockerfileTemplateData, error) {
+	goMod, err := s.filesystem.ReadFile(filepath.Join(dir, "go.mod"))
+	if err != nil {
+		return dockerfileTemplateData{}, fmt.Errorf("unable to read go.mod: %v", err)
+	}
+
+	goFiles := make([]string, 0)
+	for _, file := range projectFiles {
+		if filepath.Ext(file) == ".go" {
+			goFiles = append(goFiles, file)
+		}
+	}
+
+	return newDockerfileTemplateData(manifest, goMod, goFiles, true), nil
+}
+
+func (s InitialiserService) renderFile(filename, template string, data any) error {
+	file, err := s.filesystem.CreateFile(filename)
+	if err != nil {
+		return err
+	}
+
+	if err := s.templates.WriteTemplate(file, template, data); err != nil {
+		file.Close()
+		return fmt.Errorf("unable to write template: %v", err.Error())
+	}
+
+	return file.Close()
+}
+
+func (s InitialiserService) writeManifest(manifest models.Manifest, projectDir string) error {
+	file, err := s.filesystem.CreateFile(filepath.Join(projectDir, models.MANIFEST_FILENAME))
+	if err != nil {
+		return fmt.Errorf("failed to create '%v' file", models.MANIFEST_FILENAME)
+	}
+	defer file.Close()
+
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(manifest); err != nil {
+		return fmt.Errorf("unable to write project manifest: %v", err)
+	}
+
+	return nil
+}
+
+// Creates each of the project's directories in the project directory, along with any missing
+// parents, then moves each staged file into place. If any of them fail the files and directories
+// already moved or created are removed
+func (s InitialiserService) moveIntoPlace(stagingDir, projectDir string, directories, files []string) error {
+	moved := make([]string, 0)
+	createdDirs := make([]string, 0)
+
+	undo := func(err error) error {
+		for _, file := range moved {
+			s.filesystem.DeleteFileRecursive(file)
+		}
+		// Removing the deepest directories first
+		for i := len(createdDirs) - 1; i >= 0; i-- {
+			s.filesystem.DeleteFileRecursive(createdDirs[i])
+		}
+		return err
+	}
+
+	createDirectory := func(target string) error {
+		missingDirs := make([]string, 0)
+		for dir := target; dir != filepath.Dir(dir); dir = filepath.Dir(dir) {
+			hasDir, err := s.filesystem.HasDirectoryOrFile(dir)
+			if err != nil {
+				return fmt.Errorf("unable to check if %v exists: %v", dir, err)
+			}
+			if hasDir {
+				break
+			}
+			missingDirs = append(missingDirs, dir)
+		}
+
+		// CreateDirectory creates any missing parents along with the directory itself
+		if len(missingDirs) != 0 {
+			if err := s.filesystem.CreateDirectory(target); err != nil {
+				return fmt.Errorf("failed to create '%v' directory", target)
+			}
+			slices.Reverse(missingDirs)
+			createdDirs = append(createdDirs, missingDirs...)
+		}
+
+		return nil
 	}
 
+	// Directories without files, like the migrations directory, would otherwise be left behind
 	for _, dir := range directories {
-		dirWithProject := fmt.Sprintf("%v/%v", projectDir, dir)
-
-		if err := s.filesystem.CreateDirectory(dirWithProject); err != nil {
-			return fmt.Errorf("failed to create '%v' directory", dirWithProject)
-		}
-	}
-
-	// Creating files
-	files := []string{
-		".gitignore",
-		// Backend stuff
-		"go.mod",
-		"main.go",
-		"controllers/hello.go",
-		// Frontend stuff
-		"frontend/favicon.ico",
-		"frontend/global.css",
-		"frontend/index.html",
-		"frontend/package.json",
-		"frontend/package-lock.json",
-		"frontend/tailwind.config.js",
-		"frontend/tsconfig.json",
-		"frontend/src/index.ts",
-		"frontend/src/models/hello.ts",
-		"frontend/src/views/Button.ts",
-		"frontend/src/views/pages/HomePage.ts",
-	}
-
-	config := struct {
-		ProjectName    string
-		GithubUsername string
-	}{
-		ProjectName:    projectName,
-		GithubUsername: username,
-	}
-
-	for _, filepath := range files {
-		filepathWithProject := fmt.Sprintf("%v/%v", projectDir, filepath)
-		file, err := s.filesystem.CreateFile(filepathWithProject)
-		if err != nil {
-			return fmt.Errorf("failed to create '%v' file", filepath)
+		if err := createDirectory(filepath.Join(projectDir, dir)); err != nil {
+			return undo(err)
 		}
-		defer file.Close()
+	}
 
-		parts := strings.Split(filepath, "/")
-		filename := parts[len(parts)-1] // Last part will be the file
-		template := fmt.Sprintf("%v.tmpl", filename)
+	for _, file := range files {
+		target := filepath.Join(projectDir, file)
+		if err := createDirectory(filepath.Dir(target)); err != nil {
+			return undo(err)
+		}
 
-		if err := s.templates.WriteTemplate(file, template, config); err != nil {
-			return fmt.Errorf("unable to write template: %v", err.Error())
+		if err := s.filesystem.Rename(filepath.Join(stagingDir, file), target); err != nil {
+			return undo(fmt.Errorf("failed to move '%v' into place: %v", file, err))
 		}
+		moved = append(moved, target)
 	}
 
 	return nil
 }
+
+// Removes the staging directory, returning the error that caused the rollback
+func (s InitialiserService) rollback(stagingDir string, cause error) error {
+	if err := s.filesystem.DeleteFileRecursive(stagingDir); err != nil {
+		return errors.Join(cause, fmt.Errorf("failed to clean up %v: %v", stagingDir, err))
+	}
+
+	return cause
+}
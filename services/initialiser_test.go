@@ -1,76 +1,58 @@
 package services
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
-)
 
-// Mock implementation of the filesystem, does not do any error checking
-type mockFilesystem struct {
-	hasDirectoryOrFileReturn bool
-}
+	"github.com/danielronalds/gotm/models"
+	"github.com/danielronalds/gotm/repositories"
+)
 
-func (m mockFilesystem) ReadDirRecursive(directory string) ([]string, error) {
-	return make([]string, 0), nil
-}
-func (m mockFilesystem) ReadFile(filename string) (string, error) {
-	return "Mock contents", nil
-}
+// Mock implementation of templates repository, just writes "mock-data" to the given file
+type mockTemplates struct{}
 
-func (m mockFilesystem) HasDirectoryOrFile(directory string) (bool, error) {
-	return m.hasDirectoryOrFileReturn, nil
+func (m mockTemplates) WriteTemplate(wr io.Writer, name string, data any) error {
+	_, err := wr.Write([]byte("mock-data"))
+	return err
 }
 
-func (m mockFilesystem) CreateDirectory(directory string) error {
-	return os.Mkdir(directory, 0755)
+// Mock implementation of templates repository that fails to write the given template
+type failingTemplates struct {
+	failOn string
 }
 
-func (m mockFilesystem) CreateFile(filename string) (*os.File, error) {
-	file, err := os.Create(filename)
-	if err != nil {
-		panic(err)
+func (m failingTemplates) WriteTemplate(wr io.Writer, name string, data any) error {
+	if name == m.failOn {
+		return errors.New("mock failure")
 	}
-	return file, nil
-}
-
-func (m mockFilesystem) DeleteFileRecursive(filename string) error {
-	return os.RemoveAll(filename)
-}
-
-func (m mockFilesystem) Root() string {
-	return "."
-}
-
-func (m mockFilesystem) FromRoot(path string) string {
-	return fmt.Sprintf("./%v", strings.TrimPrefix(path, "/"))
-}
-
-// Mock implementation of templates repository, just writes "mock-data" to the given file
-type mockTemplates struct{}
-
-func (m mockTemplates) WriteTemplate(wr io.Writer, name string, data any) error {
 	_, err := wr.Write([]byte("mock-data"))
 	return err
 }
 
 func TestInitialiseProjectCreatesExpectedFiles(t *testing.T) {
 	// Arrange
-	filesystem := mockFilesystem{hasDirectoryOrFileReturn: false}
+	filesystem := repositories.NewMemoryFilesystemRepository("/project")
 	templates := mockTemplates{}
 	initService := NewInitialiserService(filesystem, templates)
 
-	username := "mock-user"
-	projectName := "testproject"
+	manifest := models.DefaultManifest("testproject", "github.com/mock-user/testproject")
 	projectDir := "testproject"
 
 	// Act
-	initService.InitProject(username, projectName, projectDir)
+	if err := initService.InitProject(context.Background(), manifest, projectDir); err != nil {
+		t.Fatalf("Failed to initialise project: %v", err)
+	}
 
 	// Assert
 	expectedFiles := []string{
+		"gotm.json",
 		".gitignore",
 		"go.mod",
 		"main.go",
@@ -89,32 +71,25 @@ func TestInitialiseProjectCreatesExpectedFiles(t *testing.T) {
 	}
 
 	for _, file := range expectedFiles {
-		_, err := os.Stat(fmt.Sprintf("%v/%v", projectDir, file))
-		if err != nil {
-			if removalErr := os.RemoveAll(projectDir); removalErr != nil {
-				panic(removalErr)
-			}
-			panic(err)
+		if hasFile, _ := filesystem.HasDirectoryOrFile(fmt.Sprintf("%v/%v", projectDir, file)); !hasFile {
+			t.Fatalf("Expected %v to be created", file)
 		}
 	}
-
-	if err := os.RemoveAll(projectDir); err != nil {
-		panic(err)
-	}
 }
 
 func TestInitialiseProjectCreatesExpectedFilesIfProjectNameDiffersFromDirName(t *testing.T) {
 	// Arrange
-	filesystem := mockFilesystem{hasDirectoryOrFileReturn: false}
+	filesystem := repositories.NewMemoryFilesystemRepository("/project")
 	templates := mockTemplates{}
 	initService := NewInitialiserService(filesystem, templates)
 
-	username := "mock-user"
-	projectName := "testproject"
+	manifest := models.DefaultManifest("testproject", "github.com/mock-user/testproject")
 	projectDir := "differentdirectory"
 
 	// Act
-	initService.InitProject(username, projectName, projectDir)
+	if err := initService.InitProject(context.Background(), manifest, projectDir); err != nil {
+		t.Fatalf("Failed to initialise project: %v", err)
+	}
 
 	// Assert
 	expectedFiles := []string{
@@ -135,16 +110,206 @@ func TestInitialiseProjectCreatesExpectedFilesIfProjectNameDiffersFromDirName(t
 	}
 
 	for _, file := range expectedFiles {
-		_, err := os.Stat(fmt.Sprintf("%v/%v", projectDir, file))
-		if err != nil {
-			if removalErr := os.RemoveAll(projectDir); removalErr != nil {
-				panic(removalErr)
-			}
-			panic(err)
+		if hasFile, _ := filesystem.HasDirectoryOrFile(fmt.Sprintf("%v/%v", projectDir, file)); !hasFile {
+			t.Fatalf("Expected %v to be created", file)
+		}
+	}
+}
+
+func TestInitialiseProjectCreatesDirectoryOthersCanRead(t *testing.T) {
+	// Arrange
+	dir := t.TempDir()
+	filesystem := repositories.NewFilesystemRepository([]string{}, dir)
+	initService := NewInitialiserService(filesystem, mockTemplates{})
+	manifest := models.DefaultManifest("testproject", "testproject")
+	projectDir := filepath.Join(dir, "testproject")
+
+	// Act
+	if err := initService.InitProject(context.Background(), manifest, projectDir); err != nil {
+		t.Fatalf("Failed to initialise project: %v", err)
+	}
+
+	// Assert
+	info, err := os.Stat(projectDir)
+	if err != nil {
+		t.Fatalf("Expected the project to be created: %v", err)
+	}
+	if mode := info.Mode().Perm(); mode != 0755 {
+		t.Fatalf("Wanted %v, got %v", fs.FileMode(0755), mode)
+	}
+}
+
+func TestInitialiseProjectLeavesNothingBehindOnFailure(t *testing.T) {
+	// Arrange
+	filesystem := repositories.NewMemoryFilesystemRepository("/project")
+	initService := NewInitialiserService(filesystem, failingTemplates{failOn: "index.ts.tmpl"})
+	manifest := models.DefaultManifest("testproject", "testproject")
+
+	// Act
+	err := initService.InitProject(context.Background(), manifest, "/project/testproject")
+
+	// Assert
+	if err == nil {
+		t.Fatal("Expected init to fail")
+	}
+	if changes := filesystem.Changes(); len(changes) != 0 {
+		t.Fatalf("Expected no files to be left behind, found %v", changes)
+	}
+}
+
+func TestInitialiseProjectLeavesNothingBehindWhenCancelled(t *testing.T) {
+	// Arrange
+	filesystem := repositories.NewMemoryFilesystemRepository("/project")
+	initService := NewInitialiserService(filesystem, mockTemplates{})
+	manifest := models.DefaultManifest("testproject", "testproject")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// Act
+	err := initService.InitProject(ctx, manifest, "/project/testproject")
+
+	// Assert
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected init to be cancelled, got %v", err)
+	}
+	if changes := filesystem.Changes(); len(changes) != 0 {
+		t.Fatalf("Expected no files to be left behind, found %v", changes)
+	}
+}
+
+func TestInitialiseProjectInPlaceReportsEveryConflict(t *testing.T) {
+	// Arrange
+	filesystem := repositories.NewMemoryFilesystemRepository("/project")
+	for _, file := range []string{"gotm.json", "go.mod", "main.go"} {
+		if _, err := filesystem.CreateFile(file); err != nil {
+			t.Fatalf("Failed to create file: %v", err)
+		}
+	}
+	initService := NewInitialiserService(filesystem, mockTemplates{})
+	manifest := models.DefaultManifest("testproject", "testproject")
+
+	// Act
+	err := initService.InitProject(context.Background(), manifest, ".")
+
+	// Assert
+	if err == nil {
+		t.Fatal("Expected init to fail")
+	}
+	for _, file := range []string{"gotm.json", "go.mod", "main.go"} {
+		if !strings.Contains(err.Error(), file) {
+			t.Fatalf("Expected %v to be reported as a conflict, got: %v", file, err)
+		}
+	}
+	if strings.Contains(err.Error(), "index.ts") {
+		t.Fatalf("Expected only existing files to be reported, got: %v", err)
+	}
+}
+
+func TestInitialiseProjectInPlaceMovesFilesIntoCurrentDirectory(t *testing.T) {
+	// Arrange
+	filesystem := repositories.NewMemoryFilesystemRepository("/project")
+	initService := NewInitialiserService(filesystem, mockTemplates{})
+	manifest := models.DefaultManifest("testproject", "testproject")
+
+	// Act
+	if err := initService.InitProject(context.Background(), manifest, "."); err != nil {
+		t.Fatalf("Failed to initialise project: %v", err)
+	}
+
+	// Assert
+	for _, change := range filesystem.Changes() {
+		if strings.Contains(change.Path, ".gotm-init-") {
+			t.Fatalf("Expected staging directory to be removed, found %v", change.Path)
+		}
+	}
+	if contents, _ := filesystem.ReadFile("frontend/src/index.ts"); contents != "mock-data" {
+		t.Fatalf("Expected index.ts to be moved into place, got %v", contents)
+	}
+}
+
+func TestInitialiseProjectInPlaceKeepsEmptyDirectories(t *testing.T) {
+	// Arrange
+	filesystem := repositories.NewMemoryFilesystemRepository("/project")
+	initService := NewInitialiserService(filesystem, mockTemplates{})
+	manifest := models.DefaultManifest("testproject", "testproject")
+	manifest.Database = models.DefaultDatabase()
+
+	// Act
+	if err := initService.InitProject(context.Background(), manifest, "."); err != nil {
+		t.Fatalf("Failed to initialise project: %v", err)
+	}
+
+	// Assert
+	for _, dir := range []string{"migrations", "queries", "frontend/src/views/pages"} {
+		if hasDir, _ := filesystem.HasDirectoryOrFile(dir); !hasDir {
+			t.Fatalf("Expected %v to be created", dir)
+		}
+	}
+	if hasFile, _ := filesystem.HasDirectoryOrFile("sqlc.yml"); !hasFile {
+		t.Fatal("Expected sqlc.yml to be moved into place")
+	}
+}
+
+func TestInitialiseProjectCreatesOptionalFiles(t *testing.T) {
+	// Arrange
+	filesystem := repositories.NewMemoryFilesystemRepository("/project")
+	initService := NewInitialiserService(filesystem, mockTemplates{})
+	manifest := models.DefaultManifest("testproject", "testproject")
+	manifest.Database = models.DefaultDatabase()
+	manifest.Dockerfile = true
+	manifest.Pages = []string{"about", "notfound"}
+
+	// Act
+	if err := initService.InitProject(context.Background(), manifest, "testproject"); err != nil {
+		t.Fatalf("Failed to initialise project: %v", err)
+	}
+
+	// Assert
+	expectedFiles := []string{
+		"sqlc.yml",
+		"Dockerfile",
+		"migrations",
+		"queries",
+		"frontend/src/views/pages/AboutPage.ts",
+		"frontend/src/views/pages/NotFoundPage.ts",
+	}
+
+	for _, file := range expectedFiles {
+		if hasFile, _ := filesystem.HasDirectoryOrFile(fmt.Sprintf("testproject/%v", file)); !hasFile {
+			t.Fatalf("Expected %v to be created", file)
 		}
 	}
+}
+
+func TestNewProjectTemplateConfigRoutesNotFoundPageLast(t *testing.T) {
+	// Arrange
+	manifest := models.DefaultManifest("testproject", "testproject")
+	manifest.Pages = []string{"notfound", "about"}
+
+	// Act
+	config := newProjectTemplateConfig(manifest)
 
-	if err := os.RemoveAll(projectDir); err != nil {
-		panic(err)
+	// Assert
+	if len(config.Pages) != 2 || config.Pages[0].Route != "/about" || config.Pages[1].Route != "/:404..." {
+		t.Fatalf("Expected the catch all route to come last, got %v", config.Pages)
+	}
+}
+
+func TestInitialiseProjectRejectsUnknownStarterPages(t *testing.T) {
+	// Arrange
+	filesystem := repositories.NewMemoryFilesystemRepository("/project")
+	initService := NewInitialiserService(filesystem, mockTemplates{})
+	manifest := models.DefaultManifest("testproject", "testproject")
+	manifest.Pages = []string{"contact"}
+
+	// Act
+	err := initService.InitProject(context.Background(), manifest, "testproject")
+
+	// Assert
+	if err == nil {
+		t.Fatal("Expected init to fail")
+	}
+	if changes := filesystem.Changes(); len(changes) != 0 {
+		t.Fatalf("Expected no files to be left behind, found %v", changes)
 	}
 }
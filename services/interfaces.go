@@ -2,13 +2,23 @@ package services
 
 import (
 	"io"
-	"os"
+	"io/fs"
+
+	"github.com/danielronalds/gotm/models"
 )
 
 type CmdRunner interface {
 	RunCmdWithPipedOutput(dir, program string, args ...string) error
 }
 
+type CmdOutputRunner interface {
+	RunCmdWithOutput(dir, program string, args ...string) (string, error)
+}
+
+type UserConfigReader interface {
+	UserConfig() (models.UserConfig, error)
+}
+
 type TemplatesWriter interface {
 	WriteTemplate(wr io.Writer, name string, data any) error
 }
@@ -23,8 +33,12 @@ type DirReader interface {
 }
 
 type ProjectRoot interface {
-	Root() string
-	FromRoot(path string) string
+	Root() (string, error)
+	FromRoot(path string) (string, error)
+}
+
+type ProjectManifest interface {
+	Manifest() (models.Manifest, error)
 }
 
 type DirCreater interface {
@@ -32,7 +46,23 @@ type DirCreater interface {
 }
 
 type FileCreater interface {
-	CreateFile(filename string) (*os.File, error)
+	CreateFile(filename string) (io.WriteCloser, error)
+}
+
+type TempDirCreater interface {
+	CreateTempDirectory(parent, pattern string) (string, error)
+}
+
+type FileModeChanger interface {
+	Chmod(filename string, mode fs.FileMode) error
+}
+
+type FileRenamer interface {
+	Rename(oldpath, newpath string) error
+}
+
+type FileWriter interface {
+	WriteFile(filename, contents string) error
 }
 
 type FileDeleter interface {
@@ -0,0 +1,98 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+)
+
+// File recording a checksum of every file gotm has generated, relative to the project root. Used
+// to tell whether a component has been edited since it was generated before removing it
+const GENERATED_LEDGER_FILE = ".gotm/generated.json"
+
+// Returns the checksums of the generated files, keyed by their path relative to the project root
+func (s ComponentService) readLedger() (map[string]string, error) {
+	ledgerFile, err := s.filesystem.FromRoot(GENERATED_LEDGER_FILE)
+	if err != nil {
+		return nil, err
+	}
+
+	contents, err := s.filesystem.ReadFile(ledgerFile)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read %v: %v", GENERATED_LEDGER_FILE, err.Error())
+	}
+
+	ledger := make(map[string]string)
+	if contents == "" {
+		return ledger, nil
+	}
+
+	if err := json.Unmarshal([]byte(contents), &ledger); err != nil {
+		return nil, fmt.Errorf("unable to parse %v: %v", GENERATED_LEDGER_FILE, err.Error())
+	}
+
+	return ledger, nil
+}
+
+func (s ComponentService) writeLedger(ledger map[string]string) error {
+	ledgerFile, err := s.filesystem.FromRoot(GENERATED_LEDGER_FILE)
+	if err != nil {
+		return err
+	}
+
+	ledgerDir := filepath.Dir(ledgerFile)
+	hasDir, err := s.filesystem.HasDirectoryOrFile(ledgerDir)
+	if err != nil {
+		return fmt.Errorf("unable to check if %v directory exists: %v", ledgerDir, err.Error())
+	}
+	if !hasDir {
+		if err := s.filesystem.CreateDirectory(ledgerDir); err != nil {
+			return fmt.Errorf("unable to create %v directory: %v", ledgerDir, err.Error())
+		}
+	}
+
+	contents, err := json.MarshalIndent(ledger, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return s.filesystem.WriteFile(ledgerFile, string(contents)+"\n")
+}
+
+// Records the checksum of a file that has just been generated
+func (s ComponentService) recordGenerated(filename string, contents string) error {
+	ledger, err := s.readLedger()
+	if err != nil {
+		return err
+	}
+
+	relative, err := s.relativeToRoot(filename)
+	if err != nil {
+		return err
+	}
+	ledger[relative] = checksum(contents)
+
+	return s.writeLedger(ledger)
+}
+
+// Returns the path relative to the project root, in the form it's stored in the ledger
+func (s ComponentService) relativeToRoot(filename string) (string, error) {
+	root, err := s.filesystem.Root()
+	if err != nil {
+		return "", err
+	}
+
+	relative, err := filepath.Rel(root, filename)
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.ToSlash(relative), nil
+}
+
+func checksum(contents string) string {
+	sum := sha256.Sum256([]byte(contents))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,341 @@
+package services
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/danielronalds/gotm/models"
+)
+
+type MigrationServiceFilesystem interface {
+	ProjectRoot
+	ProjectManifest
+	DirReader
+	FileReader
+}
+
+type MigrationDatabase interface {
+	Open(engine, location string) error
+	Close() error
+	EnsureVersionTable() error
+	AppliedMigrations() ([]models.AppliedMigration, error)
+	ApplyMigration(version int64, statements []string, up, useTransaction bool) error
+}
+
+// Service for applying and rolling back goose migrations, tracking them in goose's version table
+// so gotm and goose can be used interchangeably
+type MigrationService struct {
+	filesystem MigrationServiceFilesystem
+	database   MigrationDatabase
+}
+
+func NewMigrationService(filesystem MigrationServiceFilesystem, database MigrationDatabase) MigrationService {
+	return MigrationService{filesystem, database}
+}
+
+// Applies every pending migration in order, returning the migrations applied
+func (s MigrationService) Up() ([]models.Migration, error) {
+	migrations, err := s.open()
+	if err != nil {
+		return nil, err
+	}
+	defer s.database.Close()
+
+	latest := latestApplied(migrations)
+	pending := slices.DeleteFunc(slices.Clone(migrations), func(m models.Migration) bool { return m.Applied })
+
+	// Matching goose, which refuses to apply migrations older than the latest applied one
+	for _, migration := range pending {
+		if latest != nil && migration.Version < latest.Version {
+			return nil, fmt.Errorf("%v is older than the latest applied migration %v, rename it so it runs last", migration.Filename, latest.Filename)
+		}
+	}
+
+	applied := make([]models.Migration, 0, len(pending))
+	for _, migration := range pending {
+		if err := s.apply(migration, true); err != nil {
+			return applied, err
+		}
+		applied = append(applied, migration)
+	}
+
+	return applied, nil
+}
+
+// Rolls back the latest applied migration, returning it
+func (s MigrationService) Down() (models.Migration, error) {
+	migrations, err := s.open()
+	if err != nil {
+		return models.Migration{}, err
+	}
+	defer s.database.Close()
+
+	latest := latestApplied(migrations)
+	if latest == nil {
+		return models.Migration{}, errors.New("no migrations have been applied")
+	}
+
+	return *latest, s.apply(*latest, false)
+}
+
+// Rolls back and reapplies the latest applied migration, returning it
+func (s MigrationService) Redo() (models.Migration, error) {
+	migrations, err := s.open()
+	if err != nil {
+		return models.Migration{}, err
+	}
+	defer s.database.Close()
+
+	latest := latestApplied(migrations)
+	if latest == nil {
+		return models.Migration{}, errors.New("no migrations have been applied")
+	}
+
+	if err := s.apply(*latest, false); err != nil {
+		return *latest, err
+	}
+
+	return *latest, s.apply(*latest, true)
+}
+
+// Returns every migration in the project along with whether it has been applied
+func (s MigrationService) Status() ([]models.Migration, error) {
+	migrations, err := s.open()
+	if err != nil {
+		return nil, err
+	}
+	defer s.database.Close()
+
+	return migrations, nil
+}
+
+// Opens the project's database, returning the project's migrations in version order marked with
+// whether they've been applied
+func (s MigrationService) open() ([]models.Migration, error) {
+	manifest, err := s.filesystem.Manifest()
+	if err != nil {
+		return nil, fmt.Errorf("unable to read project manifest: %v", err)
+	}
+	if manifest.Database == nil {
+		manifest.Database = models.DefaultDatabase()
+	}
+
+	migrations, err := s.migrations(manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	location := manifest.Database.Location
+	if manifest.Database.Engine == "sqlite" && !filepath.IsAbs(location) {
+		if location, err = s.filesystem.FromRoot(location); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.database.Open(manifest.Database.Engine, location); err != nil {
+		return nil, err
+	}
+
+	if err := s.database.EnsureVersionTable(); err != nil {
+		s.database.Close()
+		return nil, err
+	}
+
+	applied, err := s.database.AppliedMigrations()
+	if err != nil {
+		s.database.Close()
+		return nil, err
+	}
+
+	for _, record := range applied {
+		i := slices.IndexFunc(migrations, func(m models.Migration) bool { return m.Version == record.Version })
+		if i == -1 {
+			s.database.Close()
+			return nil, fmt.Errorf("migration %v has been applied but its file is missing", record.Version)
+		}
+		migrations[i].Applied = true
+		migrations[i].AppliedAt = record.AppliedAt
+	}
+
+	return migrations, nil
+}
+
+// Returns the migrations in the project's migrations directory, sorted by version
+func (s MigrationService) migrations(manifest models.Manifest) ([]models.Migration, error) {
+	migrationsDir, err := s.filesystem.FromRoot(manifest.Directories.Migrations)
+	if err != nil {
+		return nil, err
+	}
+
+	hasDir, err := s.filesystem.HasDirectoryOrFile(migrationsDir)
+	if err != nil {
+		return nil, fmt.Errorf("unable to check if %v exists: %v", migrationsDir, err)
+	}
+	if !hasDir {
+		return []models.Migration{}, nil
+	}
+
+	files, err := s.filesystem.ReadDirRecursive(migrationsDir)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read migrations: %v", err)
+	}
+
+	migrations := make([]models.Migration, 0, len(files))
+	for _, file := range files {
+		if filepath.Ext(file) != ".sql" || filepath.Dir(file) != filepath.Clean(migrationsDir) {
+			continue
+		}
+
+		prefix, _, _ := strings.Cut(filepath.Base(file), "_")
+		version, err := strconv.ParseInt(prefix, 10, 64)
+		if err != nil || version < 1 {
+			return nil, fmt.Errorf("%v doesn't start with a version, i.e. 20250101120000_create_books.sql", filepath.Base(file))
+		}
+
+		if i := slices.IndexFunc(migrations, func(m models.Migration) bool { return m.Version == version }); i != -1 {
+			return nil, fmt.Errorf("%v and %v share the same version", migrations[i].Filename, filepath.Base(file))
+		}
+
+		migrations = append(migrations, models.Migration{Version: version, Filename: filepath.Base(file)})
+	}
+
+	slices.SortFunc(migrations, func(a, b models.Migration) int {
+		return cmp.Compare(a.Version, b.Version)
+	})
+
+	return migrations, nil
+}
+
+// Runs the up or down section of the migration
+func (s MigrationService) apply(migration models.Migration, up bool) error {
+	manifest, err := s.filesystem.Manifest()
+	if err != nil {
+		return fmt.Errorf("unable to read project manifest: %v", err)
+	}
+
+	filename, err := s.filesystem.FromRoot(filepath.Join(manifest.Directories.Migrations, migration.Filename))
+	if err != nil {
+		return err
+	}
+
+	contents, err := s.filesystem.ReadFile(filename)
+	if err != nil {
+		return fmt.Errorf("unable to read %v: %v", migration.Filename, err)
+	}
+
+	parsed, err := parseMigration(contents)
+	if err != nil {
+		return fmt.Errorf("unable to parse %v: %v", migration.Filename, err)
+	}
+
+	statements := parsed.up
+	if !up {
+		statements = parsed.down
+	}
+
+	if err := s.database.ApplyMigration(migration.Version, statements, up, !parsed.noTransaction); err != nil {
+		direction := "apply"
+		if !up {
+			direction = "roll back"
+		}
+		return fmt.Errorf("failed to %v %v: %v", direction, migration.Filename, err)
+	}
+
+	return nil
+}
+
+// Returns the applied migration with the highest version, nil if none have been applied
+func latestApplied(migrations []models.Migration) *models.Migration {
+	for i := len(migrations) - 1; i >= 0; i-- {
+		if migrations[i].Applied {
+			return &migrations[i]
+		}
+	}
+
+	return nil
+}
+
+// The statements of a goose migration
+type parsedMigration struct {
+	up   []string
+	down []string
+	// Set by `-- +goose NO TRANSACTION`, for statements that can't be run in a transaction
+	noTransaction bool
+}
+
+// Splits a goose migration into its up and down statements. Statements end with a semicolon at the
+// end of a line, unless they're wrapped in StatementBegin and StatementEnd annotations
+func parseMigration(contents string) (parsedMigration, error) {
+	var parsed parsedMigration
+	var current *[]string
+	var statement strings.Builder
+	inBlock, hasUp := false, false
+
+	flush := func() {
+		if strings.TrimSpace(statement.String()) != "" {
+			*current = append(*current, strings.TrimSpace(statement.String()))
+		}
+		statement.Reset()
+	}
+
+	for i, line := range strings.Split(contents, "\n") {
+		trimmed := strings.TrimSpace(line)
+
+		if annotation, ok := strings.CutPrefix(trimmed, "-- +goose "); ok {
+			switch strings.ToLower(strings.TrimSpace(annotation)) {
+			case "up":
+				current, hasUp = &parsed.up, true
+			case "down":
+				if current == nil {
+					return parsed, errors.New("found +goose Down before +goose Up")
+				}
+				flush()
+				current = &parsed.down
+			case "statementbegin":
+				if current == nil {
+					return parsed, fmt.Errorf("line %v: statement outside of an up or down section", i+1)
+				}
+				inBlock = true
+			case "statementend":
+				if !inBlock {
+					return parsed, fmt.Errorf("line %v: +goose StatementEnd without StatementBegin", i+1)
+				}
+				flush()
+				inBlock = false
+			case "no transaction":
+				parsed.noTransaction = true
+			default:
+				return parsed, fmt.Errorf("line %v: unknown annotation \"%v\"", i+1, annotation)
+			}
+			continue
+		}
+
+		if current == nil || (!inBlock && (trimmed == "" || strings.HasPrefix(trimmed, "--"))) {
+			continue
+		}
+
+		statement.WriteString(line)
+		statement.WriteString("\n")
+
+		if !inBlock && strings.HasSuffix(trimmed, ";") {
+			flush()
+		}
+	}
+
+	if !hasUp {
+		return parsed, errors.New("missing +goose Up annotation")
+	}
+	if inBlock {
+		return parsed, errors.New("missing +goose StatementEnd annotation")
+	}
+	if strings.TrimSpace(statement.String()) != "" {
+		return parsed, errors.New("last statement is missing a semicolon")
+	}
+
+	return parsed, nil
+}
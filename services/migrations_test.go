@@ -0,0 +1,163 @@
+package services
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/danielronalds/gotm/repositories"
+)
+
+const testMigration = `-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE book (
+    id INTEGER PRIMARY KEY,
+    title TEXT NOT NULL
+);
+-- +goose StatementEnd
+CREATE INDEX book_title ON book (title);
+
+-- +goose Down
+DROP INDEX book_title;
+DROP TABLE book;
+`
+
+// Creates a project in a memory filesystem with the given migrations, using a sqlite database in a
+// temporary directory
+func newMigrationTestProject(migrations map[string]string, t *testing.T) MigrationService {
+	filesystem := repositories.NewMemoryFilesystemRepository("/project")
+	location := filepath.Join(t.TempDir(), "test.db")
+	writeMemoryFile(filesystem, "/project/gotm.json", `{"name": "project", "database": {"engine": "sqlite", "location": "`+location+`"}}`, t)
+	if err := filesystem.CreateDirectory("/project/migrations"); err != nil {
+		t.Fatalf("Failed to create migrations directory: %v", err)
+	}
+	for filename, contents := range migrations {
+		writeMemoryFile(filesystem, filepath.Join("/project/migrations", filename), contents, t)
+	}
+
+	return NewMigrationService(filesystem, repositories.NewDatabaseRepository())
+}
+
+func TestParseMigrationKeepsStatementBlocksTogether(t *testing.T) {
+	// Act
+	parsed, err := parseMigration(testMigration)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Failed to parse migration: %v", err)
+	}
+	if len(parsed.up) != 2 {
+		t.Fatalf("Wanted 2 up statements, got %v: %v", len(parsed.up), parsed.up)
+	}
+	if len(parsed.down) != 2 {
+		t.Fatalf("Wanted 2 down statements, got %v: %v", len(parsed.down), parsed.down)
+	}
+}
+
+func TestParseMigrationRejectsUnterminatedBlock(t *testing.T) {
+	// Arrange
+	contents := "-- +goose Up\n-- +goose StatementBegin\nSELECT 1;\n"
+
+	// Act
+	_, err := parseMigration(contents)
+
+	// Assert
+	if err == nil {
+		t.Fatal("Expected an unterminated block to fail")
+	}
+}
+
+func TestMigrationsUpAppliesPendingMigrationsInOrder(t *testing.T) {
+	// Arrange
+	migrationService := newMigrationTestProject(map[string]string{
+		"20250102000000_add_author.sql":  "-- +goose Up\nALTER TABLE book ADD COLUMN author TEXT;\n\n-- +goose Down\nALTER TABLE book DROP COLUMN author;\n",
+		"20250101000000_create_book.sql": testMigration,
+	}, t)
+
+	// Act
+	applied, err := migrationService.Up()
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Failed to apply migrations: %v", err)
+	}
+	if len(applied) != 2 || applied[0].Version != 20250101000000 {
+		t.Fatalf("Expected both migrations to be applied in order, got %v", applied)
+	}
+
+	status, err := migrationService.Status()
+	if err != nil {
+		t.Fatalf("Failed to get status: %v", err)
+	}
+	for _, migration := range status {
+		if !migration.Applied {
+			t.Fatalf("Expected %v to be applied", migration.Filename)
+		}
+	}
+}
+
+func TestMigrationsDownRollsBackLatestMigration(t *testing.T) {
+	// Arrange
+	migrationService := newMigrationTestProject(map[string]string{
+		"20250101000000_create_book.sql": testMigration,
+	}, t)
+	if _, err := migrationService.Up(); err != nil {
+		t.Fatalf("Failed to apply migrations: %v", err)
+	}
+
+	// Act
+	rolledBack, err := migrationService.Down()
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Failed to roll back migration: %v", err)
+	}
+	if rolledBack.Version != 20250101000000 {
+		t.Fatalf("Wanted 20250101000000, got %v", rolledBack.Version)
+	}
+	if applied, _ := migrationService.Up(); len(applied) != 1 {
+		t.Fatalf("Expected the migration to be pending again, got %v", applied)
+	}
+}
+
+func TestMigrationsUpLeavesDatabaseUnchangedOnFailure(t *testing.T) {
+	// Arrange
+	migrationService := newMigrationTestProject(map[string]string{
+		"20250101000000_broken.sql": "-- +goose Up\nCREATE TABLE book (id INTEGER PRIMARY KEY);\nNOT VALID SQL;\n\n-- +goose Down\nDROP TABLE book;\n",
+	}, t)
+
+	// Act
+	_, err := migrationService.Up()
+
+	// Assert
+	if err == nil {
+		t.Fatal("Expected the broken migration to fail")
+	}
+	status, err := migrationService.Status()
+	if err != nil {
+		t.Fatalf("Failed to get status: %v", err)
+	}
+	if status[0].Applied {
+		t.Fatal("Expected the broken migration to not be recorded as applied")
+	}
+}
+
+func TestMigrationsRedoReappliesLatestMigration(t *testing.T) {
+	// Arrange
+	migrationService := newMigrationTestProject(map[string]string{
+		"20250101000000_create_book.sql": testMigration,
+	}, t)
+	if _, err := migrationService.Up(); err != nil {
+		t.Fatalf("Failed to apply migrations: %v", err)
+	}
+
+	// Act
+	redone, err := migrationService.Redo()
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Failed to redo migration: %v", err)
+	}
+	if redone.Version != 20250101000000 {
+		t.Fatalf("Wanted 20250101000000, got %v", redone.Version)
+	}
+}
@@ -0,0 +1,530 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/danielronalds/gotm/models"
+)
+
+// File the fakes of a package's interfaces are generated in, only compiled into the package's tests
+const GENERATED_MOCKS_FILE = "mocks_test.go"
+
+const generatedMocksHeader = "// Code generated by `gotm gen mocks` from the package's interfaces. DO NOT EDIT."
+
+type MocksServiceFilesystem interface {
+	ProjectRoot
+	ProjectManifest
+	DirReader
+	FileReader
+	FileWriter
+	FileDeleter
+}
+
+// Service for generating configurable fakes of the interfaces packages declare for what they
+// consume, so tests don't have to write them by hand
+type MocksService struct {
+	filesystem MocksServiceFilesystem
+}
+
+func NewMocksService(filesystem MocksServiceFilesystem) MocksService {
+	return MocksService{filesystem}
+}
+
+// A package of the project, with its Go files parsed
+type goPackage struct {
+	// Directory of the package, relative to the project root and slash separated
+	dir        string
+	name       string
+	modulePath string
+	fileset    *token.FileSet
+	files      []*ast.File
+	// Files of the package's tests, other than the generated fakes
+	testFiles []*ast.File
+}
+
+// Generates fakes of the interfaces in the package in the given directory, or in every package of
+// the project that declares interfaces if no directory is given
+func (s MocksService) GenerateMocks(dir string) ([]models.GeneratedMocks, error) {
+	packages, err := s.parsePackages()
+	if err != nil {
+		return nil, err
+	}
+
+	if dir != "" {
+		dir = path.Clean(filepath.ToSlash(dir))
+		index := slices.IndexFunc(packages, func(pkg goPackage) bool { return pkg.dir == dir })
+		if index == -1 {
+			return nil, fmt.Errorf("no Go package was found in %v", dir)
+		}
+		if len(packageInterfaces(packages[index])) == 0 {
+			return nil, fmt.Errorf("%v doesn't declare any interfaces", dir)
+		}
+		packages = packages[index : index+1]
+	}
+
+	generated := make([]models.GeneratedMocks, 0)
+	for _, pkg := range packages {
+		if dir == "" && len(packageInterfaces(pkg)) == 0 {
+			deleted, err := s.deleteMocks(pkg)
+			if err != nil {
+				return generated, err
+			}
+			if deleted {
+				generated = append(generated, models.GeneratedMocks{Filename: path.Join(pkg.dir, GENERATED_MOCKS_FILE), Deleted: true})
+			}
+			continue
+		}
+
+		mocks, err := s.writeMocks(pkg)
+		if err != nil {
+			return generated, err
+		}
+		generated = append(generated, mocks)
+	}
+
+	return generated, nil
+}
+
+// Regenerates the fakes of the packages they've been generated for, deleting them from packages
+// that no longer declare interfaces
+func (s MocksService) RegenerateMocks() ([]models.GeneratedMocks, error) {
+	packages, err := s.parsePackages()
+	if err != nil {
+		return nil, err
+	}
+
+	generated := make([]models.GeneratedMocks, 0)
+	for _, pkg := range packages {
+		filename, err := s.mocksFilename(pkg)
+		if err != nil {
+			return generated, err
+		}
+		existing, err := s.filesystem.ReadFile(filename)
+		if err != nil || !strings.HasPrefix(existing, generatedMocksHeader) {
+			continue
+		}
+
+		if len(packageInterfaces(pkg)) == 0 {
+			if _, err := s.deleteMocks(pkg); err != nil {
+				return generated, err
+			}
+			generated = append(generated, models.GeneratedMocks{Filename: path.Join(pkg.dir, GENERATED_MOCKS_FILE), Deleted: true})
+			continue
+		}
+
+		mocks, err := s.writeMocks(pkg)
+		if err != nil {
+			return generated, err
+		}
+		generated = append(generated, mocks)
+	}
+
+	return generated, nil
+}
+
+// Deletes the package's mocks file if gotm generated it, returning whether it was deleted
+func (s MocksService) deleteMocks(pkg goPackage) (bool, error) {
+	filename, err := s.mocksFilename(pkg)
+	if err != nil {
+		return false, err
+	}
+	existing, err := s.filesystem.ReadFile(filename)
+	if err != nil || !strings.HasPrefix(existing, generatedMocksHeader) {
+		return false, nil
+	}
+
+	if err := s.filesystem.DeleteFileRecursive(filename); err != nil {
+		return false, fmt.Errorf("unable to delete %v: %v", path.Join(pkg.dir, GENERATED_MOCKS_FILE), err.Error())
+	}
+
+	return true, nil
+}
+
+func (s MocksService) mocksFilename(pkg goPackage) (string, error) {
+	return s.filesystem.FromRoot(filepath.Join(filepath.FromSlash(pkg.dir), GENERATED_MOCKS_FILE))
+}
+
+// Parses the Go files of every package in the project, along with their tests
+func (s MocksService) parsePackages() ([]goPackage, error) {
+	manifest, err := s.filesystem.Manifest()
+	if err != nil {
+		return nil, fmt.Errorf("unable to read project manifest: %v", err.Error())
+	}
+	root, err := s.filesystem.Root()
+	if err != nil {
+		return nil, err
+	}
+	modulePath, err := projectModulePath(s.filesystem, manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := projectGoFiles(s.filesystem, manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	packages := make([]goPackage, 0)
+	fileset := token.NewFileSet()
+	for _, relative := range files {
+		file, err := s.parseFile(fileset, root, relative)
+		if err != nil {
+			return nil, err
+		}
+
+		dir := path.Dir(relative)
+		if len(packages) == 0 || packages[len(packages)-1].dir != dir {
+			packages = append(packages, goPackage{dir: dir, name: file.Name.Name, modulePath: modulePath, fileset: fileset})
+		}
+		packages[len(packages)-1].files = append(packages[len(packages)-1].files, file)
+	}
+
+	// Tests are parsed so fakes aren't generated under names the tests already use
+	for i, pkg := range packages {
+		dirname, err := s.filesystem.FromRoot(filepath.FromSlash(pkg.dir))
+		if err != nil {
+			return nil, err
+		}
+		testFiles, err := s.filesystem.ReadDirRecursive(dirname)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read %v: %v", pkg.dir, err.Error())
+		}
+
+		for _, testFile := range testFiles {
+			if filepath.Dir(testFile) != filepath.Clean(dirname) || !strings.HasSuffix(testFile, "_test.go") || filepath.Base(testFile) == GENERATED_MOCKS_FILE {
+				continue
+			}
+			file, err := s.parseFile(fileset, root, path.Join(pkg.dir, filepath.Base(testFile)))
+			if err != nil {
+				return nil, err
+			}
+			if file.Name.Name == pkg.name {
+				packages[i].testFiles = append(packages[i].testFiles, file)
+			}
+		}
+	}
+
+	return packages, nil
+}
+
+func (s MocksService) parseFile(fileset *token.FileSet, root, relative string) (*ast.File, error) {
+	contents, err := s.filesystem.ReadFile(filepath.Join(root, filepath.FromSlash(relative)))
+	if err != nil {
+		return nil, fmt.Errorf("unable to read %v: %v", relative, err.Error())
+	}
+
+	file, err := parser.ParseFile(fileset, relative, contents, parser.SkipObjectResolution)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse %v: %v", relative, err)
+	}
+
+	return file, nil
+}
+
+// An interface declared in a package, along with the file declaring it
+type goInterface struct {
+	name          string
+	interfaceType *ast.InterfaceType
+	file          *ast.File
+}
+
+// Returns the interfaces the package declares, in the order they're declared
+func packageInterfaces(pkg goPackage) []goInterface {
+	interfaces := make([]goInterface, 0)
+	for _, file := range pkg.files {
+		for _, decl := range file.Decls {
+			decl, ok := decl.(*ast.GenDecl)
+			if !ok || decl.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range decl.Specs {
+				typeSpec := spec.(*ast.TypeSpec)
+				if interfaceType, ok := typeSpec.Type.(*ast.InterfaceType); ok && typeSpec.TypeParams == nil {
+					interfaces = append(interfaces, goInterface{typeSpec.Name.Name, interfaceType, file})
+				}
+			}
+		}
+	}
+	return interfaces
+}
+
+// Renders the fakes of the package's interfaces into its mocks file, refusing to overwrite a file
+// gotm didn't generate
+func (s MocksService) writeMocks(pkg goPackage) (models.GeneratedMocks, error) {
+	generated := models.GeneratedMocks{
+		Filename:   path.Join(pkg.dir, GENERATED_MOCKS_FILE),
+		Interfaces: make([]string, 0),
+		Skipped:    make([]string, 0),
+	}
+
+	filename, err := s.mocksFilename(pkg)
+	if err != nil {
+		return generated, err
+	}
+	existing, err := s.filesystem.ReadFile(filename)
+	if err != nil {
+		return generated, fmt.Errorf("unable to read %v: %v", generated.Filename, err.Error())
+	}
+	if existing != "" && !strings.HasPrefix(existing, generatedMocksHeader) {
+		return generated, fmt.Errorf("%v wasn't generated by gotm, rename it so the fakes can be generated", generated.Filename)
+	}
+
+	contents, err := renderMocks(pkg, &generated)
+	if err != nil {
+		return generated, err
+	}
+	if len(generated.Interfaces) == 0 {
+		return generated, fmt.Errorf("none of the interfaces in %v could be faked: %v", pkg.dir, strings.Join(generated.Skipped, ", "))
+	}
+	if contents == existing {
+		return generated, nil
+	}
+
+	if err := s.filesystem.WriteFile(filename, contents); err != nil {
+		return generated, fmt.Errorf("unable to write %v: %v", generated.Filename, err.Error())
+	}
+	generated.Changed = true
+
+	return generated, nil
+}
+
+// Renders the mocks file of the package, recording which interfaces were faked and which were
+// skipped in generated
+func renderMocks(pkg goPackage, generated *models.GeneratedMocks) (string, error) {
+	interfaces := packageInterfaces(pkg)
+	declared := declaredNames(pkg.testFiles)
+	writer := testWriter{modulePath: pkg.modulePath, fileset: pkg.fileset, usedImports: make(map[string]bool)}
+
+	var mocks strings.Builder
+	files := make([]*ast.File, 0)
+	for _, iface := range interfaces {
+		methods, err := interfaceMethods(iface, interfaces, nil)
+		if err == nil && slices.Contains(declared, mockName(iface.name)) {
+			err = fmt.Errorf("%v is already declared in the package's tests", mockName(iface.name))
+		}
+		if err != nil {
+			generated.Skipped = append(generated.Skipped, fmt.Sprintf("%v (%v)", iface.name, err))
+			continue
+		}
+
+		mocks.WriteString("\n" + writer.mock(iface.name, methods))
+		generated.Interfaces = append(generated.Interfaces, iface.name)
+		files = append(files, iface.file)
+		for _, method := range methods {
+			files = append(files, method.file)
+		}
+	}
+
+	var source strings.Builder
+	fmt.Fprintf(&source, "%v\n\npackage %v\n\nimport (\n", generatedMocksHeader, pkg.name)
+	for _, spec := range writer.imports([]string{"sync"}, files...) {
+		fmt.Fprintf(&source, "\t%v\n", spec)
+	}
+	source.WriteString(")\n")
+	source.WriteString(mocks.String())
+
+	formatted, err := format.Source([]byte(source.String()))
+	if err != nil {
+		return "", fmt.Errorf("unable to format the fakes of %v: %v", pkg.dir, err)
+	}
+
+	return string(formatted), nil
+}
+
+// Returns the names declared at the top level of the files
+func declaredNames(files []*ast.File) []string {
+	names := make([]string, 0)
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			switch decl := decl.(type) {
+			case *ast.FuncDecl:
+				if decl.Recv == nil {
+					names = append(names, decl.Name.Name)
+				}
+			case *ast.GenDecl:
+				for _, spec := range decl.Specs {
+					switch spec := spec.(type) {
+					case *ast.TypeSpec:
+						names = append(names, spec.Name.Name)
+					case *ast.ValueSpec:
+						for _, name := range spec.Names {
+							names = append(names, name.Name)
+						}
+					}
+				}
+			}
+		}
+	}
+	return names
+}
+
+func mockName(interfaceName string) string {
+	return "mock" + capitalise(interfaceName)
+}
+
+// A method of an interface, along with the file it's declared in
+type interfaceMethod struct {
+	name     string
+	function *ast.FuncType
+	file     *ast.File
+}
+
+// Returns the methods of the interface, including those of the interfaces it embeds. Only
+// interfaces declared in the same package, and error, can be embedded as the methods of others
+// aren't known without type checking
+func interfaceMethods(iface goInterface, interfaces []goInterface, embeddedBy []string) ([]interfaceMethod, error) {
+	if slices.Contains(embeddedBy, iface.name) {
+		return nil, fmt.Errorf("%v embeds itself", iface.name)
+	}
+
+	methods := make([]interfaceMethod, 0)
+	for _, field := range iface.interfaceType.Methods.List {
+		if function, ok := field.Type.(*ast.FuncType); ok && len(field.Names) != 0 {
+			methods = append(methods, interfaceMethod{field.Names[0].Name, function, iface.file})
+			continue
+		}
+
+		embedded, ok := field.Type.(*ast.Ident)
+		if !ok {
+			return nil, errors.New("embeds an interface from another package or a type constraint")
+		}
+
+		if embedded.Name == "error" {
+			errorMethod := &ast.FuncType{Params: &ast.FieldList{}, Results: &ast.FieldList{List: []*ast.Field{{Type: ast.NewIdent("string")}}}}
+			methods = append(methods, interfaceMethod{"Error", errorMethod, iface.file})
+			continue
+		}
+
+		index := slices.IndexFunc(interfaces, func(i goInterface) bool { return i.name == embedded.Name })
+		if index == -1 {
+			return nil, fmt.Errorf("embeds %v, which isn't an interface", embedded.Name)
+		}
+		embeddedMethods, err := interfaceMethods(interfaces[index], interfaces, append(embeddedBy, iface.name))
+		if err != nil {
+			return nil, err
+		}
+		for _, method := range embeddedMethods {
+			if !slices.ContainsFunc(methods, func(m interfaceMethod) bool { return m.name == method.name }) {
+				methods = append(methods, method)
+			}
+		}
+	}
+
+	return methods, nil
+}
+
+// A parameter of a faked method
+type mockParam struct {
+	name string
+	expr ast.Expr
+	// Type of the parameter, i.e. ...string
+	source string
+	// Type the parameter is recorded as, i.e. []string
+	recorded string
+	variadic bool
+}
+
+// Renders the fake of the interface. Each method calls its function field if it's set, returning
+// zero values otherwise, and records its arguments in its calls field
+func (w testWriter) mock(interfaceName string, methods []interfaceMethod) string {
+	name := mockName(interfaceName)
+
+	var fields, callTypes, declarations strings.Builder
+	for _, method := range methods {
+		params := w.mockParams(method.function, "mock")
+		results := make([]string, 0)
+		for _, result := range fieldTypes(method.function.Results) {
+			results = append(results, w.source(result))
+		}
+
+		signature := make([]string, 0, len(params))
+		arguments := make([]string, 0, len(params))
+		recorded := make([]string, 0, len(params))
+		callFields := make([]string, 0, len(params))
+		for _, param := range params {
+			signature = append(signature, fmt.Sprintf("%v %v", param.name, param.source))
+			recorded = append(recorded, param.name)
+			callFields = append(callFields, fmt.Sprintf("\t%v %v\n", capitalise(param.name), param.recorded))
+			if param.variadic {
+				arguments = append(arguments, param.name+"...")
+			} else {
+				arguments = append(arguments, param.name)
+			}
+		}
+
+		funcResults := strings.Join(results, ", ")
+		namedResults := ""
+		if len(results) > 1 {
+			funcResults = "(" + funcResults + ")"
+		}
+		if len(results) != 0 {
+			namedResults = "(_ " + strings.Join(results, ", _ ") + ")"
+		}
+
+		callType := fmt.Sprintf("%v%vCall", name, method.name)
+		funcType := fmt.Sprintf("func(%v) %v", strings.Join(signature, ", "), funcResults)
+
+		fmt.Fprintf(&fields, "\t%vFunc %v\n\t%vCalls []%v\n", method.name, funcType, method.name, callType)
+		fmt.Fprintf(&callTypes, "\n// Arguments of a call to %v.%v\ntype %v struct {\n%v}\n", name, method.name, callType, strings.Join(callFields, ""))
+
+		fmt.Fprintf(&declarations, "\nfunc (mock *%v) %v(%v) %v {\n", name, method.name, strings.Join(signature, ", "), namedResults)
+		fmt.Fprintf(&declarations, "\tmock.mu.Lock()\n\tmock.%vCalls = append(mock.%vCalls, %v{%v})\n\tmock.mu.Unlock()\n\n", method.name, method.name, callType, strings.Join(recorded, ", "))
+		if len(results) == 0 {
+			fmt.Fprintf(&declarations, "\tif mock.%vFunc != nil {\n\t\tmock.%vFunc(%v)\n\t}\n}\n", method.name, method.name, strings.Join(arguments, ", "))
+		} else {
+			fmt.Fprintf(&declarations, "\tif mock.%vFunc != nil {\n\t\treturn mock.%vFunc(%v)\n\t}\n\treturn\n}\n", method.name, method.name, strings.Join(arguments, ", "))
+		}
+	}
+
+	var mock strings.Builder
+	fmt.Fprintf(&mock, "// Configurable fake of %v. Each method calls its function field if it's set, returning zero\n", interfaceName)
+	mock.WriteString("// values otherwise, and records the arguments it was called with in its calls field\n")
+	fmt.Fprintf(&mock, "type %v struct {\n\tmu sync.Mutex\n\n%v}\n", name, fields.String())
+	mock.WriteString(callTypes.String())
+	mock.WriteString(declarations.String())
+
+	return mock.String()
+}
+
+// Returns the parameters of the method, naming the ones that are unnamed or blank, or share the
+// receiver's name, so they can be recorded
+func (w testWriter) mockParams(function *ast.FuncType, receiver string) []mockParam {
+	params := make([]mockParam, 0)
+	if function.Params == nil {
+		return params
+	}
+
+	for _, field := range function.Params.List {
+		names := make([]string, 0)
+		for _, name := range field.Names {
+			names = append(names, name.Name)
+		}
+		if len(names) == 0 {
+			names = append(names, "_")
+		}
+
+		for _, name := range names {
+			param := mockParam{name: name, expr: field.Type, source: w.source(field.Type)}
+			param.recorded = param.source
+			if ellipsis, ok := field.Type.(*ast.Ellipsis); ok {
+				param.variadic = true
+				param.recorded = "[]" + w.source(ellipsis.Elt)
+			}
+			if param.name == "_" || param.name == receiver {
+				param.name = fmt.Sprintf("arg%v", len(params))
+			}
+			params = append(params, param)
+		}
+	}
+
+	return params
+}
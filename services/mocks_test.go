@@ -0,0 +1,134 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/danielronalds/gotm/repositories"
+)
+
+const testMocksServiceFile = `package services
+
+import "example.com/project/models"
+
+type Getter interface {
+	Get(id int) (models.Book, error)
+}
+
+type BookRepository interface {
+	Getter
+	Search(query string, tags ...string) []models.Book
+	Delete(_ int) error
+}
+
+type Cache interface {
+	Clear()
+}
+`
+
+const testMocksExistingTestFile = `package services
+
+type mockCache struct{}
+`
+
+func newMocksTestFilesystem(t *testing.T) repositories.MemoryFilesystemRepository {
+	filesystem := repositories.NewMemoryFilesystemRepository("/project")
+	writeMemoryFile(filesystem, "/project/gotm.json", `{"name": "project", "module": "example.com/project"}`, t)
+	if err := filesystem.CreateDirectory("/project/services"); err != nil {
+		t.Fatal(err)
+	}
+	writeMemoryFile(filesystem, "/project/services/books.go", testMocksServiceFile, t)
+	writeMemoryFile(filesystem, "/project/services/cache_test.go", testMocksExistingTestFile, t)
+	return filesystem
+}
+
+func TestGenerateMocksFakesInterfaces(t *testing.T) {
+	// Arrange
+	filesystem := newMocksTestFilesystem(t)
+	mocksService := NewMocksService(filesystem)
+	expected := []string{
+		"type mockBookRepository struct",
+		"GetFunc     func(id int) (models.Book, error)",
+		"SearchCalls []mockBookRepositorySearchCall",
+		"Tags  []string",
+		"return mock.SearchFunc(query, tags...)",
+		"func (mock *mockBookRepository) Delete(arg0 int) (_ error) {",
+		`"example.com/project/models"`,
+	}
+
+	// Act
+	generated, err := mocksService.GenerateMocks("services")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Failed to generate mocks: %v", err)
+	}
+	if len(generated) != 1 || !generated[0].Changed || generated[0].Filename != "services/mocks_test.go" {
+		t.Fatalf("Expected services/mocks_test.go to be written, got %v", generated)
+	}
+	contents, _ := filesystem.ReadFile("/project/services/mocks_test.go")
+	for _, snippet := range expected {
+		if !strings.Contains(contents, snippet) {
+			t.Fatalf("Wanted the fakes to contain %v, got %v", snippet, contents)
+		}
+	}
+}
+
+func TestGenerateMocksSkipsNamesDeclaredInTests(t *testing.T) {
+	// Arrange
+	filesystem := newMocksTestFilesystem(t)
+	mocksService := NewMocksService(filesystem)
+
+	// Act
+	generated, err := mocksService.GenerateMocks("services")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Failed to generate mocks: %v", err)
+	}
+	if strings.Join(generated[0].Interfaces, ",") != "Getter,BookRepository" {
+		t.Fatalf("Wanted [Getter BookRepository], got %v", generated[0].Interfaces)
+	}
+	if len(generated[0].Skipped) != 1 || !strings.HasPrefix(generated[0].Skipped[0], "Cache") {
+		t.Fatalf("Expected Cache to be skipped, got %v", generated[0].Skipped)
+	}
+}
+
+func TestGenerateMocksRefusesHandwrittenFile(t *testing.T) {
+	// Arrange
+	filesystem := newMocksTestFilesystem(t)
+	writeMemoryFile(filesystem, "/project/services/mocks_test.go", "package services\n", t)
+	mocksService := NewMocksService(filesystem)
+
+	// Act
+	_, err := mocksService.GenerateMocks("services")
+
+	// Assert
+	if err == nil {
+		t.Fatal("Expected overwriting a handwritten mocks_test.go to fail")
+	}
+}
+
+func TestRegenerateMocksDeletesFakesOfRemovedInterfaces(t *testing.T) {
+	// Arrange
+	filesystem := newMocksTestFilesystem(t)
+	mocksService := NewMocksService(filesystem)
+	if _, err := mocksService.GenerateMocks(""); err != nil {
+		t.Fatalf("Failed to generate mocks: %v", err)
+	}
+	writeMemoryFile(filesystem, "/project/services/books.go", "package services\n", t)
+
+	// Act
+	generated, err := mocksService.RegenerateMocks()
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Failed to regenerate mocks: %v", err)
+	}
+	if len(generated) != 1 || !generated[0].Deleted {
+		t.Fatalf("Expected the fakes to be deleted, got %v", generated)
+	}
+	if hasFile, _ := filesystem.HasDirectoryOrFile("/project/services/mocks_test.go"); hasFile {
+		t.Fatal("Expected services/mocks_test.go to be deleted")
+	}
+}
@@ -0,0 +1,116 @@
+package services
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+const DEFAULT_MODULE_HOST = "github.com"
+
+// Service for working out the go module path of a new project
+type ModulePathService struct {
+	config UserConfigReader
+	shell  CmdOutputRunner
+}
+
+func NewModulePathService(config UserConfigReader, shell CmdOutputRunner) ModulePathService {
+	return ModulePathService{config, shell}
+}
+
+// Resolves the module path for a project, trying the user config, then the git remote of the
+// project directory (only when initialising in place), then the git username. Falls back to the
+// bare project name if none of those are available
+func (s ModulePathService) ResolveModulePath(projectName, projectDir string) (string, error) {
+	config, err := s.config.UserConfig()
+	if err != nil {
+		return "", fmt.Errorf("unable to read user config: %v", err)
+	}
+
+	if config.ModulePrefix != "" {
+		return fmt.Sprintf("%v/%v", strings.TrimSuffix(config.ModulePrefix, "/"), projectName), nil
+	}
+
+	host := DEFAULT_MODULE_HOST
+	if config.Host != "" {
+		host = strings.TrimSuffix(config.Host, "/")
+	}
+
+	if config.Owner != "" {
+		return fmt.Sprintf("%v/%v/%v", host, config.Owner, projectName), nil
+	}
+
+	// An existing repository already knows exactly where it lives
+	if projectDir == "." {
+		if remote, err := s.shell.RunCmdWithOutput(projectDir, "git", "remote", "get-url", "origin"); err == nil {
+			if modulePath, ok := modulePathFromRemote(remote); ok {
+				return modulePath, nil
+			}
+		}
+	}
+
+	if owner, ok := s.gitUsername(); ok {
+		return fmt.Sprintf("%v/%v/%v", host, owner, projectName), nil
+	}
+
+	return projectName, nil
+}
+
+// Attempts to get the username of the user from their git config, preferring `github.user` as
+// `user.name` is often a display name
+func (s ModulePathService) gitUsername() (string, bool) {
+	for _, key := range []string{"github.user", "user.name"} {
+		name, err := s.shell.RunCmdWithOutput(".", "git", "config", "--get", key)
+		if err != nil {
+			continue
+		}
+
+		if isModulePathElement(name) {
+			return name, true
+		}
+	}
+
+	return "", false
+}
+
+var scpRemoteRegex = regexp.MustCompile(`^(?:[\w.-]+@)?([\w.-]+):(.+)$`)
+
+// Converts a git remote url into a module path, supporting both url and scp style remotes. E.g.
+// `git@gitlab.com:team/project.git` becomes `gitlab.com/team/project`
+func modulePathFromRemote(remote string) (string, bool) {
+	var host, path string
+
+	if strings.Contains(remote, "://") {
+		parsed, err := url.Parse(remote)
+		if err != nil || parsed.Hostname() == "" {
+			return "", false
+		}
+		host, path = parsed.Hostname(), parsed.Path
+	} else {
+		matches := scpRemoteRegex.FindStringSubmatch(remote)
+		if matches == nil {
+			return "", false
+		}
+		host, path = matches[1], matches[2]
+	}
+
+	path = strings.TrimSuffix(strings.Trim(path, "/"), ".git")
+	if path == "" {
+		return "", false
+	}
+
+	for _, element := range strings.Split(path, "/") {
+		if !isModulePathElement(element) {
+			return "", false
+		}
+	}
+
+	return fmt.Sprintf("%v/%v", host, path), true
+}
+
+var modulePathElementRegex = regexp.MustCompile(`^[A-Za-z0-9._~-]+$`)
+
+func isModulePathElement(element string) bool {
+	return modulePathElementRegex.MatchString(element) && !strings.HasPrefix(element, ".") && !strings.HasSuffix(element, ".")
+}
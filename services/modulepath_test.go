@@ -0,0 +1,122 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/danielronalds/gotm/models"
+)
+
+type mockUserConfig struct {
+	config models.UserConfig
+}
+
+func (m mockUserConfig) UserConfig() (models.UserConfig, error) {
+	return m.config, nil
+}
+
+// Mock implementation of the shell, returning canned output for `git config` and `git remote`
+type mockGitShell struct {
+	outputs map[string]string
+}
+
+func (m mockGitShell) RunCmdWithOutput(dir, program string, args ...string) (string, error) {
+	output, ok := m.outputs[args[len(args)-1]]
+	if !ok {
+		return "", errors.New("exit status 1")
+	}
+	return output, nil
+}
+
+func TestResolveModulePathPrefersModulePrefix(t *testing.T) {
+	// Arrange
+	config := mockUserConfig{models.UserConfig{ModulePrefix: "example.com/foo/", Owner: "ignored"}}
+	shell := mockGitShell{map[string]string{"user.name": "someone"}}
+	service := NewModulePathService(config, shell)
+
+	// Act
+	modulePath, err := service.ResolveModulePath("app", "app")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if modulePath != "example.com/foo/app" {
+		t.Fatalf("Wanted example.com/foo/app, got %v", modulePath)
+	}
+}
+
+func TestResolveModulePathUsesConfiguredHostWithGitUsername(t *testing.T) {
+	// Arrange
+	config := mockUserConfig{models.UserConfig{Host: "gitlab.example.com"}}
+	shell := mockGitShell{map[string]string{"user.name": "Display Name", "github.user": "someone"}}
+	service := NewModulePathService(config, shell)
+
+	// Act
+	modulePath, err := service.ResolveModulePath("app", "app")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if modulePath != "gitlab.example.com/someone/app" {
+		t.Fatalf("Wanted gitlab.example.com/someone/app, got %v", modulePath)
+	}
+}
+
+func TestResolveModulePathUsesRemoteWhenInitialisingInPlace(t *testing.T) {
+	// Arrange
+	shell := mockGitShell{map[string]string{"origin": "git@gitlab.com:team/sub/project.git", "user.name": "someone"}}
+	service := NewModulePathService(mockUserConfig{}, shell)
+
+	// Act
+	modulePath, err := service.ResolveModulePath("app", ".")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if modulePath != "gitlab.com/team/sub/project" {
+		t.Fatalf("Wanted gitlab.com/team/sub/project, got %v", modulePath)
+	}
+}
+
+func TestResolveModulePathFallsBackToProjectName(t *testing.T) {
+	// Arrange
+	shell := mockGitShell{map[string]string{"user.name": "Display Name"}}
+	service := NewModulePathService(mockUserConfig{}, shell)
+
+	// Act
+	modulePath, err := service.ResolveModulePath("app", "app")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if modulePath != "app" {
+		t.Fatalf("Wanted app, got %v", modulePath)
+	}
+}
+
+func TestModulePathFromRemote(t *testing.T) {
+	inputs := []struct {
+		remote   string
+		expected string
+		ok       bool
+	}{
+		{remote: "https://github.com/owner/repo.git", expected: "github.com/owner/repo", ok: true},
+		{remote: "ssh://git@git.example.com:2222/owner/repo.git", expected: "git.example.com/owner/repo", ok: true},
+		{remote: "git@github.com:owner/repo", expected: "github.com/owner/repo", ok: true},
+		{remote: "/some/local/path", ok: false},
+	}
+
+	for _, input := range inputs {
+		// Act
+		result, ok := modulePathFromRemote(input.remote)
+
+		// Assert
+		if ok != input.ok || result != input.expected {
+			t.Fatalf("%v: wanted (%v, %v), got (%v, %v)", input.remote, input.expected, input.ok, result, ok)
+		}
+	}
+}
@@ -0,0 +1,205 @@
+package services
+
+import (
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// Initialisms written in a single case in Go identifiers, i.e. UserID rather than UserId
+var goInitialisms = []string{
+	"ACL", "API", "ASCII", "CPU", "CSS", "DNS", "EOF", "GUID", "HTML", "HTTP", "HTTPS", "ID", "IP",
+	"JSON", "QPS", "RAM", "RPC", "SLA", "SMTP", "SQL", "SSH", "TCP", "TLS", "TTL", "UDP", "UI", "UID",
+	"UUID", "URI", "URL", "UTF8", "VM", "XML", "XMPP", "XSRF", "XSS",
+}
+
+// The forms of a name used in generated code. Every component template is given these
+type identifier struct {
+	// Type names, i.e. UserProfile
+	Name string
+	// Variable names, i.e. userProfile
+	CamelName string
+	// File and table names, i.e. user_profile
+	SnakeName string
+	// URL paths, i.e. user-profile
+	KebabName string
+	// Messages, i.e. user profile
+	Words string
+	// Type name in lower case, i.e. userprofile
+	LowerCaseName string
+}
+
+// A component's name split on slashes into the directories it's nested in and its own name, i.e.
+// admin/users is the users component nested in admin
+type componentName struct {
+	identifier
+	// Names of the directories the component is nested in, outermost first
+	namespace []identifier
+}
+
+func parseComponentName(name string) componentName {
+	segments := strings.FieldsFunc(name, func(r rune) bool { return r == '/' })
+	if len(segments) == 0 {
+		return componentName{}
+	}
+
+	namespace := make([]identifier, 0, len(segments)-1)
+	for _, segment := range segments[:len(segments)-1] {
+		namespace = append(namespace, newIdentifier(segment))
+	}
+
+	return componentName{newIdentifier(segments[len(segments)-1]), namespace}
+}
+
+// Returns the directory the component is nested in, relative to the directory of its type. Go
+// packages are named in lower case while frontend directories are kebab case
+func (n componentName) dir(goPackage bool) string {
+	dirs := make([]string, 0, len(n.namespace))
+	for _, segment := range n.namespace {
+		if goPackage {
+			dirs = append(dirs, segment.LowerCaseName)
+		} else {
+			dirs = append(dirs, segment.KebabName)
+		}
+	}
+	return filepath.Join(dirs...)
+}
+
+// Returns the path the component is served under, made from the namespace and the given name of
+// the component, i.e. /admin/users
+func (n componentName) routePath(leaf identifier) string {
+	segments := make([]string, 0, len(n.namespace)+1)
+	for _, segment := range n.namespace {
+		segments = append(segments, segment.KebabName)
+	}
+	return "/" + path.Join(append(segments, leaf.KebabName)...)
+}
+
+// Returns the forms of the name including its namespace, i.e. AdminUsers, used where components
+// from different directories meet like the router
+func (n componentName) qualified() identifier {
+	words := make([]string, 0)
+	for _, segment := range n.namespace {
+		words = append(words, segment.Words)
+	}
+	return newIdentifier(strings.Join(append(words, n.Words), " "))
+}
+
+// Creates the forms of a name written in any style, i.e. userProfile, UserProfile, user_profile,
+// user-profile or "user profile"
+func newIdentifier(name string) identifier {
+	return identifierFromWords(nameWords(name))
+}
+
+func identifierFromWords(words []string) identifier {
+	pascal := ""
+	for _, word := range words {
+		pascal += pascalWord(word)
+	}
+
+	// camelCase starts with the first word in lower case, even when it's an initialism like id
+	camel := ""
+	if len(words) != 0 {
+		camel = words[0] + strings.TrimPrefix(pascal, pascalWord(words[0]))
+	}
+
+	return identifier{
+		Name:          pascal,
+		CamelName:     camel,
+		SnakeName:     strings.Join(words, "_"),
+		KebabName:     strings.Join(words, "-"),
+		Words:         strings.Join(words, " "),
+		LowerCaseName: strings.ToLower(pascal),
+	}
+}
+
+// Capitalises the word, or upper cases it if it's an initialism
+func pascalWord(word string) string {
+	if slices.Contains(goInitialisms, strings.ToUpper(word)) {
+		return strings.ToUpper(word)
+	}
+	return capitalise(word)
+}
+
+// Returns the forms of the plural of the name, i.e. userProfiles
+func pluralIdentifier(name string) identifier {
+	words := nameWords(name)
+	if len(words) != 0 {
+		words[len(words)-1] = pluralise(words[len(words)-1])
+	}
+	return identifierFromWords(words)
+}
+
+// Splits a name into lower case words. Words are separated by any character that isn't a letter or
+// digit, and by changes in case, treating runs of upper case letters as a single word so HTTPServer
+// splits into http and server
+func nameWords(name string) []string {
+	words := make([]string, 0)
+
+	for _, part := range splitWords(name) {
+		runes := []rune(part)
+		start := 0
+
+		for i := 1; i < len(runes); i++ {
+			previous, current := runes[i-1], runes[i]
+			next := rune(0)
+			if i+1 < len(runes) {
+				next = runes[i+1]
+			}
+
+			lowerToUpper := (unicode.IsLower(previous) || unicode.IsDigit(previous)) && unicode.IsUpper(current)
+			endOfUpperRun := unicode.IsUpper(previous) && unicode.IsUpper(current) && unicode.IsLower(next)
+
+			if lowerToUpper || endOfUpperRun {
+				words = append(words, strings.ToLower(string(runes[start:i])))
+				start = i
+			}
+		}
+
+		words = append(words, strings.ToLower(string(runes[start:])))
+	}
+
+	return words
+}
+
+// Uppercases the first letter of s, leaving the rest as is
+func capitalise(s string) string {
+	if s == "" {
+		return s
+	}
+
+	runes := []rune(s)
+	return string(unicode.ToUpper(runes[0])) + string(runes[1:])
+}
+
+// Returns the plural of a singular word. Words that are already plural would be pluralised again,
+// i.e. bookses, which is why resource and table names have to be singular
+func pluralise(s string) string {
+	switch {
+	case strings.HasSuffix(s, "s"), strings.HasSuffix(s, "x"), strings.HasSuffix(s, "ch"), strings.HasSuffix(s, "sh"):
+		return s + "es"
+	case strings.HasSuffix(s, "y") && len(s) > 1 && !strings.ContainsAny(s[len(s)-2:len(s)-1], "aeiou"):
+		return strings.TrimSuffix(s, "y") + "ies"
+	}
+
+	return s + "s"
+}
+
+// Returns whether the word looks plural, i.e. books but not status, address or analysis
+func isPlural(s string) bool {
+	return strings.HasSuffix(s, "s") && !strings.HasSuffix(s, "ss") && !strings.HasSuffix(s, "us") && !strings.HasSuffix(s, "is")
+}
+
+// Returns the singular of a plural word, undoing pluralise
+func singularise(s string) string {
+	switch {
+	case strings.HasSuffix(s, "ies") && len(s) > 3:
+		return strings.TrimSuffix(s, "ies") + "y"
+	case strings.HasSuffix(s, "sses"), strings.HasSuffix(s, "uses"), strings.HasSuffix(s, "xes"), strings.HasSuffix(s, "ches"), strings.HasSuffix(s, "shes"):
+		return strings.TrimSuffix(s, "es")
+	}
+
+	return strings.TrimSuffix(s, "s")
+}
@@ -0,0 +1,123 @@
+package services
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestNameWordsSplitsEveryStyle(t *testing.T) {
+	// Arrange
+	inputs := []string{"userProfile", "UserProfile", "user_profile", "user-profile", "user profile", "USER_PROFILE"}
+	expected := []string{"user", "profile"}
+
+	for _, input := range inputs {
+		// Act
+		result := nameWords(input)
+
+		// Assert
+		if !slices.Equal(result, expected) {
+			t.Fatalf("Wanted %v from %v, got %v", expected, input, result)
+		}
+	}
+}
+
+func TestNameWordsKeepsInitialismsTogether(t *testing.T) {
+	// Arrange
+	expected := []string{"http", "server", "id"}
+
+	// Act
+	result := nameWords("HTTPServerID")
+
+	// Assert
+	if !slices.Equal(result, expected) {
+		t.Fatalf("Wanted %v, got %v", expected, result)
+	}
+}
+
+func TestNewIdentifier(t *testing.T) {
+	// Arrange
+	expected := identifier{
+		Name:          "UserProfileURL",
+		CamelName:     "userProfileURL",
+		SnakeName:     "user_profile_url",
+		KebabName:     "user-profile-url",
+		Words:         "user profile url",
+		LowerCaseName: "userprofileurl",
+	}
+
+	// Act
+	result := newIdentifier("userProfileUrl")
+
+	// Assert
+	if result != expected {
+		t.Fatalf("Wanted %+v, got %+v", expected, result)
+	}
+}
+
+func TestNewIdentifierLowerCasesLeadingInitialism(t *testing.T) {
+	// Arrange
+	expected := "apiKey"
+
+	// Act
+	result := newIdentifier("APIKey").CamelName
+
+	// Assert
+	if result != expected {
+		t.Fatalf("Wanted %v, got %v", expected, result)
+	}
+}
+
+func TestPluralIdentifierPluralisesLastWord(t *testing.T) {
+	// Arrange
+	expected := "user-categories"
+
+	// Act
+	result := pluralIdentifier("userCategory").KebabName
+
+	// Assert
+	if result != expected {
+		t.Fatalf("Wanted %v, got %v", expected, result)
+	}
+}
+
+func TestSingulariseUndoesPluralise(t *testing.T) {
+	// Arrange
+	words := []string{"book", "category", "address", "box", "branch", "status", "day"}
+
+	for _, word := range words {
+		// Act
+		plural := pluralise(word)
+
+		// Assert
+		if !isPlural(plural) || isPlural(word) {
+			t.Fatalf("Expected only %v to look plural, not %v", plural, word)
+		}
+		if result := singularise(plural); result != word {
+			t.Fatalf("Wanted %v, got %v", word, result)
+		}
+	}
+}
+
+func TestParseComponentNameSplitsNamespace(t *testing.T) {
+	// Arrange
+	expectedDir := "admin/reports"
+	expectedRoute := "/admin/reports/user-profile"
+	expectedQualified := "AdminReportsUserProfile"
+
+	// Act
+	result := parseComponentName("admin/reports/userProfile")
+
+	// Assert
+	if result.Name != "UserProfile" {
+		t.Fatalf("Wanted UserProfile, got %v", result.Name)
+	}
+	if dir := result.dir(true); dir != expectedDir {
+		t.Fatalf("Wanted %v, got %v", expectedDir, dir)
+	}
+	if route := result.routePath(result.identifier); route != expectedRoute {
+		t.Fatalf("Wanted %v, got %v", expectedRoute, route)
+	}
+	if qualified := result.qualified().Name; qualified != expectedQualified {
+		t.Fatalf("Wanted %v, got %v", expectedQualified, qualified)
+	}
+}
@@ -1,14 +1,31 @@
 package services
 
+import "fmt"
+
+type NpmServiceFilesystem interface {
+	ProjectRoot
+	ProjectManifest
+}
+
 type NpmService struct {
-	filesystem ProjectRoot
+	filesystem NpmServiceFilesystem
 	shell      CmdRunner
 }
 
-func NewNpmService(filesystem ProjectRoot, shell CmdRunner) NpmService {
+func NewNpmService(filesystem NpmServiceFilesystem, shell CmdRunner) NpmService {
 	return NpmService{filesystem, shell}
 }
 
 func (s NpmService) RunNpm(args []string) error {
-	return s.shell.RunCmdWithPipedOutput(s.filesystem.FromRoot("frontend"), "npm", args...)
+	manifest, err := s.filesystem.Manifest()
+	if err != nil {
+		return fmt.Errorf("unable to read project manifest: %v", err)
+	}
+
+	frontendDir, err := s.filesystem.FromRoot(manifest.Frontend)
+	if err != nil {
+		return err
+	}
+
+	return s.shell.RunCmdWithPipedOutput(frontendDir, "npm", args...)
 }
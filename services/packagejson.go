@@ -0,0 +1,158 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// A JSON object that remembers the order of its keys, so files like package.json can be edited
+// without reordering them
+type orderedObject struct {
+	keys   []string
+	values map[string]json.RawMessage
+}
+
+func newOrderedObject() *orderedObject {
+	return &orderedObject{make([]string, 0), make(map[string]json.RawMessage)}
+}
+
+func (o *orderedObject) UnmarshalJSON(data []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+
+	if token, err := decoder.Token(); err != nil || token != json.Delim('{') {
+		return errors.New("expected a JSON object")
+	}
+
+	o.keys, o.values = make([]string, 0), make(map[string]json.RawMessage)
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+
+		var value json.RawMessage
+		if err := decoder.Decode(&value); err != nil {
+			return err
+		}
+
+		o.set(token.(string), value)
+	}
+
+	return nil
+}
+
+func (o orderedObject) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteByte('{')
+
+	for i, key := range o.keys {
+		if i != 0 {
+			buffer.WriteByte(',')
+		}
+		encodedKey, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		buffer.Write(encodedKey)
+		buffer.WriteByte(':')
+		buffer.Write(o.values[key])
+	}
+
+	buffer.WriteByte('}')
+	return buffer.Bytes(), nil
+}
+
+func (o *orderedObject) set(key string, value json.RawMessage) {
+	if _, ok := o.values[key]; !ok {
+		o.keys = append(o.keys, key)
+	}
+	o.values[key] = value
+}
+
+// Returns the nested object stored at key, or an empty one if it doesn't exist
+func (o *orderedObject) object(key string) (*orderedObject, error) {
+	nested := newOrderedObject()
+
+	value, ok := o.values[key]
+	if !ok {
+		return nested, nil
+	}
+
+	if err := json.Unmarshal(value, nested); err != nil {
+		return nil, fmt.Errorf("\"%v\" is not an object", key)
+	}
+
+	return nested, nil
+}
+
+func (o *orderedObject) setObject(key string, value *orderedObject) error {
+	// Not using json.Marshal as it escapes characters like & which are common in npm scripts
+	encoded, err := value.MarshalJSON()
+	if err != nil {
+		return err
+	}
+
+	o.set(key, encoded)
+	return nil
+}
+
+// Merges the dependencies and scripts gotm requires into an existing package.json. Dependencies
+// the project already has are left at their existing versions, as are scripts that already
+// exist, each of which is returned as a conflict
+func mergePackageJson(existing, required []byte) ([]byte, []string, error) {
+	project, gotm := newOrderedObject(), newOrderedObject()
+	if err := json.Unmarshal(existing, project); err != nil {
+		return nil, nil, fmt.Errorf("unable to parse existing package.json: %v", err)
+	}
+	if err := json.Unmarshal(required, gotm); err != nil {
+		return nil, nil, fmt.Errorf("unable to parse gotm package.json: %v", err)
+	}
+
+	conflicts := make([]string, 0)
+
+	for _, section := range []string{"scripts", "dependencies", "devDependencies"} {
+		projectSection, err := project.object(section)
+		if err != nil {
+			return nil, nil, err
+		}
+		gotmSection, err := gotm.object(section)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for _, key := range gotmSection.keys {
+			existingValue, ok := projectSection.values[key]
+			if !ok {
+				projectSection.set(key, gotmSection.values[key])
+				continue
+			}
+			if section == "scripts" && !bytes.Equal(existingValue, gotmSection.values[key]) {
+				conflicts = append(conflicts, fmt.Sprintf("kept existing \"%v\" script, gotm expects %s", key, gotmSection.values[key]))
+			}
+		}
+
+		// npm keeps dependencies sorted, so following suit
+		if section != "scripts" {
+			slices.Sort(projectSection.keys)
+		}
+
+		if len(projectSection.keys) != 0 {
+			if err := project.setObject(section, projectSection); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+
+	var merged bytes.Buffer
+	encoder := json.NewEncoder(&merged)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(project); err != nil {
+		return nil, nil, err
+	}
+
+	return merged.Bytes(), conflicts, nil
+}
@@ -0,0 +1,375 @@
+package services
+
+import (
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Name of the slice in main.go that controllers are registered in
+const CONTROLLERS_SLICE = "controllers"
+
+// A call to a constructor in another package of the project, along with the calls constructing
+// its arguments, i.e. c.NewBooksController(s.NewBooksService(r.NewBooksRepository()))
+type constructorCall struct {
+	importPath string
+	// Names to import the package under when it isn't imported yet, tried before the first letter
+	// of the package's name. Used for nested packages, i.e. admin
+	importNames []string
+	function    string
+	args        []constructorCall
+}
+
+// Returns every import path the call and its arguments need, in the order they're first used
+func (c constructorCall) importPaths() []string {
+	paths := []string{c.importPath}
+	for _, arg := range c.args {
+		for _, argPath := range arg.importPaths() {
+			if !slices.Contains(paths, argPath) {
+				paths = append(paths, argPath)
+			}
+		}
+	}
+	return paths
+}
+
+// Returns the source of the call, using the names the packages are imported under
+func (c constructorCall) render(names map[string]string) string {
+	args := make([]string, 0, len(c.args))
+	for _, arg := range c.args {
+		args = append(args, arg.render(names))
+	}
+	return fmt.Sprintf("%v.%v(%v)", names[c.importPath], c.function, strings.Join(args, ", "))
+}
+
+// Returns the names each package of the call would rather be imported under, keyed by import path
+func (c constructorCall) preferredNames() map[string][]string {
+	preferred := map[string][]string{c.importPath: c.importNames}
+	for _, arg := range c.args {
+		for argPath, names := range arg.preferredNames() {
+			if _, ok := preferred[argPath]; !ok {
+				preferred[argPath] = names
+			}
+		}
+	}
+	return preferred
+}
+
+// Returns the names packages of the call are imported under in a new main.go, i.e. c for
+// controllers
+func defaultImportNames(call constructorCall) map[string]string {
+	names, _ := importNames(&ast.File{}, call)
+	return names
+}
+
+// Adds the constructor call to the controllers slice in the source, i.e.
+// `controllers := []Controller{...}`, importing any packages it needs. The result is gofmt
+// formatted. Source that already registers the controller is returned unchanged
+func registerController(source string, call constructorCall) (string, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", source, parser.ParseComments)
+	if err != nil {
+		return "", fmt.Errorf("unable to parse: %v", err)
+	}
+
+	slice := findControllersSlice(file)
+	if slice == nil {
+		return "", fmt.Errorf("unable to find `%v := []Controller{...}`", CONTROLLERS_SLICE)
+	}
+
+	names, missing := importNames(file, call)
+
+	for _, element := range slice.Elts {
+		if isCallTo(element, names[call.importPath], call.function) {
+			return source, nil
+		}
+	}
+
+	// Edits are made to the source text rather than the tree so comments stay where they are, and
+	// applied back to front so earlier offsets aren't shifted
+	edits := []sourceEdit{elementEdit(fset, slice, call.render(names))}
+	if len(missing) != 0 {
+		edits = append([]sourceEdit{importEdit(fset, file, names, missing)}, edits...)
+	}
+
+	edited := source
+	for i := len(edits) - 1; i >= 0; i-- {
+		edited = edited[:edits[i].offset] + edits[i].text + edited[edits[i].offset:]
+	}
+
+	formatted, err := format.Source([]byte(edited))
+	if err != nil {
+		return "", fmt.Errorf("unable to format: %v", err)
+	}
+
+	return string(formatted), nil
+}
+
+// Text to insert at an offset in the source
+type sourceEdit struct {
+	offset int
+	text   string
+}
+
+// Returns the composite literal assigned to the controllers slice, nil if there isn't one
+func findControllersSlice(file *ast.File) *ast.CompositeLit {
+	var slice *ast.CompositeLit
+
+	ast.Inspect(file, func(node ast.Node) bool {
+		assign, ok := node.(*ast.AssignStmt)
+		if !ok || slice != nil || len(assign.Lhs) != 1 || len(assign.Rhs) != 1 {
+			return slice == nil
+		}
+
+		ident, ok := assign.Lhs[0].(*ast.Ident)
+		if !ok || ident.Name != CONTROLLERS_SLICE {
+			return true
+		}
+
+		if literal, ok := assign.Rhs[0].(*ast.CompositeLit); ok {
+			if _, ok := literal.Type.(*ast.ArrayType); ok {
+				slice = literal
+			}
+		}
+
+		return slice == nil
+	})
+
+	return slice
+}
+
+// Returns the names each package of the call is referred to by in the file, along with the packages
+// that aren't imported yet. Missing packages are given a name that doesn't clash with any other
+// import, preferring the names given in the call, then the first letter of the package like gotm's
+// own imports
+func importNames(file *ast.File, call constructorCall) (map[string]string, []string) {
+	names := make(map[string]string)
+	used := make(map[string]bool)
+
+	for _, spec := range file.Imports {
+		specPath, _ := strconv.Unquote(spec.Path.Value)
+		name := path.Base(specPath)
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+
+		names[specPath] = name
+		used[name] = true
+	}
+
+	preferred := call.preferredNames()
+	missing := make([]string, 0)
+	for _, importPath := range call.importPaths() {
+		if _, ok := names[importPath]; ok {
+			continue
+		}
+
+		base := path.Base(importPath)
+		name := fmt.Sprintf("gotm%v", base)
+		for _, candidate := range append(slices.Clone(preferred[importPath]), base[:1], base) {
+			if !used[candidate] {
+				name = candidate
+				break
+			}
+		}
+
+		names[importPath] = name
+		used[name] = true
+		missing = append(missing, importPath)
+	}
+
+	return names, missing
+}
+
+// Returns whether the expression is a call to pkg.function()
+func isCallTo(expr ast.Expr, pkg, function string) bool {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok {
+		return false
+	}
+
+	selector, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+
+	ident, ok := selector.X.(*ast.Ident)
+	return ok && ident.Name == pkg && selector.Sel.Name == function
+}
+
+// Returns the edit appending the element to the end of the composite literal
+func elementEdit(fset *token.FileSet, literal *ast.CompositeLit, element string) sourceEdit {
+	rbrace := fset.Position(literal.Rbrace)
+
+	if len(literal.Elts) == 0 {
+		if fset.Position(literal.Lbrace).Line == rbrace.Line {
+			return sourceEdit{rbrace.Offset, element}
+		}
+		return sourceEdit{rbrace.Offset, element + ",\n"}
+	}
+
+	// Elements followed by a closing brace on the same line don't have a trailing comma
+	last := fset.Position(literal.Elts[len(literal.Elts)-1].End())
+	if last.Line == rbrace.Line {
+		return sourceEdit{last.Offset, ", " + element}
+	}
+
+	return sourceEdit{rbrace.Offset, element + ",\n"}
+}
+
+// Returns the edit importing the packages under the given names
+func importEdit(fset *token.FileSet, file *ast.File, names map[string]string, importPaths []string) sourceEdit {
+	specs := make([]string, 0, len(importPaths))
+	for _, importPath := range importPaths {
+		if names[importPath] == path.Base(importPath) {
+			specs = append(specs, strconv.Quote(importPath))
+		} else {
+			specs = append(specs, fmt.Sprintf("%v %q", names[importPath], importPath))
+		}
+	}
+
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.IMPORT {
+			continue
+		}
+
+		// Adding to the end of the last group, rather than starting a new group before the paren
+		if gen.Lparen.IsValid() && len(gen.Specs) != 0 {
+			return sourceEdit{fset.Position(gen.Specs[len(gen.Specs)-1].End()).Offset, "\n" + strings.Join(specs, "\n")}
+		}
+		if gen.Lparen.IsValid() {
+			return sourceEdit{fset.Position(gen.Rparen).Offset, "\n" + strings.Join(specs, "\n") + "\n"}
+		}
+		return sourceEdit{fset.Position(gen.End()).Offset, "\nimport " + strings.Join(specs, "\nimport ")}
+	}
+
+	return sourceEdit{fset.Position(file.Name.End()).Offset, "\n\nimport " + strings.Join(specs, "\nimport ")}
+}
+
+// Removes calls to the constructor, given as its import path and name i.e.
+// example.com/app/controllers.NewBooksController, from the controllers slice in the source, along
+// with any imports only they used. Returns whether the constructor was registered, the source is
+// returned unchanged if it wasn't
+func unregisterController(source string, constructor string) (string, bool, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", source, parser.ParseComments)
+	if err != nil {
+		return "", false, fmt.Errorf("unable to parse: %v", err)
+	}
+
+	slice := findControllersSlice(file)
+	if slice == nil {
+		return source, false, nil
+	}
+
+	dot := strings.LastIndex(constructor, ".")
+	importPath, function := constructor[:max(dot, 0)], constructor[dot+1:]
+
+	names, missing := importNames(file, constructorCall{importPath: importPath})
+	if len(missing) != 0 {
+		return source, false, nil
+	}
+
+	index := slices.IndexFunc(slice.Elts, func(element ast.Expr) bool {
+		return isCallTo(element, names[importPath], function)
+	})
+	if index == -1 {
+		return source, false, nil
+	}
+
+	// Packages the call refers to, which may no longer be needed once it's gone
+	packages := make([]string, 0)
+	ast.Inspect(slice.Elts[index], func(node ast.Node) bool {
+		if selector, ok := node.(*ast.SelectorExpr); ok {
+			if ident, ok := selector.X.(*ast.Ident); ok && !slices.Contains(packages, ident.Name) {
+				packages = append(packages, ident.Name)
+			}
+		}
+		return true
+	})
+
+	// Removing the element along with the separator between it and its neighbour
+	start, end := fset.Position(slice.Elts[index].Pos()).Offset, fset.Position(slice.Elts[index].End()).Offset
+	switch {
+	case index+1 < len(slice.Elts):
+		end = fset.Position(slice.Elts[index+1].Pos()).Offset
+	case index > 0:
+		start = fset.Position(slice.Elts[index-1].End()).Offset
+	default:
+		start, end = fset.Position(slice.Lbrace).Offset+1, fset.Position(slice.Rbrace).Offset
+	}
+	edited := source[:start] + source[end:]
+
+	edited, err = removeUnusedImports(edited, packages)
+	if err != nil {
+		return "", false, err
+	}
+
+	formatted, err := format.Source([]byte(edited))
+	if err != nil {
+		return "", false, fmt.Errorf("unable to format: %v", err)
+	}
+
+	return string(formatted), true, nil
+}
+
+// Removes the imports of the packages, referred to by the names they're imported under, that
+// are no longer used in the source
+func removeUnusedImports(source string, packages []string) (string, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", source, parser.ParseComments)
+	if err != nil {
+		return "", fmt.Errorf("unable to parse: %v", err)
+	}
+
+	used := make(map[string]bool)
+	ast.Inspect(file, func(node ast.Node) bool {
+		if selector, ok := node.(*ast.SelectorExpr); ok {
+			if ident, ok := selector.X.(*ast.Ident); ok {
+				used[ident.Name] = true
+			}
+		}
+		return true
+	})
+
+	edited := source
+	for i := len(file.Imports) - 1; i >= 0; i-- {
+		spec := file.Imports[i]
+		specPath, _ := strconv.Unquote(spec.Path.Value)
+		name := path.Base(specPath)
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+
+		if !slices.Contains(packages, name) || used[name] {
+			continue
+		}
+
+		start, end := fset.Position(spec.Pos()).Offset, fset.Position(spec.End()).Offset
+		// Single imports have the import keyword removed with them
+		if decl := importDeclOf(file, spec); decl != nil && !decl.Lparen.IsValid() {
+			start, end = fset.Position(decl.Pos()).Offset, fset.Position(decl.End()).Offset
+		}
+		edited = edited[:start] + edited[end:]
+	}
+
+	return edited, nil
+}
+
+// Returns the import declaration the spec belongs to
+func importDeclOf(file *ast.File, spec *ast.ImportSpec) *ast.GenDecl {
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if ok && gen.Tok == token.IMPORT && slices.Contains(gen.Specs, ast.Spec(spec)) {
+			return gen
+		}
+	}
+	return nil
+}
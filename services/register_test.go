@@ -0,0 +1,181 @@
+package services
+
+import (
+	"strings"
+	"testing"
+)
+
+const testMainFile = `package main
+
+import (
+	"net/http"
+
+	c "example.com/app/controllers"
+)
+
+func main() {
+	mux := http.NewServeMux()
+
+	// Every controller of the app
+	controllers := []Controller{
+		c.NewHelloController(),
+	}
+
+	for _, controller := range controllers {
+		controller.RegisterRoutes(mux)
+	}
+}
+`
+
+func TestRegisterControllerAppendsToSlice(t *testing.T) {
+	// Arrange
+	expected := "\t\tc.NewHelloController(),\n\t\tc.NewBooksController(),\n\t}"
+
+	// Act
+	result, err := registerController(testMainFile, constructorCall{importPath: "example.com/app/controllers", function: "NewBooksController"})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Failed to register controller: %v", err)
+	}
+	if !strings.Contains(result, expected) {
+		t.Fatalf("Expected controller to be appended, got:\n%v", result)
+	}
+	if !strings.Contains(result, "// Every controller of the app") {
+		t.Fatalf("Expected comments to be kept, got:\n%v", result)
+	}
+}
+
+func TestRegisterControllerIsIdempotent(t *testing.T) {
+	// Act
+	result, err := registerController(testMainFile, constructorCall{importPath: "example.com/app/controllers", function: "NewHelloController"})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Failed to register controller: %v", err)
+	}
+	if result != testMainFile {
+		t.Fatalf("Expected source to be unchanged, got:\n%v", result)
+	}
+}
+
+func TestRegisterControllerAddsMissingImport(t *testing.T) {
+	// Arrange
+	source := "package main\n\nimport \"net/http\"\n\nfunc main() {\n\tcontrollers := []Controller{}\n\t_ = controllers\n\t_ = http.NewServeMux\n}\n"
+
+	// Act
+	result, err := registerController(source, constructorCall{importPath: "example.com/app/controllers", function: "NewBooksController"})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Failed to register controller: %v", err)
+	}
+	if !strings.Contains(result, `import c "example.com/app/controllers"`) {
+		t.Fatalf("Expected controllers package to be imported, got:\n%v", result)
+	}
+	if !strings.Contains(result, "[]Controller{c.NewBooksController()}") {
+		t.Fatalf("Expected controller to be registered, got:\n%v", result)
+	}
+}
+
+func TestRegisterControllerFailsWithoutSlice(t *testing.T) {
+	// Arrange
+	source := "package main\n\nfunc main() {}\n"
+
+	// Act
+	_, err := registerController(source, constructorCall{importPath: "example.com/app/controllers", function: "NewBooksController"})
+
+	// Assert
+	if err == nil {
+		t.Fatal("Expected registration to fail")
+	}
+}
+
+func TestRegisterControllerImportsConstructorArguments(t *testing.T) {
+	// Arrange
+	call := constructorCall{
+		importPath: "example.com/app/controllers",
+		function:   "NewBooksController",
+		args: []constructorCall{{
+			importPath: "example.com/app/services",
+			function:   "NewBooksService",
+			args:       []constructorCall{{importPath: "example.com/app/repositories", function: "NewBooksRepository"}},
+		}},
+	}
+
+	// Act
+	result, err := registerController(testMainFile, call)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Failed to register controller: %v", err)
+	}
+	for _, expected := range []string{
+		"c.NewBooksController(s.NewBooksService(r.NewBooksRepository())),",
+		`s "example.com/app/services"`,
+		`r "example.com/app/repositories"`,
+	} {
+		if !strings.Contains(result, expected) {
+			t.Fatalf("Expected %v in result, got:\n%v", expected, result)
+		}
+	}
+}
+
+func TestUnregisterControllerRemovesCallAndUnusedImports(t *testing.T) {
+	// Arrange
+	source, err := registerController(testMainFile, constructorCall{
+		importPath: "example.com/app/controllers",
+		function:   "NewBooksController",
+		args:       []constructorCall{{importPath: "example.com/app/services", function: "NewBooksService"}},
+	})
+	if err != nil {
+		t.Fatalf("Failed to register controller: %v", err)
+	}
+
+	// Act
+	result, registered, err := unregisterController(source, "example.com/app/controllers.NewBooksController")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Failed to unregister controller: %v", err)
+	}
+	if !registered {
+		t.Fatal("Expected the controller to be found")
+	}
+	if result != testMainFile {
+		t.Fatalf("Wanted:\n%v\ngot:\n%v", testMainFile, result)
+	}
+}
+
+func TestUnregisterControllerLeavesUnregisteredSourceUnchanged(t *testing.T) {
+	// Act
+	result, registered, err := unregisterController(testMainFile, "example.com/app/controllers.NewBooksController")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Failed to unregister controller: %v", err)
+	}
+	if registered || result != testMainFile {
+		t.Fatalf("Expected source to be unchanged, got:\n%v", result)
+	}
+}
+
+func TestRegisterControllerImportsNestedPackageUnderItsName(t *testing.T) {
+	// Arrange
+	call := constructorCall{
+		importPath:  "example.com/app/controllers/admin",
+		importNames: []string{"admin", "adminc"},
+		function:    "NewUsersController",
+	}
+
+	// Act
+	result, err := registerController(testMainFile, call)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Failed to register controller: %v", err)
+	}
+	if !strings.Contains(result, "\"example.com/app/controllers/admin\"") || !strings.Contains(result, "admin.NewUsersController(),") {
+		t.Fatalf("Expected nested package to be imported as admin, got:\n%v", result)
+	}
+}
@@ -0,0 +1,266 @@
+package services
+
+import (
+	"fmt"
+	"go/parser"
+	"go/token"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/danielronalds/gotm/models"
+)
+
+// Works out what removing the component involves: the files generating it would have created,
+// whether they've changed since, and where it's registered. Nothing is removed
+func (s ComponentService) PlanRemoval(componentType, name string) (models.ComponentRemoval, error) {
+	removal := models.ComponentRemoval{
+		Files:       make([]string, 0),
+		Modified:    make([]string, 0),
+		Controllers: make([]string, 0),
+		Pages:       make([]string, 0),
+		Directories: make([]string, 0),
+	}
+
+	files, controllers, pages, err := s.componentFiles(componentType, name)
+	if err != nil {
+		return removal, err
+	}
+
+	ledger, err := s.readLedger()
+	if err != nil {
+		return removal, err
+	}
+
+	for _, file := range files {
+		filename, err := s.componentPath(file.componentType, file.filename)
+		if err != nil {
+			return removal, err
+		}
+
+		hasFile, err := s.filesystem.HasDirectoryOrFile(filename)
+		if err != nil {
+			return removal, fmt.Errorf("unable to check if %v exists: %v", file.filename, err.Error())
+		}
+		if !hasFile {
+			continue
+		}
+
+		relative, err := s.relativeToRoot(filename)
+		if err != nil {
+			return removal, err
+		}
+		removal.Files = append(removal.Files, relative)
+
+		dir := path.Dir(relative)
+		for range parseComponentName(name).namespace {
+			if !slices.Contains(removal.Directories, dir) {
+				removal.Directories = append(removal.Directories, dir)
+			}
+			dir = path.Dir(dir)
+		}
+
+		contents, err := s.filesystem.ReadFile(filename)
+		if err != nil {
+			return removal, fmt.Errorf("unable to read %v: %v", relative, err.Error())
+		}
+		if sum, ok := ledger[relative]; !ok || sum != checksum(contents) {
+			removal.Modified = append(removal.Modified, relative)
+		}
+	}
+
+	if len(removal.Files) == 0 {
+		return removal, fmt.Errorf("no %v named \"%v\" was found", componentType, name)
+	}
+
+	for _, controller := range controllers {
+		_, source, err := s.controllersFile()
+		if err != nil {
+			return removal, err
+		}
+		if _, registered, err := unregisterController(source, controller); err == nil && registered {
+			removal.Controllers = append(removal.Controllers, controller)
+		}
+	}
+
+	for _, page := range pages {
+		_, source, err := s.routerFile()
+		if err != nil {
+			return removal, err
+		}
+		if _, routed := unregisterPageRoute(source, page); routed {
+			removal.Pages = append(removal.Pages, page)
+		}
+	}
+
+	return removal, nil
+}
+
+// Removes the component's registrations and files. Files that have been modified since they were
+// generated are only removed when forced
+func (s ComponentService) RemoveComponent(removal models.ComponentRemoval, force bool) error {
+	if len(removal.Modified) != 0 && !force {
+		return fmt.Errorf("%v changed since gotm generated it, or wasn't generated by gotm. Pass --force to remove it anyway", strings.Join(removal.Modified, ", "))
+	}
+
+	// Unregistering first, so a failure leaves the project as it was
+	for _, controller := range removal.Controllers {
+		filename, source, err := s.controllersFile()
+		if err != nil {
+			return err
+		}
+		unregistered, _, err := unregisterController(source, controller)
+		if err != nil {
+			return fmt.Errorf("unable to unregister %v: %v", controller, err)
+		}
+		if err := s.filesystem.WriteFile(filename, unregistered); err != nil {
+			return fmt.Errorf("unable to write %v: %v", filepath.Base(filename), err.Error())
+		}
+	}
+
+	for _, page := range removal.Pages {
+		filename, source, err := s.routerFile()
+		if err != nil {
+			return err
+		}
+		unrouted, _ := unregisterPageRoute(source, page)
+		if err := s.filesystem.WriteFile(filename, unrouted); err != nil {
+			return fmt.Errorf("unable to write router: %v", err.Error())
+		}
+	}
+
+	ledger, err := s.readLedger()
+	if err != nil {
+		return err
+	}
+
+	for _, file := range removal.Files {
+		filename, err := s.filesystem.FromRoot(filepath.FromSlash(file))
+		if err != nil {
+			return err
+		}
+		if err := s.filesystem.DeleteFileRecursive(filename); err != nil {
+			return fmt.Errorf("unable to delete %v: %v", file, err.Error())
+		}
+		delete(ledger, file)
+	}
+
+	for _, dir := range removal.Directories {
+		dirname, err := s.filesystem.FromRoot(filepath.FromSlash(dir))
+		if err != nil {
+			return err
+		}
+		if hasDir, err := s.filesystem.HasDirectoryOrFile(dirname); err != nil || !hasDir {
+			continue
+		}
+		if files, err := s.filesystem.ReadDirRecursive(dirname); err != nil || len(files) != 0 {
+			continue
+		}
+		if err := s.filesystem.DeleteFileRecursive(dirname); err != nil {
+			return fmt.Errorf("unable to delete %v: %v", dir, err.Error())
+		}
+	}
+
+	return s.writeLedger(ledger)
+}
+
+// Returns the files generating the component creates, along with the constructors of any
+// controllers, qualified by their import path, and the pages it registers
+func (s ComponentService) componentFiles(componentType, name string) ([]resourceFile, []string, []string, error) {
+	componentName := parseComponentName(name)
+	pages := make([]string, 0)
+	controllers := make([]string, 0)
+
+	switch componentType {
+	case "controller", "resource":
+		manifest, err := s.filesystem.Manifest()
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("unable to read project manifest: %v", err.Error())
+		}
+		modulePath, err := s.modulePath(manifest)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+
+		importPath, _ := packageImport(modulePath, manifest.Directories.Controllers, "controller", componentName)
+		controllers = append(controllers, fmt.Sprintf("%v.New%vController", importPath, componentName.Name))
+	case "migration", "table":
+		return nil, nil, nil, fmt.Errorf("%vs can't be removed as they may have been applied, roll them back with `gotm db down` and delete them by hand", componentType)
+	}
+
+	var files []resourceFile
+	switch componentType {
+	case "controller", "service", "repository", "middleware", "model", VIEW_COMPONENT_TYPE:
+		files = []resourceFile{{componentType: componentType, filename: componentFilename(componentType, componentName)}}
+	case "page":
+		pages = append(pages, fmt.Sprintf("%vPage", componentName.qualified().Name))
+		files = []resourceFile{{componentType: "page", filename: componentFilename(componentType, componentName)}}
+	case "resource":
+		qualified := componentName.qualified().Name
+		pages = append(pages, fmt.Sprintf("%vListPage", qualified), fmt.Sprintf("%vDetailPage", qualified))
+		files = resourceFiles(componentName)
+	default:
+		return nil, nil, nil, fmt.Errorf("\"%v\" is not a component that can be removed", componentType)
+	}
+
+	// Tests of the component go with it, as they won't compile without it
+	for _, file := range files {
+		if _, ok := testTemplates[file.componentType]; ok {
+			files = append(files, resourceFile{componentType: file.componentType, filename: testFilename(file.filename)})
+		}
+	}
+
+	return files, controllers, pages, nil
+}
+
+// Returns the path and source of the file the controllers slice is in, either main.go or
+// routes.go for adopted projects. The source is empty if neither has the slice
+func (s ComponentService) controllersFile() (string, string, error) {
+	manifest, err := s.filesystem.Manifest()
+	if err != nil {
+		return "", "", fmt.Errorf("unable to read project manifest: %v", err.Error())
+	}
+
+	for _, candidate := range []string{"main.go", ADOPTED_ROUTES_FILE} {
+		filename, err := s.filesystem.FromRoot(filepath.Join(manifest.Main, candidate))
+		if err != nil {
+			return "", "", err
+		}
+
+		source, err := s.filesystem.ReadFile(filename)
+		if err != nil {
+			return "", "", fmt.Errorf("unable to read %v: %v", candidate, err.Error())
+		}
+		if source == "" {
+			continue
+		}
+
+		file, err := parser.ParseFile(token.NewFileSet(), "", source, 0)
+		if err == nil && findControllersSlice(file) != nil {
+			return filename, source, nil
+		}
+	}
+
+	return "", "", nil
+}
+
+// Returns the path and source of the router file
+func (s ComponentService) routerFile() (string, string, error) {
+	manifest, err := s.filesystem.Manifest()
+	if err != nil {
+		return "", "", fmt.Errorf("unable to read project manifest: %v", err.Error())
+	}
+
+	filename, err := s.filesystem.FromRoot(filepath.Join(manifest.Frontend, "src", "index.ts"))
+	if err != nil {
+		return "", "", err
+	}
+
+	source, err := s.filesystem.ReadFile(filename)
+	if err != nil {
+		return "", "", fmt.Errorf("unable to read router: %v", err.Error())
+	}
+
+	return filename, source, nil
+}
@@ -0,0 +1,110 @@
+package services
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/danielronalds/gotm/models"
+	"github.com/danielronalds/gotm/repositories"
+)
+
+func TestRemoveComponentDeletesGeneratedFile(t *testing.T) {
+	// Arrange
+	filesystem := repositories.NewMemoryFilesystemRepository("/project")
+	writeMemoryFile(filesystem, "/project/gotm.json", `{"name": "project"}`, t)
+	componentService := NewComponentService(filesystem, mockTemplates{})
+	if err := componentService.GenerateService("books", models.ComponentOptions{}); err != nil {
+		t.Fatalf("Failed to generate service: %v", err)
+	}
+
+	// Act
+	removal, err := componentService.PlanRemoval("service", "books")
+	if err != nil {
+		t.Fatalf("Failed to plan removal: %v", err)
+	}
+	err = componentService.RemoveComponent(removal, false)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Failed to remove service: %v", err)
+	}
+	if !slices.Equal(removal.Files, []string{"services/books.go"}) {
+		t.Fatalf("Wanted [services/books.go], got %v", removal.Files)
+	}
+	if hasFile, _ := filesystem.HasDirectoryOrFile("/project/services/books.go"); hasFile {
+		t.Fatal("Expected services/books.go to be deleted")
+	}
+}
+
+func TestRemoveComponentRefusesModifiedFileUnlessForced(t *testing.T) {
+	// Arrange
+	filesystem := repositories.NewMemoryFilesystemRepository("/project")
+	writeMemoryFile(filesystem, "/project/gotm.json", `{"name": "project"}`, t)
+	componentService := NewComponentService(filesystem, mockTemplates{})
+	if err := componentService.GenerateService("books", models.ComponentOptions{}); err != nil {
+		t.Fatalf("Failed to generate service: %v", err)
+	}
+	writeMemoryFile(filesystem, "/project/services/books.go", "package services // edited", t)
+
+	// Act
+	removal, err := componentService.PlanRemoval("service", "books")
+	if err != nil {
+		t.Fatalf("Failed to plan removal: %v", err)
+	}
+	unforcedErr := componentService.RemoveComponent(removal, false)
+	forcedErr := componentService.RemoveComponent(removal, true)
+
+	// Assert
+	if unforcedErr == nil {
+		t.Fatal("Expected removing a modified file to fail without --force")
+	}
+	if forcedErr != nil {
+		t.Fatalf("Failed to force remove service: %v", forcedErr)
+	}
+	if hasFile, _ := filesystem.HasDirectoryOrFile("/project/services/books.go"); hasFile {
+		t.Fatal("Expected services/books.go to be deleted")
+	}
+}
+
+func TestPlanRemovalFailsForMissingComponent(t *testing.T) {
+	// Arrange
+	filesystem := repositories.NewMemoryFilesystemRepository("/project")
+	writeMemoryFile(filesystem, "/project/gotm.json", `{"name": "project"}`, t)
+	componentService := NewComponentService(filesystem, mockTemplates{})
+
+	// Act
+	_, err := componentService.PlanRemoval("page", "setings")
+
+	// Assert
+	if err == nil {
+		t.Fatal("Expected removing a component that doesn't exist to fail")
+	}
+}
+
+func TestRemoveComponentDeletesEmptiedNamespaceDirectories(t *testing.T) {
+	// Arrange
+	filesystem := repositories.NewMemoryFilesystemRepository("/project")
+	writeMemoryFile(filesystem, "/project/gotm.json", `{"name": "project"}`, t)
+	componentService := NewComponentService(filesystem, mockTemplates{})
+	if err := componentService.GenerateService("admin/reports/books", models.ComponentOptions{}); err != nil {
+		t.Fatalf("Failed to generate service: %v", err)
+	}
+
+	// Act
+	removal, err := componentService.PlanRemoval("service", "admin/reports/books")
+	if err != nil {
+		t.Fatalf("Failed to plan removal: %v", err)
+	}
+	err = componentService.RemoveComponent(removal, false)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Failed to remove service: %v", err)
+	}
+	if hasDir, _ := filesystem.HasDirectoryOrFile("/project/services/admin"); hasDir {
+		t.Fatal("Expected services/admin to be deleted")
+	}
+	if hasDir, _ := filesystem.HasDirectoryOrFile("/project/services"); !hasDir {
+		t.Fatal("Expected services to be kept")
+	}
+}
@@ -0,0 +1,231 @@
+package services
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/danielronalds/gotm/models"
+)
+
+// Maps the types accepted in resource fields to their Go and TypeScript types
+var resourceFieldTypes = map[string]struct{ goType, tsType string }{
+	"string":  {"string", "string"},
+	"int":     {"int", "number"},
+	"int64":   {"int64", "number"},
+	"float":   {"float64", "number"},
+	"float64": {"float64", "number"},
+	"bool":    {"bool", "boolean"},
+}
+
+// Data passed to the resource templates
+type resourceTemplateData struct {
+	// Forms of the resource's name and the package each file is declared in. The route path is that
+	// of the plural, i.e. /admin/books
+	componentTemplateData
+	// Forms of the plural of the name, i.e. books
+	Plural identifier
+	// Import of the Go models package, named models when the resource is nested
+	ModelsImport string
+	// Import path of the TypeScript model from the pages, i.e. ../../models/book
+	PageModelImport string
+	Fields          []resourceField
+}
+
+// A field of the resource, along with its type in Go and TypeScript
+type resourceField struct {
+	// Name of the struct field, i.e. PublishedAt
+	GoName string
+	// Name of the field in JSON and TypeScript, i.e. publishedAt
+	JSONName string
+	GoType   string
+	TSType   string
+}
+
+// A file of the resource, along with the component directory it's placed in and its template
+type resourceFile struct {
+	componentType string
+	filename      string
+	template      string
+}
+
+// Generates a full stack CRUD resource: a Go model, repository, service and controller wired
+// together through interfaces, a TypeScript model calling the controller's endpoints, and list and
+// detail pages. The controller is registered in main.go and the pages in the router unless the
+// options say otherwise
+func (s ComponentService) GenerateResource(name string, options models.ComponentOptions) error {
+	manifest, err := s.filesystem.Manifest()
+	if err != nil {
+		return fmt.Errorf("unable to read project manifest: %v", err.Error())
+	}
+
+	modulePath, err := s.modulePath(manifest)
+	if err != nil {
+		return err
+	}
+
+	componentName := parseComponentName(name)
+	data, err := newResourceTemplateData(componentName, options.Fields)
+	if err != nil {
+		return err
+	}
+
+	data.ModelsImport = strconv.Quote(path.Join(modulePath, filepath.ToSlash(manifest.Directories.GoModels), filepath.ToSlash(componentName.dir(true))))
+	if len(componentName.namespace) != 0 {
+		data.ModelsImport = "models " + data.ModelsImport
+	}
+
+	modelFile := componentFilename("model", componentName)
+	pageModelImport, err := filepath.Rel(
+		filepath.Join(manifest.Directories.Pages, componentName.dir(false)),
+		filepath.Join(manifest.Directories.Models, strings.TrimSuffix(modelFile, filepath.Ext(modelFile))),
+	)
+	if err != nil {
+		return fmt.Errorf("unable to work out the import of the model from the pages: %v", err.Error())
+	}
+	data.PageModelImport = filepath.ToSlash(pageModelImport)
+	if !strings.HasPrefix(data.PageModelImport, ".") {
+		data.PageModelImport = "./" + data.PageModelImport
+	}
+
+	files := resourceFiles(componentName)
+
+	// Checking every file up front so a resource is never left half generated
+	for _, file := range files {
+		filename, err := s.componentPath(file.componentType, file.filename)
+		if err != nil {
+			return err
+		}
+		hasFile, err := s.filesystem.HasDirectoryOrFile(filename)
+		if err != nil {
+			return fmt.Errorf("unable to check if %v exists: %v", file.filename, err.Error())
+		}
+		if hasFile {
+			dir, _ := manifest.Directories.ForComponent(file.componentType)
+			return fmt.Errorf("%v already exists", filepath.Join(dir, file.filename))
+		}
+	}
+
+	var routerFile, router string
+	if !options.NoRegister {
+		qualified := componentName.qualified().Name
+		listRoute := pageRoute{fmt.Sprintf("%vListPage", qualified), files[5].filename, data.RoutePath}
+		detailRoute := pageRoute{fmt.Sprintf("%vDetailPage", qualified), files[6].filename, fmt.Sprintf("%v/:id", data.RoutePath)}
+
+		if routerFile, router, err = s.routerWithPages(listRoute, detailRoute); err != nil {
+			return err
+		}
+	}
+
+	for _, file := range files {
+		data.Package = newComponentTemplateData(file.componentType, componentName).Package
+		if err := s.renderComponent(file.componentType, file.filename, file.template, data); err != nil {
+			return err
+		}
+	}
+
+	if options.NoRegister {
+		return nil
+	}
+
+	if err := s.filesystem.WriteFile(routerFile, router); err != nil {
+		return fmt.Errorf("unable to write router: %v", err.Error())
+	}
+
+	controllerImport, controllerNames := packageImport(modulePath, manifest.Directories.Controllers, "controller", componentName)
+	serviceImport, serviceNames := packageImport(modulePath, manifest.Directories.Services, "service", componentName)
+	repositoryImport, repositoryNames := packageImport(modulePath, manifest.Directories.Repositories, "repository", componentName)
+
+	return s.registerController(constructorCall{
+		importPath:  controllerImport,
+		importNames: controllerNames,
+		function:    fmt.Sprintf("New%vController", data.Name),
+		args: []constructorCall{{
+			importPath:  serviceImport,
+			importNames: serviceNames,
+			function:    fmt.Sprintf("New%vService", data.Name),
+			args: []constructorCall{{
+				importPath:  repositoryImport,
+				importNames: repositoryNames,
+				function:    fmt.Sprintf("New%vRepository", data.Name),
+			}},
+		}},
+	})
+}
+
+// Returns the files generated for the resource, with the list and detail pages last
+func resourceFiles(name componentName) []resourceFile {
+	pagesDir := name.dir(false)
+
+	return []resourceFile{
+		{"gomodel", componentFilename("gomodel", name), "resource_model.go.tmpl"},
+		{"repository", componentFilename("repository", name), "resource_repository.go.tmpl"},
+		{"service", componentFilename("service", name), "resource_service.go.tmpl"},
+		{"controller", componentFilename("controller", name), "resource_controller.go.tmpl"},
+		{"model", componentFilename("model", name), "resource_model.ts.tmpl"},
+		{"page", filepath.Join(pagesDir, fmt.Sprintf("%vListPage.ts", name.Name)), "resource_list_page.ts.tmpl"},
+		{"page", filepath.Join(pagesDir, fmt.Sprintf("%vDetailPage.ts", name.Name)), "resource_detail_page.ts.tmpl"},
+	}
+}
+
+func newResourceTemplateData(name componentName, fieldArgs []string) (resourceTemplateData, error) {
+	fields := make([]resourceField, 0, len(fieldArgs))
+
+	for _, arg := range fieldArgs {
+		field, err := parseResourceField(arg)
+		if err != nil {
+			return resourceTemplateData{}, err
+		}
+		if slices.ContainsFunc(fields, func(f resourceField) bool { return f.GoName == field.GoName }) {
+			return resourceTemplateData{}, fmt.Errorf("field \"%v\" is given more than once", field.JSONName)
+		}
+		fields = append(fields, field)
+	}
+
+	plural := pluralIdentifier(name.Words)
+
+	return resourceTemplateData{
+		componentTemplateData: componentTemplateData{
+			identifier: name.identifier,
+			RoutePath:  name.routePath(plural),
+		},
+		Plural: plural,
+		Fields: fields,
+	}, nil
+}
+
+// Parses a field of the form name:type
+func parseResourceField(arg string) (resourceField, error) {
+	name, fieldType, ok := strings.Cut(arg, ":")
+	if !ok || name == "" || fieldType == "" {
+		return resourceField{}, fmt.Errorf("\"%v\" is not a valid field, expected name:type, i.e. title:string", arg)
+	}
+	if !isSQLIdentifier(name) || unicode.IsDigit(rune(name[0])) {
+		return resourceField{}, fmt.Errorf("\"%v\" is not a valid field name, field names can only contain letters, digits and '_', and can't start with a digit", name)
+	}
+	id := newIdentifier(name)
+	if id.SnakeName == "id" {
+		return resourceField{}, fmt.Errorf("\"%v\" is added to every resource and can't be used as a field name", name)
+	}
+
+	types, ok := resourceFieldTypes[strings.ToLower(fieldType)]
+	if !ok {
+		expected := make([]string, 0, len(resourceFieldTypes))
+		for accepted := range resourceFieldTypes {
+			expected = append(expected, accepted)
+		}
+		slices.Sort(expected)
+		return resourceField{}, fmt.Errorf("\"%v\" is not a supported field type, expected one of %v", fieldType, strings.Join(expected, ", "))
+	}
+
+	return resourceField{
+		GoName:   id.Name,
+		JSONName: name,
+		GoType:   types.goType,
+		TSType:   types.tsType,
+	}, nil
+}
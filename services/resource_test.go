@@ -0,0 +1,91 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/danielronalds/gotm/models"
+	"github.com/danielronalds/gotm/repositories"
+)
+
+func TestParseResourceField(t *testing.T) {
+	// Arrange
+	expected := resourceField{GoName: "PublishedAt", JSONName: "publishedAt", GoType: "int64", TSType: "number"}
+
+	// Act
+	result, err := parseResourceField("publishedAt:int64")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Failed to parse field: %v", err)
+	}
+	if result != expected {
+		t.Fatalf("Wanted %v, got %v", expected, result)
+	}
+}
+
+func TestParseResourceFieldRejectsInvalidFields(t *testing.T) {
+	// Arrange
+	inputs := []string{"title", "title:", ":string", "title:text", "id:int", "book-title:string"}
+
+	for _, input := range inputs {
+		// Act
+		_, err := parseResourceField(input)
+
+		// Assert
+		if err == nil {
+			t.Fatalf("Expected \"%v\" to be rejected", input)
+		}
+	}
+}
+
+func TestGenerateResourceCreatesEveryLayer(t *testing.T) {
+	// Arrange
+	filesystem := repositories.NewMemoryFilesystemRepository("/project")
+	writeMemoryFile(filesystem, "/project/gotm.json", `{"name": "project", "module": "example.com/project"}`, t)
+	componentService := NewComponentService(filesystem, mockTemplates{})
+
+	expectedFiles := []string{
+		"/project/models/book.go",
+		"/project/repositories/book.go",
+		"/project/services/book.go",
+		"/project/controllers/book.go",
+		"/project/frontend/src/models/book.ts",
+		"/project/frontend/src/views/pages/BookListPage.ts",
+		"/project/frontend/src/views/pages/BookDetailPage.ts",
+	}
+
+	// Act
+	err := componentService.GenerateResource("book", models.ComponentOptions{Fields: []string{"title:string", "pages:int"}, NoRegister: true})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Failed to generate resource: %v", err)
+	}
+	for _, file := range expectedFiles {
+		if hasFile, _ := filesystem.HasDirectoryOrFile(file); !hasFile {
+			t.Fatalf("Expected %v to be created", file)
+		}
+	}
+}
+
+func TestGenerateResourceDoesNotOverwriteExistingFiles(t *testing.T) {
+	// Arrange
+	filesystem := repositories.NewMemoryFilesystemRepository("/project")
+	writeMemoryFile(filesystem, "/project/gotm.json", `{"name": "project", "module": "example.com/project"}`, t)
+	if err := filesystem.CreateDirectory("/project/services"); err != nil {
+		t.Fatal(err)
+	}
+	writeMemoryFile(filesystem, "/project/services/book.go", "package services", t)
+	componentService := NewComponentService(filesystem, mockTemplates{})
+
+	// Act
+	err := componentService.GenerateResource("book", models.ComponentOptions{NoRegister: true})
+
+	// Assert
+	if err == nil {
+		t.Fatal("Expected an existing service to fail the resource")
+	}
+	if hasFile, _ := filesystem.HasDirectoryOrFile("/project/models/book.go"); hasFile {
+		t.Fatal("Expected no files to be created")
+	}
+}
@@ -0,0 +1,249 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Matches the call setting up the Mithril router, i.e. `m.route(root, "/home", {...})`
+var mithrilRouteRegex = regexp.MustCompile(`\bm\.route\s*\(`)
+
+// Matches import statements
+var tsImportRegex = regexp.MustCompile(`(?m)^import\s[^;\n]*;?[ \t]*$`)
+
+// Adds an import of the page and an entry routing to it to the route object passed to `m.route` in
+// the source of the router file
+func registerPageRoute(source, importPath, component, route string) (string, error) {
+	code := codeMask(source)
+
+	call := -1
+	for _, match := range mithrilRouteRegex.FindAllStringIndex(source, -1) {
+		if code[match[0]] {
+			call = match[1] - 1
+			break
+		}
+	}
+	if call == -1 {
+		return "", errors.New("unable to find the call to m.route")
+	}
+
+	args, err := splitArguments(source, code, call)
+	if err != nil {
+		return "", err
+	}
+	if len(args) != 3 || source[args[2][0]] != '{' {
+		return "", errors.New("expected m.route to be passed a route object as its third argument")
+	}
+	routesStart, routesEnd := args[2][0], args[2][1]-1
+	if source[routesEnd] != '}' {
+		return "", errors.New("expected m.route to be passed a route object as its third argument")
+	}
+
+	routes := source[routesStart : routesEnd+1]
+	if regexp.MustCompile(`["'` + "`" + `]` + regexp.QuoteMeta(route) + `["'` + "`" + `]\s*:`).MatchString(routes) {
+		return "", fmt.Errorf("the router already has a %v route", route)
+	}
+
+	entry := fmt.Sprintf("%v%q: %v,", entryIndent(source, routesStart, routesEnd), route, component)
+
+	// Mithril tries routes in order, so anything after a catch all route would never be reached
+	if catchAll := firstCatchAllRoute(source, code, routesStart, routesEnd); catchAll != -1 {
+		lineStart := strings.LastIndexByte(source[:catchAll], '\n') + 1
+		if strings.TrimSpace(source[lineStart:catchAll]) == "" {
+			return addTsImport(source[:lineStart]+entry+"\n"+source[lineStart:], component, importPath), nil
+		}
+		return addTsImport(source[:catchAll]+fmt.Sprintf("%q: %v, ", route, component)+source[catchAll:], component, importPath), nil
+	}
+
+	// Finding where the last entry ends, to add a comma after it if it doesn't have one
+	last := routesEnd - 1
+	for last > routesStart && strings.ContainsRune(" \t\r\n", rune(source[last])) {
+		last--
+	}
+
+	var edited string
+	switch source[last] {
+	case '{':
+		edited = source[:last+1] + "\n" + entry + "\n" + source[routesEnd:]
+	case ',':
+		edited = source[:last+1] + "\n" + entry + source[last+1:]
+	default:
+		edited = source[:last+1] + ",\n" + entry + source[last+1:]
+	}
+
+	return addTsImport(edited, component, importPath), nil
+}
+
+// Matches routes with a variadic parameter, i.e. `/:404...`, or Mithril's catch all `*` route
+var catchAllRouteRegex = regexp.MustCompile(`:[^/]*\.\.\.$|^\*$`)
+
+// Returns the offset of the key of the first entry of the route object between start and end that
+// matches any path, or -1 if none of them do
+func firstCatchAllRoute(source string, code []bool, start, end int) int {
+	depth := 0
+	expectingKey := false
+
+	for i := start; i < end; i++ {
+		if !code[i] {
+			if expectingKey && depth == 1 && strings.ContainsRune(`"'`+"`", rune(source[i])) {
+				closing := i + 1
+				for closing < end && source[closing] != source[i] {
+					if source[closing] == '\\' {
+						closing++
+					}
+					closing++
+				}
+				if catchAllRouteRegex.MatchString(source[i+1 : min(closing, end)]) {
+					return i
+				}
+				expectingKey = false
+				i = closing
+			}
+			continue
+		}
+
+		switch source[i] {
+		case '(', '[', '{':
+			depth++
+			expectingKey = depth == 1
+		case ')', ']', '}':
+			depth--
+		case ',':
+			expectingKey = depth == 1
+		case ' ', '\t', '\r', '\n':
+		default:
+			expectingKey = false
+		}
+	}
+
+	return -1
+}
+
+// Adds a default import of the component after the last import, unless it's already imported
+func addTsImport(source, component, importPath string) string {
+	if regexp.MustCompile(`import\s+` + regexp.QuoteMeta(component) + `\s+from`).MatchString(source) {
+		return source
+	}
+
+	statement := fmt.Sprintf("import %v from %q;", component, importPath)
+
+	imports := tsImportRegex.FindAllStringIndex(source, -1)
+	if len(imports) == 0 {
+		return statement + "\n" + source
+	}
+
+	end := imports[len(imports)-1][1]
+	return source[:end] + "\n" + statement + source[end:]
+}
+
+// Returns the indentation used by the entries of the object, defaulting to two spaces
+func entryIndent(source string, start, end int) string {
+	object := source[start:end]
+
+	newline := strings.IndexByte(object, '\n')
+	if newline == -1 {
+		return "  "
+	}
+
+	line := object[newline+1:]
+	indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+	if indent == "" || strings.HasPrefix(strings.TrimLeft(line, " \t"), "}") {
+		return "  "
+	}
+
+	return indent
+}
+
+// Returns the start and end offsets of each argument of the call whose opening parenthesis is at
+// open, with surrounding whitespace trimmed
+func splitArguments(source string, code []bool, open int) ([][2]int, error) {
+	args := make([][2]int, 0)
+	depth := 0
+	start := open + 1
+
+	addArg := func(end int) {
+		for start < end && strings.ContainsRune(" \t\r\n", rune(source[start])) {
+			start++
+		}
+		for end > start && strings.ContainsRune(" \t\r\n", rune(source[end-1])) {
+			end--
+		}
+		if start < end {
+			args = append(args, [2]int{start, end})
+		}
+	}
+
+	for i := open; i < len(source); i++ {
+		if !code[i] {
+			continue
+		}
+
+		switch source[i] {
+		case '(', '[', '{':
+			depth++
+		case ')', ']', '}':
+			depth--
+			if depth == 0 {
+				addArg(i)
+				return args, nil
+			}
+		case ',':
+			if depth == 1 {
+				addArg(i)
+				start = i + 1
+			}
+		}
+	}
+
+	return nil, errors.New("unable to find the end of the call to m.route")
+}
+
+// Marks each byte of the source that is code, rather than part of a string or comment
+func codeMask(source string) []bool {
+	code := make([]bool, len(source))
+
+	for i := 0; i < len(source); i++ {
+		switch {
+		case strings.HasPrefix(source[i:], "//"):
+			for i < len(source) && source[i] != '\n' {
+				i++
+			}
+		case strings.HasPrefix(source[i:], "/*"):
+			end := strings.Index(source[i+2:], "*/")
+			if end == -1 {
+				return code
+			}
+			i += end + 3
+		case source[i] == '"' || source[i] == '\'' || source[i] == '`':
+			quote := source[i]
+			for i++; i < len(source) && source[i] != quote; i++ {
+				if source[i] == '\\' {
+					i++
+				}
+			}
+		default:
+			code[i] = true
+		}
+	}
+
+	return code
+}
+
+// Removes the entries routing to the page, along with its import, from the source of the router
+// file. Returns whether the page was routed to, the source is returned unchanged if it wasn't
+func unregisterPageRoute(source, component string) (string, bool) {
+	quoted := `["'` + "`" + `][^"'` + "`" + `\n]*["'` + "`" + `]`
+	entryRegex := regexp.MustCompile(`(?m)^[ \t]*` + quoted + `\s*:\s*` + regexp.QuoteMeta(component) + `[ \t]*,?[ \t]*(\r?\n|$)`)
+	importRegex := regexp.MustCompile(`(?m)^import\s+` + regexp.QuoteMeta(component) + `\s+from\s[^;\n]*;?[ \t]*(\r?\n|$)`)
+
+	if !entryRegex.MatchString(source) {
+		return source, false
+	}
+
+	edited := entryRegex.ReplaceAllString(source, "")
+	edited = importRegex.ReplaceAllString(edited, "")
+
+	return edited, true
+}
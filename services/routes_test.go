@@ -0,0 +1,165 @@
+package services
+
+import (
+	"strings"
+	"testing"
+)
+
+const testRouterFile = `import m from "mithril";
+import HomePage from "./views/pages/HomePage";
+
+// Routes of the app
+m.route(document.getElementById("app"), "/home", {
+  "/home": HomePage,
+});
+`
+
+func TestRegisterPageRouteAddsImportAndRoute(t *testing.T) {
+	// Arrange
+	expected := `import m from "mithril";
+import HomePage from "./views/pages/HomePage";
+import SettingsPage from "./views/pages/SettingsPage";
+
+// Routes of the app
+m.route(document.getElementById("app"), "/home", {
+  "/home": HomePage,
+  "/settings": SettingsPage,
+});
+`
+
+	// Act
+	result, err := registerPageRoute(testRouterFile, "./views/pages/SettingsPage", "SettingsPage", "/settings")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Failed to register route: %v", err)
+	}
+	if result != expected {
+		t.Fatalf("Wanted:\n%v\ngot:\n%v", expected, result)
+	}
+}
+
+func TestRegisterPageRouteAddsCommaToLastEntry(t *testing.T) {
+	// Arrange
+	source := "import m from \"mithril\";\n\nm.route(document.body, \"/\", {\n  \"/\": HomePage\n});\n"
+
+	// Act
+	result, err := registerPageRoute(source, "./views/pages/BookPage", "BookPage", "/books/:id")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Failed to register route: %v", err)
+	}
+	if !strings.Contains(result, "\"/\": HomePage,\n  \"/books/:id\": BookPage,\n});") {
+		t.Fatalf("Expected route to be appended after a comma, got:\n%v", result)
+	}
+}
+
+func TestRegisterPageRouteAddsRouteBeforeCatchAllRoute(t *testing.T) {
+	// Arrange
+	source := `import m from "mithril";
+import HomePage from "./views/pages/HomePage";
+import NotFoundPage from "./views/pages/NotFoundPage";
+
+m.route(document.getElementById("app"), "/home", {
+  "/home": HomePage,
+  "/:404...": NotFoundPage,
+});
+`
+	expected := `import m from "mithril";
+import HomePage from "./views/pages/HomePage";
+import NotFoundPage from "./views/pages/NotFoundPage";
+import SettingsPage from "./views/pages/SettingsPage";
+
+m.route(document.getElementById("app"), "/home", {
+  "/home": HomePage,
+  "/settings": SettingsPage,
+  "/:404...": NotFoundPage,
+});
+`
+
+	// Act
+	result, err := registerPageRoute(source, "./views/pages/SettingsPage", "SettingsPage", "/settings")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Failed to register route: %v", err)
+	}
+	if result != expected {
+		t.Fatalf("Wanted:\n%v\ngot:\n%v", expected, result)
+	}
+}
+
+func TestRegisterPageRouteAddsRouteBeforeInlineCatchAllRoute(t *testing.T) {
+	// Arrange
+	source := "import m from \"mithril\";\n\nm.route(document.body, \"/\", { \"/\": HomePage, \"/books/:path...\": BooksPage });\n"
+
+	// Act
+	result, err := registerPageRoute(source, "./views/pages/AboutPage", "AboutPage", "/about")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Failed to register route: %v", err)
+	}
+	if !strings.Contains(result, `{ "/": HomePage, "/about": AboutPage, "/books/:path...": BooksPage }`) {
+		t.Fatalf("Expected route to be added before the variadic route, got:\n%v", result)
+	}
+}
+
+func TestRegisterPageRouteRejectsExistingRoute(t *testing.T) {
+	// Act
+	_, err := registerPageRoute(testRouterFile, "./views/pages/OtherHomePage", "OtherHomePage", "/home")
+
+	// Assert
+	if err == nil {
+		t.Fatal("Expected an existing route to be rejected")
+	}
+}
+
+func TestRegisterPageRouteRejectsUnrecognisedRouter(t *testing.T) {
+	// Arrange
+	source := "import m from \"mithril\";\nimport routes from \"./routes\";\n\nm.route(document.body, \"/\", routes);\n"
+
+	// Act
+	_, err := registerPageRoute(source, "./views/pages/SettingsPage", "SettingsPage", "/settings")
+
+	// Assert
+	if err == nil {
+		t.Fatal("Expected a restructured router to be rejected")
+	}
+}
+
+func TestRegisterPageRouteIgnoresCommentedOutRouter(t *testing.T) {
+	// Arrange
+	source := "// m.route(document.body, \"/\", {});\nm.route(document.body, \"/\", {\n  \"/\": HomePage,\n});\n"
+
+	// Act
+	result, err := registerPageRoute(source, "./views/pages/SettingsPage", "SettingsPage", "/settings")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Failed to register route: %v", err)
+	}
+	if !strings.HasPrefix(result, "import SettingsPage") || !strings.Contains(result, "\"/settings\": SettingsPage,\n});") {
+		t.Fatalf("Expected route to be added to the real router, got:\n%v", result)
+	}
+}
+
+func TestUnregisterPageRouteRemovesImportAndRoute(t *testing.T) {
+	// Arrange
+	source, err := registerPageRoute(testRouterFile, "./views/pages/SettingsPage", "SettingsPage", "/settings")
+	if err != nil {
+		t.Fatalf("Failed to register route: %v", err)
+	}
+
+	// Act
+	result, routed := unregisterPageRoute(source, "SettingsPage")
+
+	// Assert
+	if !routed {
+		t.Fatal("Expected the page to be found")
+	}
+	if result != testRouterFile {
+		t.Fatalf("Wanted:\n%v\ngot:\n%v", testRouterFile, result)
+	}
+}
@@ -8,6 +8,7 @@ import (
 
 type RunnerServiceFilesystem interface {
 	ProjectRoot
+	ProjectManifest
 }
 
 type RunnerService struct {
@@ -27,7 +28,17 @@ func (s *RunnerService) Run() error {
 		}
 	}
 
-	cmd := exec.Command("sh", "-c", s.filesystem.FromRoot("/.main.tmp"))
+	manifest, err := s.filesystem.Manifest()
+	if err != nil {
+		return fmt.Errorf("unable to read project manifest: %v", err)
+	}
+
+	devBinary, err := s.filesystem.FromRoot(manifest.DevBinary)
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.Command("sh", "-c", devBinary)
 	cmd.Stdout = os.Stdout
 	cmd.Stdin = os.Stdin
 	cmd.Stderr = os.Stderr
@@ -0,0 +1,355 @@
+package services
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/danielronalds/gotm/models"
+)
+
+// Maps the types accepted in table fields to their sqlite column types
+var sqliteColumnTypes = map[string]string{
+	"text":    "TEXT",
+	"string":  "TEXT",
+	"int":     "INTEGER",
+	"int64":   "INTEGER",
+	"integer": "INTEGER",
+	"bool":    "BOOLEAN",
+	"float":   "REAL",
+	"float64": "REAL",
+	"real":    "REAL",
+	"blob":    "BLOB",
+	"bytes":   "BLOB",
+}
+
+// Blob literals, i.e. x'00ff', the only form a blob default can take
+var blobLiteralRegex = regexp.MustCompile(`^[xX]'([0-9a-fA-F]{2})*'$`)
+
+// Columns added to every generated table, which can't be used as field names
+var standardColumns = []string{"id", "createdAt", "updatedAt"}
+
+type TableServiceFilesystem interface {
+	ProjectRoot
+	ProjectManifest
+	FileCreater
+	DirCreater
+	DirReader
+}
+
+// Service for generating database tables, along with the queries and code sqlc generates for them
+type TableService struct {
+	filesystem TableServiceFilesystem
+	templates  TemplatesWriter
+	shell      CmdRunner
+}
+
+func NewTableService(filesystem TableServiceFilesystem, templates TemplatesWriter, shell CmdRunner) TableService {
+	return TableService{filesystem, templates, shell}
+}
+
+// Generates a migration creating the table and a file of CRUD queries for it, creating sqlc.yml if
+// the project doesn't have one. When generateCode is set `sqlc generate` is run afterwards
+func (s TableService) GenerateTable(name string, fieldArgs []string, generateCode bool) error {
+	manifest, err := s.filesystem.Manifest()
+	if err != nil {
+		return fmt.Errorf("unable to read project manifest: %v", err)
+	}
+	if manifest.Database == nil {
+		manifest.Database = models.DefaultDatabase()
+	}
+	if manifest.Database.Engine != "sqlite" {
+		return fmt.Errorf("generating tables is only supported for sqlite databases, not %v", manifest.Database.Engine)
+	}
+
+	fields := make([]models.TableField, 0, len(fieldArgs))
+	for _, arg := range fieldArgs {
+		field, err := parseTableField(arg)
+		if err != nil {
+			return err
+		}
+		if slices.ContainsFunc(fields, func(f models.TableField) bool { return strings.EqualFold(f.Name, field.Name) }) {
+			return fmt.Errorf("field \"%v\" is given more than once", field.Name)
+		}
+		fields = append(fields, field)
+	}
+
+	data, err := newTableTemplateData(name, fields)
+	if err != nil {
+		return err
+	}
+
+	migrationsDir, err := s.componentDir(manifest.Directories.Migrations)
+	if err != nil {
+		return err
+	}
+	queriesDir, err := s.componentDir(manifest.Directories.Queries)
+	if err != nil {
+		return err
+	}
+
+	if err := s.checkTableDoesNotExist(data.SnakeName, migrationsDir, queriesDir); err != nil {
+		return err
+	}
+
+	// Generating timestamp that matches how goose generates timestamps
+	timestamp := time.Now().UTC().Format("20060102150405")
+	migration := filepath.Join(migrationsDir, fmt.Sprintf("%v_create_%v_table.sql", timestamp, data.SnakeName))
+	if err := s.renderFile(migration, "table.sql.tmpl", data); err != nil {
+		return err
+	}
+
+	if err := s.renderFile(filepath.Join(queriesDir, fmt.Sprintf("%v.sql", data.SnakeName)), "queries.sql.tmpl", data); err != nil {
+		return err
+	}
+
+	if err := s.ensureSqlcConfig(manifest); err != nil {
+		return err
+	}
+
+	if !generateCode {
+		return nil
+	}
+
+	root, err := s.filesystem.Root()
+	if err != nil {
+		return err
+	}
+	if err := s.shell.RunCmdWithPipedOutput(root, "sqlc", "generate"); err != nil {
+		return fmt.Errorf("table files were created but `sqlc generate` failed, fix the error and run it again: %v", err)
+	}
+
+	return nil
+}
+
+// Returns the absolute path of the given component directory, creating it if it doesn't exist
+func (s TableService) componentDir(dir string) (string, error) {
+	componentDir, err := s.filesystem.FromRoot(dir)
+	if err != nil {
+		return "", err
+	}
+
+	hasDir, err := s.filesystem.HasDirectoryOrFile(componentDir)
+	if err != nil {
+		return "", fmt.Errorf("unable to check if %v directory exists: %v", componentDir, err)
+	}
+	if !hasDir {
+		if err := s.filesystem.CreateDirectory(componentDir); err != nil {
+			return "", fmt.Errorf("unable to create %v directory: %v", componentDir, err)
+		}
+	}
+
+	return componentDir, nil
+}
+
+// Checks that neither a migration creating the table nor its queries have already been generated
+func (s TableService) checkTableDoesNotExist(name, migrationsDir, queriesDir string) error {
+	hasQueries, err := s.filesystem.HasDirectoryOrFile(filepath.Join(queriesDir, fmt.Sprintf("%v.sql", name)))
+	if err != nil {
+		return fmt.Errorf("unable to check if table already exists: %v", err)
+	}
+	if hasQueries {
+		return fmt.Errorf("queries for a %v table already exist", name)
+	}
+
+	migrations, err := s.filesystem.ReadDirRecursive(migrationsDir)
+	if err != nil {
+		return fmt.Errorf("unable to read migrations: %v", err)
+	}
+	for _, migration := range migrations {
+		if strings.HasSuffix(filepath.Base(migration), fmt.Sprintf("_create_%v_table.sql", name)) {
+			return fmt.Errorf("a migration creating the %v table already exists", name)
+		}
+	}
+
+	return nil
+}
+
+// Creates sqlc.yml in the project root if it's missing
+func (s TableService) ensureSqlcConfig(manifest models.Manifest) error {
+	config, err := s.filesystem.FromRoot("sqlc.yml")
+	if err != nil {
+		return err
+	}
+
+	for _, filename := range []string{"sqlc.yml", "sqlc.yaml", "sqlc.json"} {
+		existing, err := s.filesystem.FromRoot(filename)
+		if err != nil {
+			return err
+		}
+		hasConfig, err := s.filesystem.HasDirectoryOrFile(existing)
+		if err != nil {
+			return fmt.Errorf("unable to check if %v exists: %v", filename, err)
+		}
+		if hasConfig {
+			return nil
+		}
+	}
+
+	return s.renderFile(config, "sqlc.yml.tmpl", newProjectTemplateConfig(manifest))
+}
+
+func (s TableService) renderFile(filename, template string, data any) error {
+	file, err := s.filesystem.CreateFile(filename)
+	if err != nil {
+		return fmt.Errorf("unable to create %v: %v", filepath.Base(filename), err)
+	}
+	defer file.Close()
+
+	if err := s.templates.WriteTemplate(file, template, data); err != nil {
+		return fmt.Errorf("unable to write template: %v", err)
+	}
+
+	return nil
+}
+
+// Data passed to the table and queries templates
+type tableTemplateData struct {
+	// Forms of the table's name. The table itself is named in snake case, while queries use the
+	// type name, i.e. CreateBook
+	identifier
+	// Forms of the plural of the name, used for the list query
+	Plural  identifier
+	Columns []tableColumn
+}
+
+// A column defined by one of the table's fields
+type tableColumn struct {
+	Name string
+	// The column's definition in a CREATE TABLE statement
+	Definition string
+}
+
+func newTableTemplateData(name string, fields []models.TableField) (tableTemplateData, error) {
+	columns := make([]tableColumn, 0, len(fields))
+
+	for _, field := range fields {
+		columnType, ok := sqliteColumnTypes[strings.ToLower(field.Type)]
+		if !ok {
+			return tableTemplateData{}, fmt.Errorf("\"%v\" is not a supported field type, expected one of %v", field.Type, strings.Join(sortedKeys(sqliteColumnTypes), ", "))
+		}
+
+		definition := []string{field.Name, columnType}
+		if field.NotNull {
+			definition = append(definition, "NOT NULL")
+		}
+		if field.Unique {
+			definition = append(definition, "UNIQUE")
+		}
+		if field.Default != "" {
+			definition = append(definition, "DEFAULT", sqlDefault(field.Default, columnType))
+		}
+		if field.References != "" {
+			definition = append(definition, fmt.Sprintf("REFERENCES %v(id)", field.References))
+		}
+
+		columns = append(columns, tableColumn{field.Name, strings.Join(definition, " ")})
+	}
+
+	return tableTemplateData{
+		identifier: newIdentifier(name),
+		Plural:     pluralIdentifier(name),
+		Columns:    columns,
+	}, nil
+}
+
+// Parses a field of the form name=type[:notnull][:unique][:default=value][:references=table]
+func parseTableField(arg string) (models.TableField, error) {
+	name, definition, ok := strings.Cut(arg, "=")
+	if !ok || name == "" || definition == "" {
+		return models.TableField{}, fmt.Errorf("\"%v\" is not a valid field, expected name=type, i.e. title=text:notnull", arg)
+	}
+	if !isSQLIdentifier(name) {
+		return models.TableField{}, fmt.Errorf("\"%v\" is not a valid field name, field names can only contain letters, digits and '_'", name)
+	}
+	if slices.ContainsFunc(standardColumns, func(column string) bool { return strings.EqualFold(column, name) }) {
+		return models.TableField{}, fmt.Errorf("\"%v\" is added to every table and can't be used as a field name", name)
+	}
+
+	modifiers := strings.Split(definition, ":")
+	field := models.TableField{Name: name, Type: modifiers[0]}
+
+	for _, modifier := range modifiers[1:] {
+		key, value, _ := strings.Cut(modifier, "=")
+
+		switch strings.ToLower(key) {
+		case "notnull":
+			field.NotNull = true
+		case "unique":
+			field.Unique = true
+		case "default":
+			if value == "" {
+				return models.TableField{}, fmt.Errorf("field \"%v\" is missing a value for default, i.e. default=0", name)
+			}
+			field.Default = value
+		case "references":
+			if !isSQLIdentifier(value) {
+				return models.TableField{}, fmt.Errorf("field \"%v\" has an invalid table for references, i.e. references=author", name)
+			}
+			// Tables are named in snake case, so references=BookAuthor refers to book_author
+			field.References = newIdentifier(value).SnakeName
+		default:
+			return models.TableField{}, fmt.Errorf("\"%v\" is not a valid field modifier, expected notnull, unique, default=<value> or references=<table>", modifier)
+		}
+	}
+
+	if field.Default != "" {
+		if err := validateSQLDefault(field.Default, sqliteColumnTypes[strings.ToLower(field.Type)]); err != nil {
+			return models.TableField{}, fmt.Errorf("field \"%v\" has an invalid default: %v", name, err)
+		}
+	}
+
+	return field, nil
+}
+
+// Checks the default is a literal of the column type, as anything else would only fail when the
+// migration is run. Text defaults are quoted, so any value is valid
+func validateSQLDefault(value, columnType string) error {
+	switch columnType {
+	case "INTEGER":
+		if _, err := strconv.ParseInt(value, 10, 64); err != nil {
+			return fmt.Errorf("\"%v\" is not an integer", value)
+		}
+	case "REAL":
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return fmt.Errorf("\"%v\" is not a number", value)
+		}
+	case "BOOLEAN":
+		if !slices.Contains([]string{"0", "1", "true", "false"}, strings.ToLower(value)) {
+			return fmt.Errorf("\"%v\" is not a boolean, expected 0, 1, true or false", value)
+		}
+	case "BLOB":
+		if !blobLiteralRegex.MatchString(value) {
+			return fmt.Errorf("\"%v\" is not a blob, expected a hex literal i.e. x'00ff'", value)
+		}
+	}
+
+	return nil
+}
+
+// Returns the default as an SQL expression, quoting text values that aren't already quoted
+func sqlDefault(value, columnType string) string {
+	if columnType != "TEXT" || strings.HasPrefix(value, "'") {
+		return value
+	}
+	if _, err := strconv.ParseFloat(value, 64); err == nil {
+		return value
+	}
+
+	return fmt.Sprintf("'%v'", strings.ReplaceAll(value, "'", "''"))
+}
+
+func isSQLIdentifier(name string) bool {
+	if name == "" || unicode.IsDigit([]rune(name)[0]) {
+		return false
+	}
+
+	return strings.IndexFunc(name, func(r rune) bool {
+		return !(r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9'))
+	}) == -1
+}
@@ -0,0 +1,158 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/danielronalds/gotm/models"
+	"github.com/danielronalds/gotm/repositories"
+)
+
+// Mock implementation of the shell, recording the commands run
+type mockShell struct {
+	commands *[]string
+}
+
+func (m mockShell) RunCmdWithPipedOutput(dir, program string, args ...string) error {
+	*m.commands = append(*m.commands, strings.Join(append([]string{program}, args...), " "))
+	return nil
+}
+
+func TestGenerateTableCreatesFilesAndRunsSqlc(t *testing.T) {
+	// Arrange
+	filesystem := repositories.NewMemoryFilesystemRepository("/project")
+	writeMemoryFile(filesystem, "/project/gotm.json", `{"name": "project"}`, t)
+	commands := make([]string, 0)
+	tableService := NewTableService(filesystem, mockTemplates{}, mockShell{&commands})
+
+	// Act
+	if err := tableService.GenerateTable("book", []string{"title=text:notnull"}, true); err != nil {
+		t.Fatalf("Failed to generate table: %v", err)
+	}
+
+	// Assert
+	for _, file := range []string{"/project/queries/book.sql", "/project/sqlc.yml"} {
+		if hasFile, _ := filesystem.HasDirectoryOrFile(file); !hasFile {
+			t.Fatalf("Expected %v to be created", file)
+		}
+	}
+	migrations, _ := filesystem.ReadDirRecursive("/project/migrations")
+	if len(migrations) != 1 || !strings.HasSuffix(migrations[0], "_create_book_table.sql") {
+		t.Fatalf("Expected a migration creating the table, got %v", migrations)
+	}
+	if len(commands) != 1 || commands[0] != "sqlc generate" {
+		t.Fatalf("Wanted [sqlc generate], got %v", commands)
+	}
+}
+
+func TestGenerateTableRejectsExistingTable(t *testing.T) {
+	// Arrange
+	filesystem := repositories.NewMemoryFilesystemRepository("/project")
+	writeMemoryFile(filesystem, "/project/gotm.json", `{"name": "project"}`, t)
+	if err := filesystem.CreateDirectory("/project/migrations"); err != nil {
+		t.Fatalf("Failed to create migrations directory: %v", err)
+	}
+	writeMemoryFile(filesystem, "/project/migrations/20250101000000_create_book_table.sql", "", t)
+	commands := make([]string, 0)
+	tableService := NewTableService(filesystem, mockTemplates{}, mockShell{&commands})
+
+	// Act
+	err := tableService.GenerateTable("book", nil, true)
+
+	// Assert
+	if err == nil {
+		t.Fatal("Expected generating an existing table to fail")
+	}
+	if len(commands) != 0 {
+		t.Fatalf("Expected sqlc not to be run, got %v", commands)
+	}
+}
+
+func TestParseTableFieldWithModifiers(t *testing.T) {
+	// Arrange
+	arg := "authorId=int64:notnull:unique:default=1:references=author"
+
+	// Act
+	field, err := parseTableField(arg)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Failed to parse field: %v", err)
+	}
+	if field.Name != "authorId" || field.Type != "int64" || !field.NotNull || !field.Unique || field.Default != "1" || field.References != "author" {
+		t.Fatalf("Parsed field incorrectly, got %+v", field)
+	}
+}
+
+func TestParseTableFieldRejectsStandardColumns(t *testing.T) {
+	// Arrange
+	arg := "createdAt=int64"
+
+	// Act
+	_, err := parseTableField(arg)
+
+	// Assert
+	if err == nil {
+		t.Fatal("Expected createdAt to be rejected")
+	}
+}
+
+func TestTableColumnDefinitionQuotesTextDefaults(t *testing.T) {
+	// Arrange
+	field, _ := parseTableField("author=text:notnull:default=Anon")
+	expected := "author TEXT NOT NULL DEFAULT 'Anon'"
+
+	// Act
+	data, err := newTableTemplateData("book", []models.TableField{field})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Failed to build template data: %v", err)
+	}
+	if data.Columns[0].Definition != expected {
+		t.Fatalf("Wanted %v, got %v", expected, data.Columns[0].Definition)
+	}
+}
+
+func TestParseTableFieldRejectsDefaultsOfTheWrongType(t *testing.T) {
+	// Arrange
+	inputs := []string{"count=int:default=abc", "count=int64:default=1.5", "price=float:default=free", "active=bool:default=yes", "data=blob:default=abc"}
+
+	for _, arg := range inputs {
+		// Act
+		_, err := parseTableField(arg)
+
+		// Assert
+		if err == nil {
+			t.Fatalf("Expected %v to be rejected", arg)
+		}
+	}
+}
+
+func TestParseTableFieldAcceptsDefaultsOfTheColumnType(t *testing.T) {
+	// Arrange
+	inputs := []string{"count=int:default=-3", "price=float:default=2.5", "active=bool:default=TRUE", "active=bool:default=0", "data=blob:default=x'00ff'", "title=text:default=abc"}
+
+	for _, arg := range inputs {
+		// Act
+		_, err := parseTableField(arg)
+
+		// Assert
+		if err != nil {
+			t.Fatalf("Expected %v to be accepted, got %v", arg, err)
+		}
+	}
+}
+
+func TestParseTableFieldReferencesSnakeCaseTable(t *testing.T) {
+	// Act
+	field, err := parseTableField("authorId=int64:references=BookAuthor")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Failed to parse field: %v", err)
+	}
+	if field.References != "book_author" {
+		t.Fatalf("Wanted book_author, got %v", field.References)
+	}
+}
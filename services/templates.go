@@ -0,0 +1,86 @@
+package services
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/danielronalds/gotm/models"
+)
+
+type TemplatesRepository interface {
+	ListTemplates() ([]models.TemplateInfo, error)
+	TemplateSource(name string) (models.TemplateInfo, string, error)
+	EmbeddedTemplateSource(name string) (string, error)
+	TemplateOverrideDir(source models.TemplateSource) (string, error)
+}
+
+type TemplatesServiceFilesystem interface {
+	DirCreater
+	DirReader
+	FileCreater
+}
+
+// Service for inspecting and overriding the templates components are generated from
+type TemplatesService struct {
+	filesystem TemplatesServiceFilesystem
+	templates  TemplatesRepository
+}
+
+func NewTemplatesService(filesystem TemplatesServiceFilesystem, templates TemplatesRepository) TemplatesService {
+	return TemplatesService{filesystem, templates}
+}
+
+func (s TemplatesService) ListTemplates() ([]models.TemplateInfo, error) {
+	return s.templates.ListTemplates()
+}
+
+// Returns the source of the template that will be used when generating with the given name
+func (s TemplatesService) ShowTemplate(name string) (models.TemplateInfo, string, error) {
+	return s.templates.TemplateSource(name)
+}
+
+// Copies the built in template with the given name into the project (or user) override
+// directory, returning the path of the copy
+func (s TemplatesService) EjectTemplate(name string, source models.TemplateSource) (string, error) {
+	contents, err := s.templates.EmbeddedTemplateSource(name)
+	if err != nil {
+		return "", err
+	}
+
+	dir, err := s.templates.TemplateOverrideDir(source)
+	if err != nil {
+		return "", fmt.Errorf("unable to find %v template directory: %v", source, err)
+	}
+
+	hasDir, err := s.filesystem.HasDirectoryOrFile(dir)
+	if err != nil {
+		return "", fmt.Errorf("unable to check if %v exists: %v", dir, err)
+	}
+	if !hasDir {
+		if err := s.filesystem.CreateDirectory(dir); err != nil {
+			return "", fmt.Errorf("unable to create %v: %v", dir, err)
+		}
+	}
+
+	templatePath := filepath.Join(dir, name)
+
+	hasFile, err := s.filesystem.HasDirectoryOrFile(templatePath)
+	if err != nil {
+		return "", fmt.Errorf("unable to check if %v exists: %v", templatePath, err)
+	}
+	if hasFile {
+		return "", fmt.Errorf("%v has already been ejected to %v", name, templatePath)
+	}
+
+	file, err := s.filesystem.CreateFile(templatePath)
+	if err != nil {
+		return "", fmt.Errorf("unable to create %v: %v", templatePath, err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write([]byte(contents)); err != nil {
+		return "", fmt.Errorf("unable to write %v: %v", templatePath, err)
+	}
+
+	return templatePath, nil
+}
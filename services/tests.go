@@ -0,0 +1,824 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/danielronalds/gotm/models"
+)
+
+// Components that tests can be generated for, along with the template of their tests
+var testTemplates = map[string]string{
+	"controller": "controller_test.go.tmpl",
+	"service":    "component_test.go.tmpl",
+	"repository": "component_test.go.tmpl",
+}
+
+// Imports the generated tests of a controller's routes need
+var routeTestImports = []string{"net/http", "net/http/httptest", "strings"}
+
+// What each type of component needs for a test to be generated for it
+var testNeeds = map[string]string{
+	"controller": "routes whose handlers are methods or functions in its file, or interfaces it depends on with methods",
+	"service":    "exported methods returning an error, or interfaces it depends on with methods",
+	"repository": "interfaces it depends on with methods, as its methods depend on state gotm can't work out",
+}
+
+// Methods writing a request body, which the generated route tests send an empty JSON object to
+var bodyMethods = []string{"POST", "PUT", "PATCH"}
+
+// Data passed to the templates of generated tests
+type componentTestData struct {
+	componentTemplateData
+	// Type of the component, i.e. BookController
+	Type string
+	// Variable the component is assigned to in the tests, i.e. controller
+	Variable string
+	// Call to the component's constructor, passing fakes for the interfaces it depends on
+	Constructor string
+	// Call to the component's constructor, passing the variables of the stored fakes instead
+	StoredConstructor string
+	// Import specs of the test file, i.e. "net/http" or models "example.com/app/models/admin"
+	Imports []string
+	Fakes   []testFake
+	// Fakes the constructor stores in the component's fields, which the constructor's test checks
+	Stored []testStored
+	// Routes registered in RegisterRoutes whose responses are known, only found for controllers
+	Routes []testRoute
+	// Exported methods of the component returning an error, only tested for services
+	Methods []testMethod
+}
+
+// Returns whether the test of the component has anything to check
+func (d componentTestData) hasTests() bool {
+	return len(d.Stored) != 0 || len(d.Routes) != 0 || len(d.Methods) != 0
+}
+
+// Implementation of an interface the component depends on. Each method calls its function field if
+// it's set, returning zero values otherwise
+type testFake struct {
+	Name      string
+	Interface string
+	// Function fields of the fake, i.e. GetFunc func(id int) (models.Book, error)
+	Fields []string
+	// Declarations of the fake's methods
+	Declarations []string
+	Methods      []testFakeMethod
+}
+
+type testFakeMethod struct {
+	Name string
+	// Function literal's signature that can be set as the method's field, with its results named so
+	// returning nothing returns zero values, i.e. func(id int) (_ models.Book, _ error)
+	Literal string
+	// Call to the method with zero values for its arguments, i.e. Get(0)
+	Call string
+}
+
+// A fake passed to the component's constructor, along with the field it's stored in
+type testStored struct {
+	Field string
+	// Variable the fake is assigned to before being passed to the constructor, i.e. fakeRepository
+	Variable string
+	Fake     testFake
+}
+
+type testRoute struct {
+	// Pattern the route is registered with, i.e. GET /api/books/{id}
+	Pattern string
+	Method  string
+	// Path requested to reach the route, with wildcards filled in, i.e. /api/books/1
+	Path string
+	Body string
+	// Status the handler responds with when nothing goes wrong, i.e. http.StatusOK
+	Status string
+	// Content-Type the handler responds with, empty if it doesn't set one
+	ContentType string
+}
+
+type testMethod struct {
+	Name string
+	// Call to the method with zero values for its arguments, i.e. Get(0)
+	Call string
+	// Variables the results of the call are assigned to, i.e. _, err
+	Results      string
+	ReturnsError bool
+}
+
+// Generates a test for an existing controller, service or repository from its source, so tests
+// can be backfilled for components that have changed since they were generated
+func (s ComponentService) GenerateTest(componentType, name string) error {
+	templateName, ok := testTemplates[componentType]
+	if !ok {
+		return fmt.Errorf("tests can't be generated for %v components, expected one of [controller, service, repository]", componentType)
+	}
+
+	componentName := parseComponentName(name)
+	filename := componentFilename(componentType, componentName)
+	componentFilepath, err := s.componentPath(componentType, filename)
+	if err != nil {
+		return err
+	}
+
+	source, err := s.filesystem.ReadFile(componentFilepath)
+	if err != nil {
+		return fmt.Errorf("unable to read %v: %v", filepath.Base(componentFilepath), err.Error())
+	}
+	if source == "" {
+		return fmt.Errorf("no %v named \"%v\" was found", componentType, name)
+	}
+
+	testFilepath := filepath.Join(filepath.Dir(componentFilepath), testFilename(filepath.Base(componentFilepath)))
+	hasTest, err := s.filesystem.HasDirectoryOrFile(testFilepath)
+	if err != nil {
+		return fmt.Errorf("unable to check if %v exists: %v", filepath.Base(testFilepath), err.Error())
+	}
+	if hasTest {
+		return fmt.Errorf("the %v already has a test, %v", componentType, filepath.Base(testFilepath))
+	}
+
+	fileset := token.NewFileSet()
+	file, err := parser.ParseFile(fileset, filepath.Base(componentFilepath), source, parser.SkipObjectResolution)
+	if err != nil {
+		return fmt.Errorf("unable to parse %v: %v", filepath.Base(componentFilepath), err)
+	}
+
+	packageTypes, err := s.packageTypes(fileset, filepath.Dir(componentFilepath))
+	if err != nil {
+		return err
+	}
+
+	manifest, err := s.filesystem.Manifest()
+	if err != nil {
+		return fmt.Errorf("unable to read project manifest: %v", err.Error())
+	}
+	modulePath, err := s.modulePath(manifest)
+	if err != nil {
+		return err
+	}
+
+	data, err := newComponentTestData(componentType, modulePath, componentName, fileset, file, packageTypes)
+	if err != nil {
+		return err
+	}
+	if !data.hasTests() {
+		return models.NothingToTestError{ComponentType: componentType, Needs: testNeeds[componentType]}
+	}
+
+	return s.renderComponent(componentType, testFilename(filename), templateName, data)
+}
+
+// Returns the filename of the test of the Go file, i.e. users_test.go for users.go
+func testFilename(filename string) string {
+	return strings.TrimSuffix(filename, ".go") + "_test.go"
+}
+
+// Returns the types declared in the package in the directory, keyed by name
+func (s ComponentService) packageTypes(fileset *token.FileSet, dir string) (map[string]ast.Expr, error) {
+	files, err := s.filesystem.ReadDirRecursive(dir)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read %v: %v", dir, err.Error())
+	}
+
+	types := make(map[string]ast.Expr)
+	for _, filename := range files {
+		if filepath.Dir(filename) != filepath.Clean(dir) || filepath.Ext(filename) != ".go" || strings.HasSuffix(filename, "_test.go") {
+			continue
+		}
+
+		source, err := s.filesystem.ReadFile(filename)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read %v: %v", filepath.Base(filename), err.Error())
+		}
+		file, err := parser.ParseFile(fileset, filepath.Base(filename), source, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse %v: %v", filepath.Base(filename), err)
+		}
+
+		for _, decl := range file.Decls {
+			if decl, ok := decl.(*ast.GenDecl); ok && decl.Tok == token.TYPE {
+				for _, spec := range decl.Specs {
+					typeSpec := spec.(*ast.TypeSpec)
+					types[typeSpec.Name.Name] = typeSpec.Type
+				}
+			}
+		}
+	}
+
+	return types, nil
+}
+
+// Works out what the component's test exercises from its source: the constructor, the interfaces
+// it depends on, and the routes or methods of the component
+func newComponentTestData(componentType, modulePath string, name componentName, fileset *token.FileSet, file *ast.File, packageTypes map[string]ast.Expr) (componentTestData, error) {
+	data := componentTestData{
+		componentTemplateData: newComponentTemplateData(componentType, name),
+		Type:                  name.Name + pascalWord(componentType),
+		Variable:              componentType,
+		Fakes:                 make([]testFake, 0),
+		Stored:                make([]testStored, 0),
+		Routes:                make([]testRoute, 0),
+		Methods:               make([]testMethod, 0),
+	}
+	data.Package = file.Name.Name
+
+	writer := testWriter{modulePath: modulePath, fileset: fileset, packageTypes: packageTypes, usedImports: make(map[string]bool)}
+
+	constructorName := "New" + data.Type
+	var constructor *ast.FuncDecl
+	for _, decl := range file.Decls {
+		if function, ok := decl.(*ast.FuncDecl); ok && function.Recv == nil && function.Name.Name == constructorName {
+			constructor = function
+		}
+	}
+	if constructor == nil {
+		return data, fmt.Errorf("unable to find %v, the constructor of the %v", constructorName, componentType)
+	}
+
+	// Fields the fakes are stored in are checked by the constructor's test, which calls a method of
+	// each fake through the field it should be stored in
+	fields := make([]string, 0)
+	interfaces := make([]string, 0)
+	if structType, ok := packageTypes[data.Type].(*ast.StructType); ok {
+		for _, field := range structType.Fields.List {
+			ident, ok := field.Type.(*ast.Ident)
+			if !ok {
+				continue
+			}
+			for _, fieldName := range field.Names {
+				fields = append(fields, fieldName.Name)
+				interfaces = append(interfaces, ident.Name)
+			}
+		}
+	}
+
+	args := make([]string, 0)
+	storedArgs := make([]string, 0)
+	for _, param := range fieldTypes(constructor.Type.Params) {
+		if _, isVariadic := param.(*ast.Ellipsis); isVariadic {
+			continue
+		}
+
+		fake, ok := writer.fake(param, data.Type)
+		if !ok {
+			args = append(args, writer.zeroValue(param))
+			storedArgs = append(storedArgs, writer.zeroValue(param))
+			continue
+		}
+		if !slices.ContainsFunc(data.Fakes, func(f testFake) bool { return f.Name == fake.Name }) {
+			data.Fakes = append(data.Fakes, fake)
+		}
+		args = append(args, fake.Name+"{}")
+
+		field := slices.Index(interfaces, fake.Interface)
+		if field == -1 || len(fake.Methods) == 0 {
+			storedArgs = append(storedArgs, fake.Name+"{}")
+			continue
+		}
+		stored := testStored{Field: fields[field], Variable: "fake" + capitalise(fields[field]), Fake: fake}
+		data.Stored = append(data.Stored, stored)
+		storedArgs = append(storedArgs, stored.Variable)
+		// Each field is only checked once, in case the component takes the same interface twice
+		interfaces[field] = ""
+	}
+	data.Constructor = fmt.Sprintf("%v(%v)", constructorName, strings.Join(args, ", "))
+	data.StoredConstructor = fmt.Sprintf("%v(%v)", constructorName, strings.Join(storedArgs, ", "))
+
+	for _, decl := range file.Decls {
+		method, ok := decl.(*ast.FuncDecl)
+		if !ok || method.Recv == nil || receiverName(method) != data.Type {
+			continue
+		}
+
+		if componentType == "controller" && method.Name.Name == "RegisterRoutes" {
+			data.Routes = writer.registeredRoutes(method, file, data.Type)
+		}
+
+		// Repositories hold state the methods depend on, which can't be worked out from the source,
+		// so only services, whose dependencies are faked, have their methods tested. Methods that
+		// don't return an error have nothing to check without knowing what they should return
+		if componentType == "service" && method.Name.IsExported() {
+			if test := writer.method(method); test.ReturnsError {
+				data.Methods = append(data.Methods, test)
+			}
+		}
+	}
+
+	required := []string{"testing"}
+	if len(data.Routes) != 0 {
+		required = append(slices.Clone(routeTestImports), required...)
+	}
+	data.Imports = writer.imports(required, file)
+
+	return data, nil
+}
+
+// Returns the name of the type the method's receiver is
+func receiverName(method *ast.FuncDecl) string {
+	receiver := method.Recv.List[0].Type
+	if pointer, ok := receiver.(*ast.StarExpr); ok {
+		receiver = pointer.X
+	}
+	if ident, ok := receiver.(*ast.Ident); ok {
+		return ident.Name
+	}
+	return ""
+}
+
+// Returns the type of every parameter or result in the list, repeating types shared by names
+func fieldTypes(fields *ast.FieldList) []ast.Expr {
+	types := make([]ast.Expr, 0)
+	if fields == nil {
+		return types
+	}
+
+	for _, field := range fields.List {
+		for range max(len(field.Names), 1) {
+			types = append(types, field.Type)
+		}
+	}
+	return types
+}
+
+// Returns the routes the patterns passed to mux.Handle and mux.HandleFunc register, leaving out the
+// ones whose handler's response can't be worked out from the component's file
+func (w testWriter) registeredRoutes(registerRoutes *ast.FuncDecl, file *ast.File, componentType string) []testRoute {
+	routes := make([]testRoute, 0)
+
+	ast.Inspect(registerRoutes.Body, func(node ast.Node) bool {
+		call, ok := node.(*ast.CallExpr)
+		if !ok || len(call.Args) != 2 {
+			return true
+		}
+		selector, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok || (selector.Sel.Name != "Handle" && selector.Sel.Name != "HandleFunc") {
+			return true
+		}
+		literal, ok := call.Args[0].(*ast.BasicLit)
+		if !ok || literal.Kind != token.STRING {
+			return true
+		}
+		pattern, err := strconv.Unquote(literal.Value)
+		if err != nil {
+			return true
+		}
+
+		handler := handlerDecl(call.Args[1], file, componentType)
+		if handler == nil {
+			return true
+		}
+		response := w.response(handler, file, nil, nil)
+		if !response.written {
+			response.status = &ast.SelectorExpr{X: ast.NewIdent("http"), Sel: ast.NewIdent("StatusOK")}
+		}
+		if response.status == nil {
+			return true
+		}
+
+		route := newTestRoute(pattern)
+		route.Status = w.source(response.status)
+		route.ContentType = response.contentType
+		routes = append(routes, route)
+		return true
+	})
+
+	return routes
+}
+
+// Returns the declaration of the handler passed to mux.Handle or mux.HandleFunc, if it's a method of
+// the component or a function in its file, i.e. c.handleGet or http.HandlerFunc(c.handleGet)
+func handlerDecl(handler ast.Expr, file *ast.File, componentType string) *ast.FuncDecl {
+	if conversion, ok := handler.(*ast.CallExpr); ok && len(conversion.Args) == 1 {
+		if selector, ok := conversion.Fun.(*ast.SelectorExpr); ok && selector.Sel.Name == "HandlerFunc" {
+			handler = conversion.Args[0]
+		}
+	}
+
+	name, isMethod := "", false
+	switch handler := handler.(type) {
+	case *ast.SelectorExpr:
+		name, isMethod = handler.Sel.Name, true
+	case *ast.Ident:
+		name = handler.Name
+	default:
+		return nil
+	}
+
+	for _, decl := range file.Decls {
+		function, ok := decl.(*ast.FuncDecl)
+		if !ok || function.Name.Name != name || function.Body == nil || (function.Recv != nil) != isMethod {
+			continue
+		}
+		if !isMethod || receiverName(function) == componentType {
+			return function
+		}
+	}
+
+	return nil
+}
+
+// Response a handler writes when nothing goes wrong
+type handlerResponse struct {
+	// Whether the status was written, as handlers that don't write one respond with 200
+	written bool
+	// Expression of the status, i.e. http.StatusOK, nil if it isn't a constant
+	status      ast.Expr
+	contentType string
+}
+
+// Works out the response the handler writes when nothing goes wrong, which is what it does outside
+// of any if statements. Calls to functions in the file that are passed the ResponseWriter are
+// followed, with args being the expressions their parameters were passed
+func (w testWriter) response(function *ast.FuncDecl, file *ast.File, args map[string]ast.Expr, visited []string) handlerResponse {
+	response := handlerResponse{}
+	if slices.Contains(visited, function.Name.Name) {
+		return response
+	}
+	visited = append(visited, function.Name.Name)
+
+	writer := ""
+	for _, field := range function.Type.Params.List {
+		for _, name := range field.Names {
+			if selector, ok := field.Type.(*ast.SelectorExpr); ok && selector.Sel.Name == "ResponseWriter" {
+				writer = name.Name
+			}
+		}
+	}
+	if writer == "" {
+		return response
+	}
+
+	// Parameters are swapped for what they were passed, so statuses passed to helpers are known
+	resolve := func(expr ast.Expr) ast.Expr {
+		if ident, ok := expr.(*ast.Ident); ok && args != nil {
+			return args[ident.Name]
+		}
+		return expr
+	}
+	isWriter := func(expr ast.Expr) bool {
+		ident, ok := expr.(*ast.Ident)
+		return ok && ident.Name == writer
+	}
+
+	// Only the first status written is sent
+	writeStatus := func(status ast.Expr) {
+		if !response.written {
+			response.written, response.status = true, constantStatus(status)
+		}
+	}
+
+	for _, statement := range function.Body.List {
+		expression, ok := statement.(*ast.ExprStmt)
+		if !ok {
+			continue
+		}
+		call, ok := expression.X.(*ast.CallExpr)
+		if !ok {
+			continue
+		}
+
+		switch fun := call.Fun.(type) {
+		case *ast.SelectorExpr:
+			// w.WriteHeader(http.StatusCreated), or w.Write([]byte("...")) which writes 200
+			if isWriter(fun.X) && fun.Sel.Name == "WriteHeader" && len(call.Args) == 1 {
+				writeStatus(resolve(call.Args[0]))
+			}
+			if isWriter(fun.X) && fun.Sel.Name == "Write" {
+				writeStatus(&ast.SelectorExpr{X: ast.NewIdent("http"), Sel: ast.NewIdent("StatusOK")})
+			}
+
+			// http.Error(w, "...", http.StatusBadRequest), http.NotFound(w, r) and
+			// http.Redirect(w, r, "/", http.StatusFound), which always respond with the status
+			if pkg, ok := fun.X.(*ast.Ident); ok && pkg.Name == "http" && len(call.Args) != 0 && isWriter(call.Args[0]) {
+				switch {
+				case fun.Sel.Name == "Error" && len(call.Args) == 3:
+					if !response.written {
+						response.contentType = "text/plain; charset=utf-8"
+					}
+					writeStatus(resolve(call.Args[2]))
+				case fun.Sel.Name == "NotFound":
+					if !response.written {
+						response.contentType = "text/plain; charset=utf-8"
+					}
+					writeStatus(&ast.SelectorExpr{X: ast.NewIdent("http"), Sel: ast.NewIdent("StatusNotFound")})
+				case fun.Sel.Name == "Redirect" && len(call.Args) == 4:
+					writeStatus(resolve(call.Args[3]))
+				}
+				continue
+			}
+
+			// w.Header().Set("Content-Type", "application/json")
+			header, ok := fun.X.(*ast.CallExpr)
+			if !ok || fun.Sel.Name != "Set" || len(call.Args) != 2 {
+				continue
+			}
+			if headerSelector, ok := header.Fun.(*ast.SelectorExpr); !ok || !isWriter(headerSelector.X) || headerSelector.Sel.Name != "Header" {
+				continue
+			}
+			if key := stringLiteral(call.Args[0]); strings.EqualFold(key, "Content-Type") && !response.written {
+				response.contentType = stringLiteral(resolve(call.Args[1]))
+			}
+		case *ast.Ident:
+			// Helpers writing the response, i.e. bookJSON(w, http.StatusOK, book)
+			helper := handlerDecl(fun, file, "")
+			if helper == nil || !slices.ContainsFunc(call.Args, isWriter) {
+				continue
+			}
+			helperArgs := make(map[string]ast.Expr)
+			helperParams := make([]string, 0)
+			for _, field := range helper.Type.Params.List {
+				for _, name := range field.Names {
+					helperParams = append(helperParams, name.Name)
+				}
+			}
+			for i, arg := range call.Args {
+				if i < len(helperParams) {
+					helperArgs[helperParams[i]] = resolve(arg)
+				}
+			}
+
+			helperResponse := w.response(helper, file, helperArgs, visited)
+			if helperResponse.contentType != "" && !response.written {
+				response.contentType = helperResponse.contentType
+			}
+			if helperResponse.written && !response.written {
+				response.written, response.status = true, helperResponse.status
+			}
+		}
+	}
+
+	return response
+}
+
+// Returns the expression if it's a status the test can refer to, i.e. http.StatusOK or 200
+func constantStatus(expr ast.Expr) ast.Expr {
+	switch expr := expr.(type) {
+	case *ast.SelectorExpr:
+		if pkg, ok := expr.X.(*ast.Ident); ok && pkg.Name == "http" && strings.HasPrefix(expr.Sel.Name, "Status") {
+			return expr
+		}
+	case *ast.BasicLit:
+		if expr.Kind == token.INT {
+			return expr
+		}
+	}
+	return nil
+}
+
+// Returns the value of the string literal, or an empty string if the expression isn't one
+func stringLiteral(expr ast.Expr) string {
+	literal, ok := expr.(*ast.BasicLit)
+	if !ok || literal.Kind != token.STRING {
+		return ""
+	}
+	value, _ := strconv.Unquote(literal.Value)
+	return value
+}
+
+// Works out the request reaching the route registered with the pattern, i.e. GET /books/{id}
+// is reached with GET /books/1
+func newTestRoute(pattern string) testRoute {
+	route := testRoute{Pattern: pattern, Method: "GET"}
+
+	target := pattern
+	if method, rest, found := strings.Cut(pattern, " "); found {
+		route.Method = method
+		target = strings.TrimSpace(rest)
+	}
+	// Patterns may start with a host, i.e. example.com/books
+	if i := strings.Index(target, "/"); i != -1 {
+		target = target[i:]
+	}
+
+	segments := strings.Split(target, "/")
+	for i, segment := range segments {
+		switch {
+		case segment == "{$}":
+			segments[i] = ""
+		case strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}"):
+			segments[i] = "1"
+		}
+	}
+	route.Path = strings.Join(segments, "/")
+
+	if slices.Contains(bodyMethods, route.Method) {
+		route.Body = "{}"
+	}
+
+	return route
+}
+
+// Writes the Go source of the generated test, keeping track of the packages it refers to
+type testWriter struct {
+	modulePath   string
+	fileset      *token.FileSet
+	packageTypes map[string]ast.Expr
+	// Names of the packages, imported by the component, that the test refers to
+	usedImports map[string]bool
+}
+
+func (w testWriter) source(expr ast.Expr) string {
+	ast.Inspect(expr, func(node ast.Node) bool {
+		if selector, ok := node.(*ast.SelectorExpr); ok {
+			if pkg, ok := selector.X.(*ast.Ident); ok {
+				w.usedImports[pkg.Name] = true
+			}
+		}
+		return true
+	})
+
+	var source bytes.Buffer
+	printer.Fprint(&source, w.fileset, expr)
+	return source.String()
+}
+
+// Returns a fake implementing the type if it's an interface declared in the component's package.
+// Fakes are named after the component unless the interface is, so fakes in the same package don't clash
+func (w testWriter) fake(expr ast.Expr, owner string) (testFake, bool) {
+	ident, ok := expr.(*ast.Ident)
+	if !ok {
+		return testFake{}, false
+	}
+	interfaceType, ok := w.packageTypes[ident.Name].(*ast.InterfaceType)
+	if !ok {
+		return testFake{}, false
+	}
+
+	fake := testFake{Name: "fake" + capitalise(ident.Name), Interface: ident.Name, Fields: make([]string, 0), Declarations: make([]string, 0), Methods: make([]testFakeMethod, 0)}
+	if !strings.HasPrefix(capitalise(ident.Name), owner) {
+		fake.Name = "fake" + owner + capitalise(ident.Name)
+	}
+
+	for _, method := range interfaceType.Methods.List {
+		function, ok := method.Type.(*ast.FuncType)
+		// Embedded interfaces aren't faked, the constructor is passed nil instead
+		if !ok || len(method.Names) == 0 {
+			return testFake{}, false
+		}
+		name := method.Names[0].Name
+
+		params := w.mockParams(function, "f")
+		signature := make([]string, 0, len(params))
+		arguments := make([]string, 0, len(params))
+		zeroValues := make([]string, 0, len(params))
+		for _, param := range params {
+			signature = append(signature, fmt.Sprintf("%v %v", param.name, param.source))
+			if param.variadic {
+				arguments = append(arguments, param.name+"...")
+				continue
+			}
+			arguments = append(arguments, param.name)
+			zeroValues = append(zeroValues, w.zeroValue(param.expr))
+		}
+
+		results := make([]string, 0)
+		for _, result := range fieldTypes(function.Results) {
+			results = append(results, w.source(result))
+		}
+		funcResults := strings.Join(results, ", ")
+		namedResults := ""
+		if len(results) > 1 {
+			funcResults = "(" + funcResults + ")"
+		}
+		if len(results) != 0 {
+			namedResults = "(_ " + strings.Join(results, ", _ ") + ")"
+		}
+
+		fake.Fields = append(fake.Fields, fmt.Sprintf("%vFunc func(%v) %v", name, strings.Join(signature, ", "), funcResults))
+		fake.Methods = append(fake.Methods, testFakeMethod{
+			Name:    name,
+			Literal: strings.TrimSpace(fmt.Sprintf("func(%v) %v", strings.Join(signature, ", "), namedResults)),
+			Call:    fmt.Sprintf("%v(%v)", name, strings.Join(zeroValues, ", ")),
+		})
+
+		declaration := strings.TrimSpace(fmt.Sprintf("func (f %v) %v(%v) %v", fake.Name, name, strings.Join(signature, ", "), namedResults))
+		if len(results) == 0 {
+			declaration += fmt.Sprintf(" {\n\tif f.%vFunc != nil {\n\t\tf.%vFunc(%v)\n\t}\n}", name, name, strings.Join(arguments, ", "))
+		} else {
+			declaration += fmt.Sprintf(" {\n\tif f.%vFunc != nil {\n\t\treturn f.%vFunc(%v)\n\t}\n\treturn\n}", name, name, strings.Join(arguments, ", "))
+		}
+		fake.Declarations = append(fake.Declarations, declaration)
+	}
+
+	return fake, true
+}
+
+// Returns the zero value of the type as Go source
+func (w testWriter) zeroValue(expr ast.Expr) string {
+	switch expr := expr.(type) {
+	case *ast.Ident:
+		switch {
+		case expr.Name == "string":
+			return `""`
+		case expr.Name == "bool":
+			return "false"
+		case expr.Name == "error" || expr.Name == "any":
+			return "nil"
+		case goBasicTypes[expr.Name] == "number":
+			return "0"
+		}
+		switch w.packageTypes[expr.Name].(type) {
+		case *ast.StructType:
+			return expr.Name + "{}"
+		case *ast.InterfaceType:
+			return "nil"
+		}
+	case *ast.StarExpr, *ast.MapType, *ast.FuncType, *ast.ChanType, *ast.InterfaceType:
+		return "nil"
+	case *ast.ArrayType:
+		if expr.Len == nil {
+			return "nil"
+		}
+	}
+
+	return fmt.Sprintf("*new(%v)", w.source(expr))
+}
+
+// Returns the test of the method, calling it with zero values
+func (w testWriter) method(method *ast.FuncDecl) testMethod {
+	args := make([]string, 0)
+	for _, param := range fieldTypes(method.Type.Params) {
+		if _, isVariadic := param.(*ast.Ellipsis); !isVariadic {
+			args = append(args, w.zeroValue(param))
+		}
+	}
+
+	results := fieldTypes(method.Type.Results)
+	variables := make([]string, len(results))
+	for i := range results {
+		variables[i] = "_"
+	}
+
+	test := testMethod{Name: method.Name.Name, Call: fmt.Sprintf("%v(%v)", method.Name.Name, strings.Join(args, ", "))}
+	if len(results) != 0 {
+		if ident, ok := results[len(results)-1].(*ast.Ident); ok && ident.Name == "error" {
+			variables[len(variables)-1] = "err"
+			test.ReturnsError = true
+		}
+	}
+	if test.ReturnsError {
+		test.Results = strings.Join(variables, ", ")
+	}
+
+	return test
+}
+
+// Returns the import specs of the test, the ones every test of its type needs along with the
+// imports of the files that the test refers to. The standard library is grouped first, with an
+// empty spec separating it from the other imports
+func (w testWriter) imports(required []string, files ...*ast.File) []string {
+	standard := make([]string, 0)
+	imports := make([]string, 0)
+	for _, importPath := range required {
+		standard = append(standard, strconv.Quote(importPath))
+	}
+
+	specs := make([]*ast.ImportSpec, 0)
+	for _, file := range files {
+		specs = append(specs, file.Imports...)
+	}
+
+	for _, spec := range specs {
+		importPath, err := strconv.Unquote(spec.Path.Value)
+		if err != nil || slices.Contains(required, importPath) {
+			continue
+		}
+
+		name := filepath.Base(importPath)
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		if !w.usedImports[name] {
+			continue
+		}
+
+		importSpec := spec.Path.Value
+		if spec.Name != nil {
+			importSpec = fmt.Sprintf("%v %v", spec.Name.Name, spec.Path.Value)
+		}
+		if slices.Contains(standard, importSpec) || slices.Contains(imports, importSpec) {
+			continue
+		}
+
+		// Standard library import paths don't start with a domain, unlike most modules but not all
+		isLocal := importPath == w.modulePath || strings.HasPrefix(importPath, w.modulePath+"/")
+		if isLocal || strings.Contains(strings.Split(importPath, "/")[0], ".") {
+			imports = append(imports, importSpec)
+		} else {
+			standard = append(standard, importSpec)
+		}
+	}
+
+	if len(imports) == 0 {
+		return standard
+	}
+	return append(append(standard, ""), imports...)
+}
@@ -0,0 +1,248 @@
+package services
+
+import (
+	"errors"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"slices"
+	"testing"
+
+	"github.com/danielronalds/gotm/models"
+	"github.com/danielronalds/gotm/repositories"
+)
+
+const testBookService = `package services
+
+import "example.com/app/models"
+
+type BookServiceRepository interface {
+	Get(id int) (models.Book, error)
+	Delete(id int) error
+}
+
+type BookService struct {
+	repository BookServiceRepository
+}
+
+func NewBookService(repository BookServiceRepository, name string) BookService {
+	return BookService{repository}
+}
+
+func (s BookService) Get(id int) (models.Book, error) {
+	return s.repository.Get(id)
+}
+
+func (s BookService) count() int {
+	return 0
+}
+`
+
+const testUsersController = `package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+type UsersController struct{}
+
+func NewUsersController() UsersController {
+	return UsersController{}
+}
+
+func (c UsersController) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /users/hello", c.handleGetHello)
+	mux.HandleFunc("POST /users", http.HandlerFunc(c.handleCreate))
+	mux.HandleFunc("DELETE /users/{id}", c.handleDelete)
+	mux.Handle("GET /users/legacy", legacyHandler())
+}
+
+func (c UsersController) handleGetHello(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode("Hello"); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (c UsersController) handleCreate(w http.ResponseWriter, r *http.Request) {
+	if r.Body == nil {
+		http.Error(w, "Missing body", http.StatusBadRequest)
+		return
+	}
+
+	userJSON(w, http.StatusCreated, "created")
+}
+
+func (c UsersController) handleDelete(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func userJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+`
+
+func parseTestSource(source string, t *testing.T) (*token.FileSet, *ast.File, map[string]ast.Expr) {
+	fileset := token.NewFileSet()
+	file, err := parser.ParseFile(fileset, "", source, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	types := make(map[string]ast.Expr)
+	ast.Inspect(file, func(node ast.Node) bool {
+		if spec, ok := node.(*ast.TypeSpec); ok {
+			types[spec.Name.Name] = spec.Type
+		}
+		return true
+	})
+
+	return fileset, file, types
+}
+
+func TestNewTestRouteFillsInWildcards(t *testing.T) {
+	// Arrange
+	expected := testRoute{Pattern: "PUT /api/books/{id}/pages/{page...}", Method: "PUT", Path: "/api/books/1/pages/1", Body: "{}"}
+
+	// Act
+	result := newTestRoute("PUT /api/books/{id}/pages/{page...}")
+
+	// Assert
+	if result != expected {
+		t.Fatalf("Wanted %v, got %v", expected, result)
+	}
+}
+
+func TestNewTestRouteDefaultsToGet(t *testing.T) {
+	// Arrange
+	expected := testRoute{Pattern: "/static/{$}", Method: "GET", Path: "/static/"}
+
+	// Act
+	result := newTestRoute("/static/{$}")
+
+	// Assert
+	if result != expected {
+		t.Fatalf("Wanted %v, got %v", expected, result)
+	}
+}
+
+func TestNewComponentTestDataFakesInterfaceDependencies(t *testing.T) {
+	// Arrange
+	fileset, file, types := parseTestSource(testBookService, t)
+	expectedConstructor := `NewBookService(fakeBookServiceRepository{}, "")`
+	expectedStoredConstructor := `NewBookService(fakeRepository, "")`
+	expectedFakeMethod := "func (f fakeBookServiceRepository) Get(id int) (_ models.Book, _ error) {\n\tif f.GetFunc != nil {\n\t\treturn f.GetFunc(id)\n\t}\n\treturn\n}"
+
+	// Act
+	data, err := newComponentTestData("service", "example.com/app", parseComponentName("book"), fileset, file, types)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Failed to work out test: %v", err)
+	}
+	if data.Constructor != expectedConstructor {
+		t.Fatalf("Wanted %v, got %v", expectedConstructor, data.Constructor)
+	}
+	if data.StoredConstructor != expectedStoredConstructor {
+		t.Fatalf("Wanted %v, got %v", expectedStoredConstructor, data.StoredConstructor)
+	}
+	if len(data.Fakes) != 1 || data.Fakes[0].Declarations[0] != expectedFakeMethod {
+		t.Fatalf("Wanted a fake with %v, got %v", expectedFakeMethod, data.Fakes)
+	}
+	if data.Fakes[0].Fields[0] != "GetFunc func(id int) (models.Book, error)" || data.Fakes[0].Methods[0].Literal != "func(id int) (_ models.Book, _ error)" {
+		t.Fatalf("Expected Get to be configurable, got %v", data.Fakes[0])
+	}
+	if len(data.Stored) != 1 || data.Stored[0].Field != "repository" || data.Stored[0].Fake.Methods[0].Call != "Get(0)" {
+		t.Fatalf("Wanted repository to be checked by calling Get, got %v", data.Stored)
+	}
+	if len(data.Methods) != 1 || data.Methods[0].Call != "Get(0)" || data.Methods[0].Results != "_, err" {
+		t.Fatalf("Expected only Get to be tested, got %v", data.Methods)
+	}
+	if data.Imports[len(data.Imports)-1] != `"example.com/app/models"` {
+		t.Fatalf("Expected the models package to be imported, got %v", data.Imports)
+	}
+}
+
+func TestNewComponentTestDataWorksOutRouteResponses(t *testing.T) {
+	// Arrange
+	fileset, file, types := parseTestSource(testUsersController, t)
+	expected := []testRoute{
+		{Pattern: "GET /users/hello", Method: "GET", Path: "/users/hello", Status: "http.StatusOK", ContentType: "application/json"},
+		{Pattern: "POST /users", Method: "POST", Path: "/users", Body: "{}", Status: "http.StatusCreated", ContentType: "application/json"},
+		{Pattern: "DELETE /users/{id}", Method: "DELETE", Path: "/users/1", Status: "http.StatusNoContent"},
+	}
+
+	// Act
+	data, err := newComponentTestData("controller", "example.com/app", parseComponentName("users"), fileset, file, types)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Failed to work out test: %v", err)
+	}
+	if !slices.Equal(data.Routes, expected) {
+		t.Fatalf("Wanted %v, got %v", expected, data.Routes)
+	}
+}
+
+func TestGenerateTestSkipsComponentsWithNothingToCheck(t *testing.T) {
+	// Arrange
+	filesystem := repositories.NewMemoryFilesystemRepository("/project")
+	writeMemoryFile(filesystem, "/project/gotm.json", `{"name": "project", "module": "example.com/project"}`, t)
+	if err := filesystem.CreateDirectory("/project/services"); err != nil {
+		t.Fatal(err)
+	}
+	writeMemoryFile(filesystem, "/project/services/orders.go", "package services\n\ntype OrdersService struct{}\n\nfunc NewOrdersService() OrdersService {\n\treturn OrdersService{}\n}\n\nfunc (s OrdersService) Count() int {\n\treturn 0\n}\n", t)
+	componentService := NewComponentService(filesystem, mockTemplates{})
+
+	// Act
+	err := componentService.GenerateTest("service", "orders")
+
+	// Assert
+	var nothingToTest models.NothingToTestError
+	if !errors.As(err, &nothingToTest) {
+		t.Fatalf("Expected a service with nothing to check to be skipped, got %v", err)
+	}
+	if hasFile, _ := filesystem.HasDirectoryOrFile("/project/services/orders_test.go"); hasFile {
+		t.Fatal("Expected services/orders_test.go not to be created")
+	}
+}
+
+func TestGenerateTestRequiresExistingComponent(t *testing.T) {
+	// Arrange
+	filesystem := repositories.NewMemoryFilesystemRepository("/project")
+	writeMemoryFile(filesystem, "/project/gotm.json", `{"name": "project", "module": "example.com/project"}`, t)
+	componentService := NewComponentService(filesystem, mockTemplates{})
+
+	// Act
+	err := componentService.GenerateTest("controller", "users")
+
+	// Assert
+	if err == nil {
+		t.Fatal("Expected generating a test for a missing controller to fail")
+	}
+}
+
+func TestGenerateTestBackfillsExistingComponent(t *testing.T) {
+	// Arrange
+	filesystem := repositories.NewMemoryFilesystemRepository("/project")
+	writeMemoryFile(filesystem, "/project/gotm.json", `{"name": "project", "module": "example.com/project"}`, t)
+	if err := filesystem.CreateDirectory("/project/controllers"); err != nil {
+		t.Fatal(err)
+	}
+	writeMemoryFile(filesystem, "/project/controllers/users.go", testUsersController, t)
+	componentService := NewComponentService(filesystem, mockTemplates{})
+
+	// Act
+	err := componentService.GenerateTest("controller", "users")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Failed to generate test: %v", err)
+	}
+	if hasFile, _ := filesystem.HasDirectoryOrFile("/project/controllers/users_test.go"); !hasFile {
+		t.Fatal("Expected controllers/users_test.go to be created")
+	}
+}
@@ -0,0 +1,764 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/danielronalds/gotm/models"
+)
+
+// File the TypeScript declarations are generated in, inside the project's types directory
+const GENERATED_TYPES_FILE = "generated.ts"
+
+const generatedTypesHeader = "// Code generated by `gotm gen types` from the project's Go structs. DO NOT EDIT.\n"
+
+// TypeScript types of Go's builtin types, as encoding/json marshals them
+var goBasicTypes = map[string]string{
+	"string": "string", "bool": "boolean",
+	"int": "number", "int8": "number", "int16": "number", "int32": "number", "int64": "number",
+	"uint": "number", "uint8": "number", "uint16": "number", "uint32": "number", "uint64": "number",
+	"uintptr": "number", "float32": "number", "float64": "number", "byte": "number", "rune": "number",
+}
+
+// TypeScript types of types from the standard library, keyed by import path and type name
+var goStandardTypes = map[string]string{
+	"time.Time":                "string",
+	"time.Duration":            "number",
+	"encoding/json.RawMessage": "unknown",
+}
+
+// HTTP methods dropped from the start of handler names when naming the structs they respond with
+var handlerMethodWords = []string{"get", "post", "put", "patch", "delete"}
+
+var tsIdentifierRegex = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+type TypesServiceFilesystem interface {
+	ProjectRoot
+	ProjectManifest
+	DirReader
+	DirCreater
+	FileReader
+	FileWriter
+}
+
+// Service for generating TypeScript declarations of the JSON the backend sends and receives, so the
+// frontend's models don't have to re-declare the shape of every response by hand
+type TypesService struct {
+	filesystem TypesServiceFilesystem
+}
+
+func NewTypesService(filesystem TypesServiceFilesystem) TypesService {
+	return TypesService{filesystem}
+}
+
+// A Go type declared in the project, identified by the directory of its package, relative to the
+// project root, and its name
+type goTypeKey struct {
+	dir  string
+	name string
+}
+
+type goTypeDecl struct {
+	key  goTypeKey
+	expr ast.Expr
+	// Import paths of the file the type is declared in, keyed by the name they're imported under
+	imports map[string]string
+	// Whether the type is declared regardless of whether another type refers to it
+	root bool
+	// Names of the type's type parameters, which become the parameters of the TypeScript type
+	typeParams []string
+}
+
+// Generates the declarations of every exported struct with json tags, along with the anonymous
+// structs handlers respond with and every type they refer to, writing them to the types directory
+func (s TypesService) GenerateTypes() (models.GeneratedTypes, error) {
+	generated := models.GeneratedTypes{Types: make([]string, 0)}
+
+	manifest, err := s.filesystem.Manifest()
+	if err != nil {
+		return generated, fmt.Errorf("unable to read project manifest: %v", err.Error())
+	}
+
+	decls, modulePath, err := s.parseProject(manifest)
+	if err != nil {
+		return generated, err
+	}
+
+	declarations, names := declareTypes(decls, modulePath)
+	generated.Types = names
+
+	dir, err := s.filesystem.FromRoot(manifest.Directories.Types)
+	if err != nil {
+		return generated, err
+	}
+	filename := filepath.Join(dir, GENERATED_TYPES_FILE)
+	generated.Filename = filepath.ToSlash(filepath.Join(manifest.Directories.Types, GENERATED_TYPES_FILE))
+
+	existing, err := s.filesystem.ReadFile(filename)
+	if err != nil {
+		return generated, fmt.Errorf("unable to read %v: %v", generated.Filename, err.Error())
+	}
+	if existing == declarations {
+		return generated, nil
+	}
+
+	if err := s.filesystem.CreateDirectory(dir); err != nil {
+		return generated, fmt.Errorf("unable to create %v: %v", manifest.Directories.Types, err.Error())
+	}
+	if err := s.filesystem.WriteFile(filename, declarations); err != nil {
+		return generated, fmt.Errorf("unable to write %v: %v", generated.Filename, err.Error())
+	}
+	generated.Changed = true
+
+	return generated, nil
+}
+
+// Returns whether the project's types have been generated before, and so should be kept up to date
+func (s TypesService) HasGeneratedTypes() (bool, error) {
+	manifest, err := s.filesystem.Manifest()
+	if err != nil {
+		return false, fmt.Errorf("unable to read project manifest: %v", err.Error())
+	}
+
+	filename, err := s.filesystem.FromRoot(filepath.Join(manifest.Directories.Types, GENERATED_TYPES_FILE))
+	if err != nil {
+		return false, err
+	}
+
+	return s.filesystem.HasDirectoryOrFile(filename)
+}
+
+// Parses every Go file of the project outside the frontend, returning the types it declares and
+// the project's module path
+func (s TypesService) parseProject(manifest models.Manifest) (map[goTypeKey]*goTypeDecl, string, error) {
+	root, err := s.filesystem.Root()
+	if err != nil {
+		return nil, "", err
+	}
+
+	modulePath, err := projectModulePath(s.filesystem, manifest)
+	if err != nil {
+		return nil, "", err
+	}
+
+	files, err := projectGoFiles(s.filesystem, manifest)
+	if err != nil {
+		return nil, "", err
+	}
+
+	decls := make(map[goTypeKey]*goTypeDecl)
+	anonymous := make([]anonymousStruct, 0)
+	fileset := token.NewFileSet()
+
+	for _, relative := range files {
+		contents, err := s.filesystem.ReadFile(filepath.Join(root, filepath.FromSlash(relative)))
+		if err != nil {
+			return nil, "", fmt.Errorf("unable to read %v: %v", relative, err.Error())
+		}
+
+		parsed, err := parser.ParseFile(fileset, relative, contents, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, "", fmt.Errorf("unable to parse %v: %v", relative, err)
+		}
+
+		anonymous = append(anonymous, collectTypeDecls(decls, parsed, path.Dir(relative))...)
+	}
+
+	if err := declareAnonymousStructs(decls, anonymous); err != nil {
+		return nil, "", err
+	}
+
+	return decls, modulePath, nil
+}
+
+type projectFilesystem interface {
+	ProjectRoot
+	DirReader
+	FileReader
+}
+
+// Returns the module path of the project, from its manifest or else its go.mod
+func projectModulePath(filesystem projectFilesystem, manifest models.Manifest) (string, error) {
+	if manifest.Module != "" {
+		return manifest.Module, nil
+	}
+
+	goMod, err := filesystem.FromRoot("go.mod")
+	if err != nil {
+		return "", err
+	}
+
+	contents, err := filesystem.ReadFile(goMod)
+	if err != nil {
+		return "", fmt.Errorf("unable to read go.mod: %v", err.Error())
+	}
+
+	modulePath := modulePathFromGoMod(contents)
+	if modulePath == "" {
+		return "", errors.New("unable to find the module path in go.mod")
+	}
+
+	return modulePath, nil
+}
+
+// Returns the Go files of the project, leaving out tests and anything in the frontend or ignored
+// directories. Paths are slash separated, relative to the project root, and sorted
+func projectGoFiles(filesystem projectFilesystem, manifest models.Manifest) ([]string, error) {
+	root, err := filesystem.Root()
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := filesystem.ReadDirRecursive(root)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read project: %v", err.Error())
+	}
+
+	goFiles := make([]string, 0)
+	for _, file := range files {
+		relative, err := filepath.Rel(root, file)
+		if err != nil {
+			return nil, err
+		}
+		relative = filepath.ToSlash(relative)
+
+		if !strings.HasSuffix(relative, ".go") || strings.HasSuffix(relative, "_test.go") || isWithin(manifest.Frontend, relative) {
+			continue
+		}
+		if slices.ContainsFunc(strings.Split(relative, "/"), func(dir string) bool { return slices.Contains(manifest.IgnoredDirs, dir) }) {
+			continue
+		}
+
+		goFiles = append(goFiles, relative)
+	}
+	slices.Sort(goFiles)
+
+	return goFiles, nil
+}
+
+// Returns whether the slash separated path is the directory or inside it
+func isWithin(dir, relative string) bool {
+	dir = path.Clean(filepath.ToSlash(dir))
+	return dir != "." && (relative == dir || strings.HasPrefix(relative, dir+"/"))
+}
+
+// An anonymous struct with json tags, which is named once every type of the project is known
+type anonymousStruct struct {
+	dir  string
+	name string
+	// Type of the receiver of the method the struct is in, empty if it's in a function
+	receiver string
+	function string
+	expr     *ast.StructType
+	imports  map[string]string
+}
+
+// Adds the exported types the file declares to decls, returning the anonymous structs its
+// functions marshal
+func collectTypeDecls(decls map[goTypeKey]*goTypeDecl, file *ast.File, dir string) []anonymousStruct {
+	imports := make(map[string]string)
+	for _, spec := range file.Imports {
+		importPath, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			continue
+		}
+		name := path.Base(importPath)
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		imports[name] = importPath
+	}
+
+	anonymous := make([]anonymousStruct, 0)
+	for _, decl := range file.Decls {
+		switch decl := decl.(type) {
+		case *ast.GenDecl:
+			if decl.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range decl.Specs {
+				typeSpec := spec.(*ast.TypeSpec)
+				if !typeSpec.Name.IsExported() {
+					continue
+				}
+				structType, isStruct := typeSpec.Type.(*ast.StructType)
+				key := goTypeKey{dir, typeSpec.Name.Name}
+				decls[key] = &goTypeDecl{key, typeSpec.Type, imports, isStruct && hasJSONTags(structType), typeParamNames(typeSpec)}
+			}
+		case *ast.FuncDecl:
+			if decl.Body != nil {
+				anonymous = append(anonymous, collectAnonymousStructs(decl, dir, imports)...)
+			}
+		}
+	}
+
+	return anonymous
+}
+
+// Returns the anonymous structs with json tags in the function's body, i.e. the responses of
+// handlers, named after the function and the variable they're assigned to
+func collectAnonymousStructs(function *ast.FuncDecl, dir string, imports map[string]string) []anonymousStruct {
+	receiver := receiverTypeName(function)
+	anonymous := make([]anonymousStruct, 0)
+	variables := make(map[ast.Expr]string)
+
+	ast.Inspect(function.Body, func(node ast.Node) bool {
+		var structType *ast.StructType
+
+		switch node := node.(type) {
+		case *ast.AssignStmt:
+			for i, value := range node.Rhs {
+				if i >= len(node.Lhs) {
+					break
+				}
+				if ident, ok := node.Lhs[i].(*ast.Ident); ok {
+					variables[value] = ident.Name
+				}
+			}
+			return true
+		case *ast.ValueSpec:
+			for i, value := range node.Values {
+				if i < len(node.Names) {
+					variables[value] = node.Names[i].Name
+				}
+			}
+			if declared, ok := node.Type.(*ast.StructType); ok && len(node.Names) != 0 {
+				variables[declared] = node.Names[0].Name
+				structType = declared
+			}
+		case *ast.CompositeLit:
+			if literal, ok := node.Type.(*ast.StructType); ok {
+				structType = literal
+				if name, ok := variables[node]; ok {
+					variables[literal] = name
+				}
+			}
+		}
+
+		if structType == nil || !hasJSONTags(structType) {
+			return true
+		}
+
+		variable, ok := variables[structType]
+		if !ok {
+			variable = "response"
+		}
+		name := anonymousStructName(function.Name.Name, variable)
+		anonymous = append(anonymous, anonymousStruct{dir, name, receiver, function.Name.Name, structType, imports})
+
+		return true
+	})
+
+	return anonymous
+}
+
+// Returns the name of the type of the function's receiver, or an empty string if it isn't a method
+func receiverTypeName(function *ast.FuncDecl) string {
+	if function.Recv == nil || len(function.Recv.List) == 0 {
+		return ""
+	}
+
+	expr := function.Recv.List[0].Type
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+	switch receiver := expr.(type) {
+	case *ast.IndexExpr:
+		expr = receiver.X
+	case *ast.IndexListExpr:
+		expr = receiver.X
+	}
+	if ident, ok := expr.(*ast.Ident); ok {
+		return ident.Name
+	}
+
+	return ""
+}
+
+// Adds the anonymous structs to decls. A name shared with another anonymous struct or a declared
+// type of the same package is qualified with the receiver of the method the struct is in, i.e.
+// BooksControllerHelloResponse, failing if that doesn't make it unique
+func declareAnonymousStructs(decls map[goTypeKey]*goTypeDecl, anonymous []anonymousStruct) error {
+	counts := make(map[goTypeKey]int)
+	for _, structType := range anonymous {
+		counts[goTypeKey{structType.dir, structType.name}]++
+	}
+
+	for _, structType := range anonymous {
+		key := goTypeKey{structType.dir, structType.name}
+		if (counts[key] > 1 || decls[key] != nil) && structType.receiver != "" {
+			key.name = structType.receiver + key.name
+		}
+
+		if decls[key] != nil {
+			function := structType.function
+			if structType.receiver != "" {
+				function = fmt.Sprintf("%v.%v", structType.receiver, structType.function)
+			}
+			return fmt.Errorf("unable to name the anonymous struct in %v of %v, as %v is used by another type. Declare it as a named type instead", function, structType.dir, key.name)
+		}
+
+		decls[key] = &goTypeDecl{key, structType.expr, structType.imports, true, nil}
+	}
+
+	return nil
+}
+
+// Names an anonymous struct after the function it's in and the variable it's assigned to, dropping
+// the handle and HTTP method prefixes of handlers, i.e. handleGetHello and response give HelloResponse
+func anonymousStructName(function, variable string) string {
+	words := nameWords(function)
+	if len(words) > 1 && words[0] == "handle" {
+		words = words[1:]
+	}
+	if len(words) > 1 && slices.Contains(handlerMethodWords, words[0]) {
+		words = words[1:]
+	}
+
+	return identifierFromWords(append(words, nameWords(variable)...)).Name
+}
+
+func hasJSONTags(structType *ast.StructType) bool {
+	for _, field := range structType.Fields.List {
+		if _, ok := jsonTag(field); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns the json tag of the field, and whether it has one
+func jsonTag(field *ast.Field) (string, bool) {
+	if field.Tag == nil {
+		return "", false
+	}
+	tag, err := strconv.Unquote(field.Tag.Value)
+	if err != nil {
+		return "", false
+	}
+	return reflect.StructTag(tag).Lookup("json")
+}
+
+// Converts Go types to TypeScript, keeping track of the project's types that are referred to
+type tsConverter struct {
+	decls      map[goTypeKey]*goTypeDecl
+	modulePath string
+	// TypeScript names of the project's types, which are only known once every referred to type is
+	names map[goTypeKey]string
+	// Types that have been referred to, in the order they were
+	referred []goTypeKey
+}
+
+// Renders the declarations of the root types and every type they refer to, returning the file and
+// the names of the declared types
+func declareTypes(decls map[goTypeKey]*goTypeDecl, modulePath string) (string, []string) {
+	converter := &tsConverter{decls: decls, modulePath: modulePath}
+
+	roots := make([]goTypeKey, 0)
+	for key, decl := range decls {
+		if decl.root {
+			roots = append(roots, key)
+		}
+	}
+	slices.SortFunc(roots, func(a, b goTypeKey) int { return strings.Compare(a.dir+"/"+a.name, b.dir+"/"+b.name) })
+
+	// Rendering once finds every referred to type, so types sharing a name can be told apart
+	converter.referred = roots
+	for i := 0; i < len(converter.referred); i++ {
+		converter.declaration(converter.referred[i])
+	}
+	converter.names = typeNames(converter.referred)
+
+	declarations := make(map[string]string)
+	names := make([]string, 0, len(converter.referred))
+	for _, key := range converter.referred {
+		name := converter.names[key]
+		declarations[name] = converter.declaration(key)
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	var file strings.Builder
+	file.WriteString(generatedTypesHeader)
+	for _, name := range names {
+		file.WriteString("\n" + declarations[name])
+	}
+	if len(names) == 0 {
+		file.WriteString("\nexport {};\n")
+	}
+
+	return file.String(), names
+}
+
+// Names each type after its Go name, prefixing the names that appear in more than one package with
+// their package's directory, i.e. ControllersAdminUser
+func typeNames(keys []goTypeKey) map[goTypeKey]string {
+	counts := make(map[string]int)
+	for _, key := range keys {
+		counts[key.name]++
+	}
+
+	names := make(map[goTypeKey]string)
+	for _, key := range keys {
+		names[key] = key.name
+		if counts[key.name] > 1 {
+			dir := strings.ReplaceAll(key.dir, "/", " ")
+			if key.dir == "." {
+				dir = "main"
+			}
+			names[key] = newIdentifier(dir).Name + key.name
+		}
+	}
+	return names
+}
+
+func (c *tsConverter) name(key goTypeKey) string {
+	if name, ok := c.names[key]; ok {
+		return name
+	}
+	return key.name
+}
+
+func (c *tsConverter) declaration(key goTypeKey) string {
+	decl := c.decls[key]
+	name := c.name(key)
+	if len(decl.typeParams) != 0 {
+		name += "<" + strings.Join(decl.typeParams, ", ") + ">"
+	}
+
+	structType, ok := decl.expr.(*ast.StructType)
+	if !ok {
+		return fmt.Sprintf("export type %v = %v;\n", name, c.tsType(decl.expr, decl))
+	}
+
+	extends, fields := c.fields(structType, decl)
+	heritage := ""
+	if len(extends) != 0 {
+		heritage = " extends " + strings.Join(extends, ", ")
+	}
+
+	var declaration strings.Builder
+	fmt.Fprintf(&declaration, "export interface %v%v {\n", name, heritage)
+	for _, field := range fields {
+		fmt.Fprintf(&declaration, "  %v;\n", field)
+	}
+	declaration.WriteString("}\n")
+
+	return declaration.String()
+}
+
+// Returns the types the struct's embedded structs are promoted from, and its fields, following the
+// rules encoding/json marshals structs by
+func (c *tsConverter) fields(structType *ast.StructType, decl *goTypeDecl) ([]string, []string) {
+	extends := make([]string, 0)
+	fields := make([]string, 0)
+
+	for _, field := range structType.Fields.List {
+		tag, _ := jsonTag(field)
+		tagName, options, _ := strings.Cut(tag, ",")
+		if tag == "-" {
+			continue
+		}
+
+		names := make([]string, 0, len(field.Names))
+		for _, name := range field.Names {
+			if name.IsExported() {
+				names = append(names, name.Name)
+			}
+		}
+
+		// Untagged embedded structs have their fields promoted into the struct
+		if len(field.Names) == 0 {
+			embedded := field.Type
+			if pointer, ok := embedded.(*ast.StarExpr); ok {
+				embedded = pointer.X
+			}
+			if tagName == "" {
+				if key, ok := c.resolve(genericType(embedded), decl); ok {
+					if _, isStruct := c.decls[key].expr.(*ast.StructType); isStruct {
+						extends = append(extends, c.tsType(embedded, decl))
+						continue
+					}
+				}
+			}
+			if name := embeddedName(embedded); ast.IsExported(name) {
+				names = append(names, name)
+			}
+		}
+
+		optional := slices.Contains(strings.Split(options, ","), "omitempty") || slices.Contains(strings.Split(options, ","), "omitzero")
+
+		tsType := c.tsType(field.Type, decl)
+		if slices.Contains(strings.Split(options, ","), "string") {
+			tsType = "string"
+		}
+		// Nil pointers are marshalled as null, unless they're omitted
+		if _, isPointer := field.Type.(*ast.StarExpr); isPointer && !optional {
+			tsType += " | null"
+		}
+
+		for _, name := range names {
+			if tagName != "" {
+				name = tagName
+			}
+			if !tsIdentifierRegex.MatchString(name) {
+				name = strconv.Quote(name)
+			}
+			if optional {
+				name += "?"
+			}
+			fields = append(fields, fmt.Sprintf("%v: %v", name, tsType))
+		}
+	}
+
+	return extends, fields
+}
+
+// Returns the name of the embedded type, which is the name of the field it's embedded as
+func embeddedName(expr ast.Expr) string {
+	switch expr := genericType(expr).(type) {
+	case *ast.Ident:
+		return expr.Name
+	case *ast.SelectorExpr:
+		return expr.Sel.Name
+	}
+	return ""
+}
+
+// Returns the generic type an instantiation like Page[Book] is of, or the expression itself if it
+// isn't one
+func genericType(expr ast.Expr) ast.Expr {
+	switch expr := expr.(type) {
+	case *ast.IndexExpr:
+		return expr.X
+	case *ast.IndexListExpr:
+		return expr.X
+	}
+	return expr
+}
+
+// Returns the names of the type's type parameters, i.e. T for Page[T any]
+func typeParamNames(typeSpec *ast.TypeSpec) []string {
+	if typeSpec.TypeParams == nil {
+		return nil
+	}
+
+	names := make([]string, 0)
+	for _, field := range typeSpec.TypeParams.List {
+		for _, name := range field.Names {
+			names = append(names, name.Name)
+		}
+	}
+	return names
+}
+
+// Returns the project type the expression names, if it names one
+func (c *tsConverter) resolve(expr ast.Expr, decl *goTypeDecl) (goTypeKey, bool) {
+	var key goTypeKey
+
+	switch expr := expr.(type) {
+	case *ast.Ident:
+		key = goTypeKey{decl.key.dir, expr.Name}
+	case *ast.SelectorExpr:
+		pkg, ok := expr.X.(*ast.Ident)
+		if !ok {
+			return key, false
+		}
+		importPath, ok := decl.imports[pkg.Name]
+		if !ok || (importPath != c.modulePath && !strings.HasPrefix(importPath, c.modulePath+"/")) {
+			return key, false
+		}
+		dir := strings.TrimPrefix(strings.TrimPrefix(importPath, c.modulePath), "/")
+		if dir == "" {
+			dir = "."
+		}
+		key = goTypeKey{dir, expr.Sel.Name}
+	default:
+		return key, false
+	}
+
+	_, ok := c.decls[key]
+	return key, ok
+}
+
+// Returns the name of the type, recording that it has been referred to
+func (c *tsConverter) refer(key goTypeKey) string {
+	if !slices.Contains(c.referred, key) {
+		c.referred = append(c.referred, key)
+	}
+	return c.name(key)
+}
+
+func (c *tsConverter) tsType(expr ast.Expr, decl *goTypeDecl) string {
+	if ident, ok := expr.(*ast.Ident); ok && slices.Contains(decl.typeParams, ident.Name) {
+		return ident.Name
+	}
+	if key, ok := c.resolve(expr, decl); ok {
+		return c.refer(key)
+	}
+
+	switch expr := expr.(type) {
+	case *ast.IndexExpr:
+		return c.instantiation(expr.X, []ast.Expr{expr.Index}, decl)
+	case *ast.IndexListExpr:
+		return c.instantiation(expr.X, expr.Indices, decl)
+	case *ast.Ident:
+		if tsType, ok := goBasicTypes[expr.Name]; ok {
+			return tsType
+		}
+	case *ast.SelectorExpr:
+		if pkg, ok := expr.X.(*ast.Ident); ok {
+			if tsType, ok := goStandardTypes[decl.imports[pkg.Name]+"."+expr.Sel.Name]; ok {
+				return tsType
+			}
+		}
+	case *ast.ParenExpr:
+		return c.tsType(expr.X, decl)
+	case *ast.StarExpr:
+		return c.tsType(expr.X, decl)
+	case *ast.ArrayType:
+		// Byte slices are marshalled as base64 strings
+		if ident, ok := expr.Elt.(*ast.Ident); ok && expr.Len == nil && (ident.Name == "byte" || ident.Name == "uint8") {
+			return "string"
+		}
+		element := c.tsType(expr.Elt, decl)
+		if strings.ContainsAny(element, " |") {
+			element = "(" + element + ")"
+		}
+		return element + "[]"
+	case *ast.MapType:
+		return fmt.Sprintf("Record<string, %v>", c.tsType(expr.Value, decl))
+	case *ast.StructType:
+		_, fields := c.fields(expr, decl)
+		if len(fields) == 0 {
+			return "{}"
+		}
+		return "{ " + strings.Join(fields, "; ") + " }"
+	}
+
+	return "unknown"
+}
+
+// Converts an instantiation of a generic type, i.e. Page[Book] to Page<Book>
+func (c *tsConverter) instantiation(generic ast.Expr, args []ast.Expr, decl *goTypeDecl) string {
+	key, ok := c.resolve(generic, decl)
+	if !ok {
+		return "unknown"
+	}
+
+	tsArgs := make([]string, 0, len(args))
+	for _, arg := range args {
+		tsArgs = append(tsArgs, c.tsType(arg, decl))
+	}
+	return c.refer(key) + "<" + strings.Join(tsArgs, ", ") + ">"
+}
@@ -0,0 +1,227 @@
+package services
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/danielronalds/gotm/repositories"
+)
+
+const testModelsFile = `package models
+
+import "time"
+
+type Book struct {
+	ID        int               ` + "`json:\"id\"`" + `
+	Title     string            ` + "`json:\"title\"`" + `
+	Subtitle  *string           ` + "`json:\"subtitle\"`" + `
+	Editor    *Author           ` + "`json:\"editor,omitempty\"`" + `
+	Tags      []string          ` + "`json:\"tags,omitempty\"`" + `
+	Ratings   map[string]float64 ` + "`json:\"ratings\"`" + `
+	Author    Author            ` + "`json:\"author\"`" + `
+	Published time.Time         ` + "`json:\"published\"`" + `
+	internal  string
+	Secret    string ` + "`json:\"-\"`" + `
+}
+
+type Author struct {
+	Name string
+}
+
+type unexported struct {
+	Name string ` + "`json:\"name\"`" + `
+}
+`
+
+const testHandlerFile = `package controllers
+
+import "encoding/json"
+
+func handleGetHello(w http.ResponseWriter, r *http.Request) {
+	response := struct {
+		Message string ` + "`json:\"message\"`" + `
+	}{Message: "Hello"}
+
+	json.NewEncoder(w).Encode(response)
+}
+`
+
+func newTypesTestFilesystem(t *testing.T) repositories.MemoryFilesystemRepository {
+	filesystem := repositories.NewMemoryFilesystemRepository("/project")
+	writeMemoryFile(filesystem, "/project/gotm.json", `{"name": "project", "module": "example.com/project"}`, t)
+	for _, dir := range []string{"/project/models", "/project/controllers"} {
+		if err := filesystem.CreateDirectory(dir); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeMemoryFile(filesystem, "/project/models/book.go", testModelsFile, t)
+	writeMemoryFile(filesystem, "/project/controllers/hello.go", testHandlerFile, t)
+	return filesystem
+}
+
+func TestGenerateTypesDeclaresStructsWithJSONTags(t *testing.T) {
+	// Arrange
+	filesystem := newTypesTestFilesystem(t)
+	typesService := NewTypesService(filesystem)
+	expected := `export interface Book {
+  id: number;
+  title: string;
+  subtitle: string | null;
+  editor?: Author;
+  tags?: string[];
+  ratings: Record<string, number>;
+  author: Author;
+  published: string;
+}
+`
+
+	// Act
+	generated, err := typesService.GenerateTypes()
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Failed to generate types: %v", err)
+	}
+	contents, _ := filesystem.ReadFile("/project/frontend/src/types/generated.ts")
+	if !strings.Contains(contents, expected) {
+		t.Fatalf("Expected Book to be declared as:\n%v\ngot:\n%v", expected, contents)
+	}
+	if !strings.Contains(contents, "export interface Author {\n  Name: string;\n}") {
+		t.Fatalf("Expected the referenced Author struct to be declared, got:\n%v", contents)
+	}
+	if !slices.Equal(generated.Types, []string{"Author", "Book", "HelloResponse"}) {
+		t.Fatalf("Wanted [Author Book HelloResponse], got %v", generated.Types)
+	}
+}
+
+func TestGenerateTypesNamesAnonymousHandlerStructs(t *testing.T) {
+	// Arrange
+	filesystem := newTypesTestFilesystem(t)
+	typesService := NewTypesService(filesystem)
+
+	// Act
+	_, err := typesService.GenerateTypes()
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Failed to generate types: %v", err)
+	}
+	contents, _ := filesystem.ReadFile("/project/frontend/src/types/generated.ts")
+	if !strings.Contains(contents, "export interface HelloResponse {\n  message: string;\n}") {
+		t.Fatalf("Expected the handler's response to be declared, got:\n%v", contents)
+	}
+}
+
+func TestGenerateTypesLeavesUnchangedFileAlone(t *testing.T) {
+	// Arrange
+	filesystem := newTypesTestFilesystem(t)
+	typesService := NewTypesService(filesystem)
+	if _, err := typesService.GenerateTypes(); err != nil {
+		t.Fatalf("Failed to generate types: %v", err)
+	}
+
+	// Act
+	generated, err := typesService.GenerateTypes()
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Failed to generate types: %v", err)
+	}
+	if generated.Changed {
+		t.Fatal("Expected regenerating unchanged types to leave the file alone")
+	}
+}
+
+func TestGenerateTypesDeclaresGenericStructs(t *testing.T) {
+	// Arrange
+	filesystem := newTypesTestFilesystem(t)
+	writeMemoryFile(filesystem, "/project/models/page.go", `package models
+
+type Page[T any] struct {
+	Items []T `+"`json:\"items\"`"+`
+	Next  *int `+"`json:\"next\"`"+`
+}
+
+type Pair[K comparable, V any] struct {
+	Key   K `+"`json:\"key\"`"+`
+	Value V `+"`json:\"value\"`"+`
+}
+
+type Shelf struct {
+	Page[Book]
+	Labels Pair[string, Author] `+"`json:\"labels\"`"+`
+}
+`, t)
+	typesService := NewTypesService(filesystem)
+
+	// Act
+	_, err := typesService.GenerateTypes()
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Failed to generate types: %v", err)
+	}
+	contents, _ := filesystem.ReadFile("/project/frontend/src/types/generated.ts")
+	for _, expected := range []string{
+		"export interface Page<T> {\n  items: T[];\n  next: number | null;\n}",
+		"export interface Pair<K, V> {\n  key: K;\n  value: V;\n}",
+		"export interface Shelf extends Page<Book> {\n  labels: Pair<string, Author>;\n}",
+	} {
+		if !strings.Contains(contents, expected) {
+			t.Fatalf("Expected:\n%v\ngot:\n%v", expected, contents)
+		}
+	}
+}
+
+func TestTypeNamesPrefixesNamesSharedAcrossPackages(t *testing.T) {
+	// Arrange
+	keys := []goTypeKey{{"models", "User"}, {"controllers/admin", "User"}, {"models", "Book"}}
+
+	// Act
+	names := typeNames(keys)
+
+	// Assert
+	if names[keys[0]] != "ModelsUser" || names[keys[1]] != "ControllersAdminUser" || names[keys[2]] != "Book" {
+		t.Fatalf("Wanted ModelsUser, ControllersAdminUser and Book, got %v", names)
+	}
+}
+
+func TestGenerateTypesQualifiesSharedAnonymousStructNamesWithReceiver(t *testing.T) {
+	// Arrange
+	filesystem := newTypesTestFilesystem(t)
+	for _, controller := range []string{"Books", "Authors"} {
+		handler := strings.ReplaceAll(testHandlerFile, "func handleGetHello(", "func (c "+controller+"Controller) handleGetHello(")
+		writeMemoryFile(filesystem, "/project/controllers/"+strings.ToLower(controller)+".go", handler, t)
+	}
+	if err := filesystem.DeleteFileRecursive("/project/controllers/hello.go"); err != nil {
+		t.Fatal(err)
+	}
+	typesService := NewTypesService(filesystem)
+
+	// Act
+	generated, err := typesService.GenerateTypes()
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Failed to generate types: %v", err)
+	}
+	if !slices.Equal(generated.Types, []string{"Author", "AuthorsControllerHelloResponse", "Book", "BooksControllerHelloResponse"}) {
+		t.Fatalf("Wanted the responses to be named after their controllers, got %v", generated.Types)
+	}
+}
+
+func TestGenerateTypesRejectsSharedAnonymousStructNamesOutsideMethods(t *testing.T) {
+	// Arrange
+	filesystem := newTypesTestFilesystem(t)
+	writeMemoryFile(filesystem, "/project/controllers/other.go", strings.Replace(testHandlerFile, "handleGetHello", "handlePostHello", 1), t)
+	typesService := NewTypesService(filesystem)
+
+	// Act
+	_, err := typesService.GenerateTypes()
+
+	// Assert
+	if err == nil || !strings.Contains(err.Error(), "HelloResponse") {
+		t.Fatalf("Expected the shared name to be reported, got %v", err)
+	}
+}
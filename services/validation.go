@@ -0,0 +1,306 @@
+package services
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// Go's keywords, which can't be used as identifiers
+var goKeywords = []string{
+	"break", "case", "chan", "const", "continue", "default", "defer", "else", "fallthrough", "for",
+	"func", "go", "goto", "if", "import", "interface", "map", "package", "range", "return", "select",
+	"struct", "switch", "type", "var",
+}
+
+// TypeScript's reserved and strict mode reserved words, which can't be used as identifiers
+var typescriptReservedWords = []string{
+	"break", "case", "catch", "class", "const", "continue", "debugger", "default", "delete", "do",
+	"else", "enum", "export", "extends", "false", "finally", "for", "function", "if", "import", "in",
+	"instanceof", "new", "null", "return", "super", "switch", "this", "throw", "true", "try",
+	"typeof", "var", "void", "while", "with", "implements", "interface", "let", "package",
+	"private", "protected", "public", "static", "yield", "await",
+}
+
+// Names npm refuses to publish packages under
+var npmBlacklistedNames = []string{"node_modules", "favicon.ico"}
+
+// The longest name npm allows for a package
+const NPM_MAX_NAME_LENGTH = 214
+
+// Digits spelt out, used when suggesting names for identifiers that start with a number
+var digitWords = map[rune]string{
+	'0': "zero", '1': "one", '2': "two", '3': "three", '4': "four",
+	'5': "five", '6': "six", '7': "seven", '8': "eight", '9': "nine",
+}
+
+// Components that can be nested in directories by giving them a name like admin/users
+var nestableComponents = []string{"controller", "service", "repository", "middleware", "model", "view", "page", "resource"}
+
+// Components generated into the frontend, where directories aren't Go packages
+var frontendComponents = []string{"model", "view", "page"}
+
+// Service for checking that project and component names will produce code that compiles, with each
+// error suggesting a valid name to use instead
+type ValidationService struct{}
+
+func NewValidationService() ValidationService {
+	return ValidationService{}
+}
+
+// Checks the project name can be used as a directory name and as the package.json name
+func (s ValidationService) ValidateProjectName(name string) error {
+	problem := ""
+
+	switch {
+	case name == "":
+		return fmt.Errorf("project name can't be empty")
+	case strings.ContainsAny(name, `/\`):
+		problem = "it can't contain a path separator"
+	case name == "." || name == "..":
+		problem = "it can't refer to the current or parent directory"
+	case len(name) > NPM_MAX_NAME_LENGTH:
+		problem = fmt.Sprintf("npm package names can't be longer than %v characters", NPM_MAX_NAME_LENGTH)
+	case strings.ToLower(name) != name:
+		problem = "npm package names can't contain capital letters"
+	case strings.ContainsRune(name, ' '):
+		problem = "npm package names can't contain spaces"
+	case strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_"):
+		problem = "npm package names can't start with a period or underscore"
+	case slices.Contains(npmBlacklistedNames, name):
+		problem = fmt.Sprintf("npm doesn't allow packages named %v", name)
+	case strings.IndexFunc(name, func(r rune) bool { return !isURLSafe(r) }) != -1:
+		problem = "npm package names can only contain lowercase letters, digits, '-', '.', '_' and '~'"
+	default:
+		return nil
+	}
+
+	return invalidNameError(name, "project name", problem, suggestProjectName(name))
+}
+
+// Checks the module path follows Go's rules for import paths
+func (s ValidationService) ValidateModulePath(path string) error {
+	if path == "" {
+		return fmt.Errorf("module path can't be empty")
+	}
+
+	for _, element := range strings.Split(path, "/") {
+		problem := ""
+
+		switch {
+		case element == "":
+			problem = "it can't contain empty path elements, i.e. a leading, trailing or double '/'"
+		case element == "." || element == "..":
+			problem = "it can't contain '.' or '..' path elements"
+		case strings.HasPrefix(element, ".") || strings.HasSuffix(element, "."):
+			problem = fmt.Sprintf("path element \"%v\" can't start or end with a period", element)
+		case strings.IndexFunc(element, func(r rune) bool { return !isURLSafe(unicode.ToLower(r)) }) != -1:
+			problem = fmt.Sprintf("path element \"%v\" can only contain letters, digits, '-', '.', '_' and '~'", element)
+		default:
+			continue
+		}
+
+		return invalidNameError(path, "module path", problem, suggestModulePath(path))
+	}
+
+	return nil
+}
+
+// Checks the name of a component is valid for the language and file the component is generated
+// into
+func (s ValidationService) ValidateComponentName(componentType, name string) error {
+	if name == "" {
+		return fmt.Errorf("%v name can't be empty", componentType)
+	}
+
+	description := fmt.Sprintf("%v name", componentType)
+
+	// Names like admin/users nest the component in directories, which become Go packages
+	segments := strings.Split(name, "/")
+	leaf := len(segments) - 1
+	if leaf > 0 {
+		if !slices.Contains(nestableComponents, componentType) {
+			return fmt.Errorf("\"%v\" is not a valid %v: %v components can't be nested in directories", name, description, componentType)
+		}
+		if segments[leaf] == "" {
+			return fmt.Errorf("\"%v\" is not a valid %v: the name can't end with '/'", name, description)
+		}
+
+		// Package names are the camel cased directory names, so can't be keywords
+		language, reserved := "Go", goKeywords
+		if slices.Contains(frontendComponents, componentType) {
+			language, reserved = "TypeScript", nil
+		}
+		for i := range leaf {
+			if err := validateComponentWords(segments, i, description, language, reserved, false); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Resources and tables pluralise their name for their list routes and queries, so it has to be
+	// singular
+	if componentType == "resource" || componentType == "table" {
+		if words := nameWords(segments[leaf]); len(words) != 0 && isPlural(words[len(words)-1]) {
+			words[len(words)-1] = singularise(words[len(words)-1])
+			suggestion := identifierFromWords(words).CamelName
+			if componentType == "table" {
+				suggestion = identifierFromWords(words).SnakeName
+			}
+			return invalidNameError(name, description, fmt.Sprintf("%v names are pluralised where needed, so have to be singular", componentType), replaceSegment(segments, leaf, suggestion))
+		}
+	}
+
+	switch componentType {
+	case "migration":
+		if strings.IndexFunc(name, func(r rune) bool { return !isLetterOrDigit(r) && r != '_' && r != '-' }) != -1 {
+			return invalidNameError(name, description, "migration names can only contain letters, digits, '_' and '-'", suggestSnakeCase(name))
+		}
+		return nil
+	case "table":
+		if strings.IndexFunc(name, func(r rune) bool { return r > unicode.MaxASCII }) != -1 {
+			return invalidNameError(name, description, "table names can only contain ASCII letters and digits", suggestSnakeCase(name))
+		}
+		return validateComponentWords(segments, leaf, description, "SQL", nil, false)
+	case "model", "view", "page":
+		// The name is only used capitalised, as in DefaultPage, which never clashes with a reserved word
+		return validateComponentWords(segments, leaf, description, "TypeScript", nil, false)
+	default:
+		words := nameWords(segments[leaf])
+		if len(words) > 1 && words[len(words)-1] == "test" {
+			return invalidNameError(name, description, "Go ignores files ending in _test outside of tests", replaceSegment(segments, leaf, identifierFromWords(words[:len(words)-1]).CamelName))
+		}
+
+		// Other components only use the name within identifiers like TypeController, but resources
+		// name variables with the bare camel cased name in both their Go and TypeScript code
+		if componentType != "resource" {
+			return validateComponentWords(segments, leaf, description, "Go", nil, false)
+		}
+		if err := validateComponentWords(segments, leaf, description, "Go", goKeywords, true); err != nil {
+			return err
+		}
+		return validateComponentWords(segments, leaf, description, "TypeScript", typescriptReservedWords, true)
+	}
+}
+
+// Splits a nested name into the directories it's nested in and its own name, i.e. admin and users
+// for admin/users
+func cutNamespace(name string) (namespace, leaf string, nested bool) {
+	i := strings.LastIndex(name, "/")
+	if i == -1 {
+		return "", name, false
+	}
+	return name[:i], name[i+1:], true
+}
+
+// Checks the segment at i of a nested name, which may be written in any casing, produces valid
+// identifiers. The words of the segment become type and variable names, so the first can't start
+// with a digit and the camel case form can't be one of the reserved words. The error quotes the
+// whole name, suggesting it with only the segment replaced, which stays singular if it has to be
+func validateComponentWords(segments []string, i int, description, language string, reserved []string, singular bool) error {
+	name, segment := strings.Join(segments, "/"), segments[i]
+	words := nameWords(segment)
+	problem := ""
+
+	switch {
+	case len(words) == 0:
+		return fmt.Errorf("\"%v\" is not a valid %v: names need at least one letter or digit", name, description)
+	case unicode.IsDigit([]rune(words[0])[0]):
+		problem = fmt.Sprintf("%v identifiers can't start with a digit", language)
+	case slices.Contains(reserved, newIdentifier(segment).CamelName):
+		problem = fmt.Sprintf("\"%v\" is a reserved word in %v", newIdentifier(segment).CamelName, language)
+	default:
+		return nil
+	}
+
+	return invalidNameError(name, description, problem, replaceSegment(segments, i, suggestIdentifier(segment, reserved, singular)))
+}
+
+// Joins the segments of a nested name back together, with the segment at i replaced
+func replaceSegment(segments []string, i int, segment string) string {
+	replaced := slices.Clone(segments)
+	replaced[i] = segment
+	return strings.Join(replaced, "/")
+}
+
+func invalidNameError(name, description, problem, suggestion string) error {
+	if suggestion == "" || suggestion == name {
+		return fmt.Errorf("\"%v\" is not a valid %v: %v", name, description, problem)
+	}
+
+	return fmt.Errorf("\"%v\" is not a valid %v: %v, try \"%v\"", name, description, problem, suggestion)
+}
+
+// Returns whether r can appear unescaped in a URL, going by the characters npm allows
+func isURLSafe(r rune) bool {
+	return (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || strings.ContainsRune("-._~", r)
+}
+
+func isLetterOrDigit(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// Splits the name into words on any character that isn't a letter or digit
+func splitWords(name string) []string {
+	return strings.FieldsFunc(name, func(r rune) bool { return !isLetterOrDigit(r) })
+}
+
+func suggestProjectName(name string) string {
+	words := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool { return !isURLSafe(r) || r == '.' || r == '_' })
+	return strings.Join(words, "-")
+}
+
+func suggestModulePath(path string) string {
+	elements := make([]string, 0)
+
+	for _, element := range strings.Split(path, "/") {
+		words := strings.FieldsFunc(element, func(r rune) bool { return !isURLSafe(unicode.ToLower(r)) })
+		element = strings.Trim(strings.Join(words, "-"), ".")
+		if element != "" {
+			elements = append(elements, element)
+		}
+	}
+
+	return strings.Join(elements, "/")
+}
+
+func suggestSnakeCase(name string) string {
+	return newIdentifier(name).SnakeName
+}
+
+// Suggests a camel case identifier made from the words in the name, spelling out a leading digit
+// and pluralising reserved words. Reserved words that have to stay singular are suffixed with item
+// instead, i.e. classItem
+func suggestIdentifier(name string, reserved []string, singular bool) string {
+	words := splitWords(name)
+	if len(words) == 0 {
+		return ""
+	}
+
+	if first := []rune(words[0])[0]; unicode.IsDigit(first) {
+		words[0] = strings.TrimPrefix(words[0], string(first))
+		words = slices.Insert(words, 0, digitWords[first])
+	}
+
+	suggestion := ""
+	for i, word := range words {
+		if word == "" {
+			continue
+		}
+		if i != 0 {
+			runes := []rune(word)
+			word = string(unicode.ToUpper(runes[0])) + string(runes[1:])
+		}
+		suggestion += word
+	}
+
+	if slices.Contains(reserved, suggestion) && singular {
+		return suggestion + "Item"
+	}
+	if slices.Contains(reserved, suggestion) {
+		return pluralise(suggestion)
+	}
+
+	return suggestion
+}
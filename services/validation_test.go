@@ -0,0 +1,224 @@
+package services
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateProjectNameAcceptsValidName(t *testing.T) {
+	// Arrange
+	service := NewValidationService()
+
+	// Act
+	err := service.ValidateProjectName("my-app")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+}
+
+func TestValidateProjectNameSuggestsNpmName(t *testing.T) {
+	// Arrange
+	service := NewValidationService()
+
+	// Act
+	err := service.ValidateProjectName("My App")
+
+	// Assert
+	if err == nil || !strings.Contains(err.Error(), `try "my-app"`) {
+		t.Fatalf("Expected \"my-app\" to be suggested, got %v", err)
+	}
+}
+
+func TestValidateModulePathRejectsSpaces(t *testing.T) {
+	// Arrange
+	service := NewValidationService()
+
+	// Act
+	err := service.ValidateModulePath("github.com/user/my app")
+
+	// Assert
+	if err == nil || !strings.Contains(err.Error(), `try "github.com/user/my-app"`) {
+		t.Fatalf("Expected \"github.com/user/my-app\" to be suggested, got %v", err)
+	}
+}
+
+func TestValidateModulePathAcceptsValidPath(t *testing.T) {
+	// Arrange
+	service := NewValidationService()
+
+	// Act
+	err := service.ValidateModulePath("github.com/User/my_app.v2")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+}
+
+func TestValidateComponentNameSpellsOutLeadingDigit(t *testing.T) {
+	// Arrange
+	service := NewValidationService()
+
+	// Act
+	err := service.ValidateComponentName("controller", "2fa")
+
+	// Assert
+	if err == nil || !strings.Contains(err.Error(), `try "twoFa"`) {
+		t.Fatalf("Expected \"twoFa\" to be suggested, got %v", err)
+	}
+}
+
+func TestValidateComponentNameRejectsReservedWords(t *testing.T) {
+	// Arrange
+	service := NewValidationService()
+
+	// Act
+	err := service.ValidateComponentName("service", "switch/users")
+
+	// Assert
+	if err == nil || !strings.Contains(err.Error(), `try "switches/users"`) {
+		t.Fatalf("Expected \"switches/users\" to be suggested, got %v", err)
+	}
+}
+
+func TestValidateComponentNameAcceptsReservedWordsThatArentBareIdentifiers(t *testing.T) {
+	// Arrange
+	service := NewValidationService()
+	inputs := []struct{ componentType, name string }{
+		{"controller", "type"},
+		{"service", "type"},
+		{"page", "default"},
+		{"view", "class"},
+		{"controller", "admin/type"},
+	}
+
+	for _, input := range inputs {
+		// Act
+		err := service.ValidateComponentName(input.componentType, input.name)
+
+		// Assert
+		if err != nil {
+			t.Fatalf("Expected %v \"%v\" to be accepted, got %v", input.componentType, input.name, err)
+		}
+	}
+}
+
+func TestValidateComponentNameKeepsNamespaceInSuggestion(t *testing.T) {
+	// Arrange
+	service := NewValidationService()
+	inputs := []struct{ componentType, name, expected string }{
+		{"controller", "admin/2fa", "admin/twoFa"},
+		{"service", "func/users", "funcs/users"},
+		{"resource", "admin/var", "admin/varItem"},
+		{"resource", "class", "classItem"},
+	}
+
+	for _, input := range inputs {
+		// Act
+		err := service.ValidateComponentName(input.componentType, input.name)
+
+		// Assert
+		if err == nil || !strings.Contains(err.Error(), `try "`+input.expected+`"`) {
+			t.Fatalf("Expected \"%v\" to be suggested, got %v", input.expected, err)
+		}
+	}
+}
+
+func TestValidateComponentNameRejectsTestSuffix(t *testing.T) {
+	// Arrange
+	service := NewValidationService()
+
+	// Act
+	err := service.ValidateComponentName("service", "vehicle_test")
+
+	// Assert
+	if err == nil || !strings.Contains(err.Error(), `try "vehicle"`) {
+		t.Fatalf("Expected \"vehicle\" to be suggested, got %v", err)
+	}
+}
+
+func TestValidateComponentNameAcceptsSnakeCaseMigration(t *testing.T) {
+	// Arrange
+	service := NewValidationService()
+
+	// Act
+	err := service.ValidateComponentName("migration", "create_books-table")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+}
+
+func TestValidateComponentNameAcceptsAnyCasing(t *testing.T) {
+	// Arrange
+	service := NewValidationService()
+
+	for _, name := range []string{"userProfile", "UserProfile", "user_profile", "user-profile"} {
+		// Act
+		err := service.ValidateComponentName("controller", name)
+
+		// Assert
+		if err != nil {
+			t.Fatalf("Expected no error for %v, got %v", name, err)
+		}
+	}
+}
+
+func TestValidateComponentNameAcceptsNestedNames(t *testing.T) {
+	// Arrange
+	service := NewValidationService()
+
+	// Act
+	err := service.ValidateComponentName("controller", "admin/reports/userProfile")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+}
+
+func TestValidateComponentNameRejectsInvalidNesting(t *testing.T) {
+	// Arrange
+	service := NewValidationService()
+	inputs := []struct{ componentType, name string }{
+		{"controller", "admin/"},
+		{"controller", "../users"},
+		{"controller", "admin//users"},
+		{"service", "func/users"},
+		{"migration", "admin/create_users"},
+		{"table", "admin/users"},
+	}
+
+	for _, input := range inputs {
+		// Act
+		err := service.ValidateComponentName(input.componentType, input.name)
+
+		// Assert
+		if err == nil {
+			t.Fatalf("Expected %v \"%v\" to be rejected", input.componentType, input.name)
+		}
+	}
+}
+
+func TestValidateComponentNameRejectsPluralResourcesAndTables(t *testing.T) {
+	// Arrange
+	service := NewValidationService()
+	inputs := []struct{ componentType, name, expected string }{
+		{"resource", "books", "book"},
+		{"resource", "admin/userCategories", "admin/userCategory"},
+		{"table", "BookAuthors", "book_author"},
+	}
+
+	for _, input := range inputs {
+		// Act
+		err := service.ValidateComponentName(input.componentType, input.name)
+
+		// Assert
+		if err == nil || !strings.Contains(err.Error(), `try "`+input.expected+`"`) {
+			t.Fatalf("Expected \"%v\" to be suggested for %v, got %v", input.expected, input.name, err)
+		}
+	}
+}